@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/NL-A/nla_framework/pgimport"
+	"github.com/serenize/snaker"
+)
+
+// nla import [-dir <path>] [-out <path>] [-prefix <folder>] [-tables a,b] <dump.sql>
+func cmdImport(args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	dir := fs.String("dir", "", "directory with project description (default: . or ./projectTemplate)")
+	out := fs.String("out", "", "directory for doc folders (default: project directory)")
+	prefix := fs.String("prefix", "", "parent folder for doc folders, e.g. 'docs' (DocType.PathPrefix)")
+	tables := fs.String("tables", "", "comma separated list of tables to import (default: all except framework tables)")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		log.Fatalf("usage: nla import [-dir <path>] [-out <path>] [-prefix <folder>] [-tables a,b] <dump.sql>")
+	}
+	data, err := ioutil.ReadFile(fs.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	schema, err := pgimport.Parse(string(data))
+	if err != nil {
+		log.Fatalf("%s: %s", fs.Arg(0), err)
+	}
+	only := []string{}
+	if len(*tables) > 0 {
+		for _, v := range strings.Split(*tables, ",") {
+			only = append(only, strings.TrimSpace(v))
+		}
+	}
+
+	// документы создаются go кодом. Для проекта в project.yaml нужно явно указать, куда положить go пакеты
+	root := *out
+	if len(root) == 0 {
+		pr, err := findProject(*dir)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(pr.specPath) > 0 {
+			log.Fatalf("%s is described in %s: import creates go packages, use -out <path>", pr.dir, pr.specPath)
+		}
+		root = pr.dir
+	}
+
+	docTables := schema.DocTables(only)
+	if len(docTables) == 0 {
+		log.Fatalf("%s: no tables to import", fs.Arg(0))
+	}
+	getDocs := []string{}
+	for _, t := range docTables {
+		docDir := filepath.Join(root, *prefix, snaker.SnakeToCamelLower(t.Name))
+		path := filepath.Join(docDir, "main.go")
+		if _, err = os.Stat(path); err == nil {
+			log.Fatalf("%s already exists", path)
+		}
+		src, err := pgimport.GenerateDoc(t, *prefix)
+		if err != nil {
+			log.Fatalf("table %s: %s", t.Name, err)
+		}
+		writeScaffoldFile(filepath.Join(docDir, "tmpl", ".gitkeep"), nil)
+		writeScaffoldFile(path, src)
+		fmt.Printf("created %s\n", path)
+		getDocs = append(getDocs, t.Package()+".GetDoc(&p)")
+	}
+	fmt.Printf("add to p.Docs (referenced docs go first):\n\t%s\n", strings.Join(getDocs, ",\n\t"))
+}
@@ -0,0 +1,75 @@
+// nla - утилита командной строки для генерации проекта
+//
+//	nla init [-name <name>] [dir]        создание описания нового проекта (projectTemplate/project.yaml)
+//	nla generate [-dir <path>] [-force]  генерация проекта
+//	nla validate [-dir <path>]           проверка описания проекта
+//	nla diff [-dir <path>]               что изменится при генерации, без записи на диск
+//	nla watch [-dir <path>] [-force]     генерация при изменении описания проекта и шаблонов. Перегенерируются только измененные документы
+//	nla doc add [-dir <path>] <name>     создание папки для нового документа с tmpl/
+//	nla import [-dir <path>] <dump.sql>  создание документов по дампу схемы postgres (pg_dump --schema-only)
+//
+// Описание проекта ищется в текущей директории или в ./projectTemplate: либо project.yaml (project.yml, project.json),
+// либо go пакет с main.go. В последнем случае утилита запускает 'go run .' и передает команду через переменную окружения NLA_COMMAND
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+)
+
+const usage = `usage: nla <command> [flags]
+
+commands:
+  init [-name <name>] [dir]        create new project description
+  generate [-dir <path>] [-force]  generate project
+  validate [-dir <path>]           validate project description
+  diff [-dir <path>]               show what generate would change
+  watch [-dir <path>] [-force]     regenerate changed docs on every change of project files
+  doc add [-dir <path>] <name>     create folder for new doc with tmpl/
+  import [-dir <path>] <dump.sql>  create docs from postgres schema dump (pg_dump --schema-only)
+`
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		fmt.Print(usage)
+		os.Exit(2)
+	}
+	cmd, args := os.Args[1], os.Args[2:]
+	switch cmd {
+	case "init":
+		cmdInit(args)
+	case "generate", "validate", "diff", "watch":
+		cmdProject(cmd, args)
+	case "doc":
+		if len(args) == 0 || args[0] != "add" {
+			log.Fatalf("usage: nla doc add [-dir <path>] <name>")
+		}
+		cmdDocAdd(args[1:])
+	case "import":
+		cmdImport(args)
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+	default:
+		fmt.Print(usage)
+		os.Exit(2)
+	}
+}
+
+// generate, validate, diff, watch
+func cmdProject(cmd string, args []string) {
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	dir := fs.String("dir", "", "directory with project description (default: . or ./projectTemplate)")
+	force := fs.Bool("force", false, "overwrite files edited manually")
+	fs.Parse(args)
+
+	pr, err := findProject(*dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err = pr.run(cmd, *force); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	nla "github.com/NL-A/nla_framework"
+	"github.com/NL-A/nla_framework/spec"
+	"github.com/NL-A/nla_framework/types"
+)
+
+type (
+	// найденное описание проекта
+	projectDesc struct {
+		dir      string // директория с описанием. Относительно нее считаются пути в описании и корень генерируемого проекта
+		specPath string // файл project.yaml/json. Если пустой, то проект описан в go коде
+	}
+)
+
+var specFilenames = []string{"project.yaml", "project.yml", "project.json"}
+
+// поиск описания проекта. Если директория не указана, то смотрим текущую директорию и ./projectTemplate
+func findProject(dir string) (projectDesc, error) {
+	candidates := []string{dir}
+	if len(dir) == 0 {
+		candidates = []string{".", "projectTemplate"}
+	}
+	for _, c := range candidates {
+		for _, name := range specFilenames {
+			if isFileExist(filepath.Join(c, name)) {
+				return projectDesc{dir: c, specPath: name}, nil
+			}
+		}
+		if isFileExist(filepath.Join(c, "main.go")) {
+			return projectDesc{dir: c}, nil
+		}
+	}
+	return projectDesc{}, fmt.Errorf("project description (%s or main.go) not found in %s", strings.Join(specFilenames, ", "), strings.Join(candidates, ", "))
+}
+
+func (pr projectDesc) run(cmd string, isForce bool) error {
+	if len(pr.specPath) == 0 {
+		return pr.runGo(cmd, isForce)
+	}
+	// корень генерируемого проекта по умолчанию '..' относительно директории с описанием, как и для проектов на go
+	if err := os.Chdir(pr.dir); err != nil {
+		return err
+	}
+	if cmd == nla.CommandWatch {
+		return nla.Watch(func() (types.ProjectType, error) {
+			p, err := spec.Load(pr.specPath)
+			p.Generator.IsForceOverwrite = isForce
+			return p, err
+		}, nil)
+	}
+	p, err := spec.Load(pr.specPath)
+	if err != nil {
+		return err
+	}
+	p.Generator.IsForceOverwrite = isForce
+	switch cmd {
+	case nla.CommandValidate:
+		if !nla.Validate(p) {
+			os.Exit(1)
+		}
+	case nla.CommandDiff:
+		nla.StartDryRun(p, nil)
+	default:
+		start := time.Now()
+		nla.Start(p, nil)
+		fmt.Printf("generated in %v\n", time.Since(start).Round(time.Millisecond))
+	}
+	return nil
+}
+
+// проект описан в go коде: запускаем его и передаем команду через переменную окружения (см nla_framework.Start)
+func (pr projectDesc) runGo(cmd string, isForce bool) error {
+	if cmd == nla.CommandWatch {
+		return pr.watchGo(isForce)
+	}
+	c := pr.command(cmd, isForce, "go", "run", ".")
+	start := time.Now()
+	err := c.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		// сообщение об ошибке уже выведено самим проектом
+		os.Exit(exitErr.ExitCode())
+	}
+	if err == nil && cmd == nla.CommandGenerate {
+		fmt.Printf("generated in %v\n", time.Since(start).Round(time.Millisecond))
+	}
+	return err
+}
+
+// watch для проекта, описанного в go коде. После каждого изменения файлов проект завершается с кодом WatchRestartExitCode,
+// и его надо перекомпилировать. 'go run' код выхода программы не передает, поэтому собираем и запускаем бинарник
+func (pr projectDesc) watchGo(isForce bool) error {
+	bin := filepath.Join(os.TempDir(), fmt.Sprintf("nla_watch_%v", os.Getpid()))
+	if runtime.GOOS == "windows" {
+		bin += ".exe"
+	}
+	defer os.Remove(bin)
+	for {
+		if err := pr.command("", false, "go", "build", "-o", bin, ".").Run(); err != nil {
+			// ошибка компиляции уже выведена - ждем исправления
+			if _, err = nla.WaitForChanges(pr.dir); err != nil {
+				return err
+			}
+			continue
+		}
+		err := pr.command(nla.CommandWatch, isForce, bin).Run()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == nla.WatchRestartExitCode {
+			continue
+		}
+		return err
+	}
+}
+
+func (pr projectDesc) command(cmd string, isForce bool, name string, args ...string) *exec.Cmd {
+	c := exec.Command(name, args...)
+	c.Dir = pr.dir
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	c.Env = os.Environ()
+	if len(cmd) > 0 {
+		c.Env = append(c.Env, nla.CommandEnv+"="+cmd)
+	}
+	if isForce {
+		c.Env = append(c.Env, nla.ForceOverwriteEnv+"=1")
+	}
+	return c
+}
+
+func isFileExist(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,313 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/NL-A/nla_framework/spec"
+	"github.com/serenize/snaker"
+	"gopkg.in/yaml.v3"
+)
+
+// шаблон описания нового проекта. Пользователи и авторизация - служебные таблицы фреймворка, поэтому в описании только настройки и пункт меню.
+// Схема для подсказок в редакторе лежит рядом с описанием - она соответствует версии фреймворка, которой создан проект
+const projectYamlTmpl = `# yaml-language-server: $schema=./schema.json
+name: [[.Name]]
+config:
+  vue:
+    quasarVersion: 2
+  postgres:
+    dbName: [[.Name]]
+    port: 5432
+    password: "[[.Name]]"
+    timeZone: Europe/Moscow
+  webServer:
+    port: 3081
+    url: http://localhost:3081
+  email:
+    sender: info@example.com
+    senderName: [[.Name]]
+    host: smtp.example.com
+    port: 465
+  user:
+    roles:
+      userList: [admin]
+      userUpdate: [admin]
+      userGetById: [admin]
+roles:
+  - name: admin
+    nameRu: администратор
+vue:
+  uiAppName: [[.Name]]
+  menu:
+    - url: users
+      text: пользователи
+      icon: image/users.svg
+      roles: [admin]
+docs: []
+`
+
+// шаблон документа для проекта, описанного в go коде
+const docGoTmpl = `package [[.Package]]
+
+import (
+	t "github.com/NL-A/nla_framework/types"
+)
+
+const (
+	name   = "[[.Name]]"
+	nameRu = "[[.NameRu]]"
+)
+
+func GetDoc(p *t.ProjectType) t.DocType {
+	doc := t.DocType{
+		Project:    p,
+		Name:       name,
+		NameRu:     nameRu,
+		PathPrefix: "[[.PathPrefix]]",
+		Flds: []t.FldType{
+			t.GetFldTitle(),
+		},
+		IsBaseTemplates: t.DocIsBaseTemplates{Vue: true, Sql: true},
+		Templates:       map[string]*t.DocTemplate{},
+	}
+	doc.Vue = t.DocVue{RouteName: name, MenuIcon: "image/file.svg", Roles: []string{}, I18n: map[string]string{"listTitle": nameRu}}
+	doc.Sql.FillBaseMethods(doc.Name)
+	doc.Init()
+	return doc
+}
+`
+
+type (
+	// описание нового документа в project.yaml. Порядок полей - как в файле
+	docScaffold struct {
+		Name       string              `yaml:"name" json:"name"`
+		NameRu     string              `yaml:"nameRu" json:"nameRu"`
+		PathPrefix string              `yaml:"pathPrefix,omitempty" json:"pathPrefix,omitempty"`
+		Vue        docVueScaffold      `yaml:"vue" json:"vue"`
+		Flds       []map[string]string `yaml:"flds" json:"flds"`
+	}
+	docVueScaffold struct {
+		RouteName string            `yaml:"routeName" json:"routeName"`
+		MenuIcon  string            `yaml:"menuIcon" json:"menuIcon"`
+		I18n      map[string]string `yaml:"i18n" json:"i18n"`
+	}
+)
+
+// nla init [-name <name>] [dir]
+func cmdInit(args []string) {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	name := fs.String("name", "", "project name (default: name of directory)")
+	fs.Parse(args)
+
+	dir := "."
+	if fs.NArg() > 0 {
+		dir = fs.Arg(0)
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(*name) == 0 {
+		*name = filepath.Base(absDir)
+	}
+	if strings.Contains(*name, " ") {
+		log.Fatalf("wrong project name: '%s'. Remove spaces.", *name)
+	}
+	path := filepath.Join(dir, "projectTemplate", "project.yaml")
+	if isFileExist(path) {
+		log.Fatalf("%s already exists", path)
+	}
+	data := executeScaffold(projectYamlTmpl, map[string]string{"Name": *name})
+	writeScaffoldFile(path, data)
+	schema, err := spec.Schema()
+	if err != nil {
+		log.Fatal(err)
+	}
+	schemaPath := filepath.Join(filepath.Dir(path), "schema.json")
+	writeScaffoldFile(schemaPath, append(schema, '\n'))
+	next := "nla doc add <name> && nla generate"
+	if dir != "." {
+		next = fmt.Sprintf("cd %s && %s", dir, next)
+	}
+	fmt.Printf("created %s, %s\nnext: %s\n", path, schemaPath, next)
+}
+
+// nla doc add [-dir <path>] [-nameRu <title>] [-prefix <folder>] <name>
+func cmdDocAdd(args []string) {
+	fs := flag.NewFlagSet("doc add", flag.ExitOnError)
+	dir := fs.String("dir", "", "directory with project description (default: . or ./projectTemplate)")
+	nameRu := fs.String("nameRu", "", "doc title (default: name)")
+	prefix := fs.String("prefix", "", "parent folder for doc folder, e.g. 'docs' (DocType.PathPrefix)")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		log.Fatalf("usage: nla doc add [-dir <path>] [-nameRu <title>] [-prefix <folder>] <name>")
+	}
+	name := fs.Arg(0)
+	if len(*nameRu) == 0 {
+		*nameRu = name
+	}
+
+	pr, err := findProject(*dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// папка документа: <prefix>/<nameCamel>/tmpl - там ищутся шаблоны документа (см ProjectType.FillDocTemplatesFields)
+	docDir := filepath.Join(pr.dir, *prefix, snaker.SnakeToCamelLower(name))
+	if _, err = os.Stat(docDir); err == nil {
+		log.Fatalf("%s already exists", docDir)
+	}
+	writeScaffoldFile(filepath.Join(docDir, "tmpl", ".gitkeep"), nil)
+
+	if len(pr.specPath) == 0 {
+		params := map[string]string{"Package": strings.ToLower(snaker.SnakeToCamelLower(name)), "Name": name, "NameRu": *nameRu, "PathPrefix": *prefix}
+		path := filepath.Join(docDir, "main.go")
+		writeScaffoldFile(path, executeScaffold(docGoTmpl, params))
+		fmt.Printf("created %s\nadd %s.GetDoc(&p) to p.Docs and {DocName: \"%s\"} to p.Vue.Menu\n", path, params["Package"], name)
+		return
+	}
+
+	doc := docScaffold{
+		Name:       name,
+		NameRu:     *nameRu,
+		PathPrefix: *prefix,
+		Vue:        docVueScaffold{RouteName: name, MenuIcon: "image/file.svg", I18n: map[string]string{"listTitle": *nameRu}},
+		Flds:       []map[string]string{{"type": "title"}},
+	}
+	specPath := filepath.Join(pr.dir, pr.specPath)
+	if strings.HasSuffix(specPath, ".json") {
+		err = addDocToJsonSpec(specPath, doc)
+	} else {
+		err = addDocToYamlSpec(specPath, doc)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("created %s, doc '%s' added to %s\n", docDir, name, specPath)
+}
+
+// добавление документа и пункта меню в yaml. Работаем с деревом yaml, чтобы сохранить комментарии и порядок полей
+func addDocToYamlSpec(path string, doc docScaffold) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var root yaml.Node
+	if err = yaml.Unmarshal(data, &root); err != nil {
+		return err
+	}
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return fmt.Errorf("%s: project description must be a mapping", path)
+	}
+	top := root.Content[0]
+
+	docs := yamlMapValue(top, "docs", yaml.SequenceNode)
+	for _, d := range docs.Content {
+		if n := yamlMapGet(d, "name"); n != nil && n.Value == doc.Name {
+			return fmt.Errorf("%s: doc '%s' already exists", path, doc.Name)
+		}
+	}
+	var docNode yaml.Node
+	if err = docNode.Encode(doc); err != nil {
+		return err
+	}
+	docs.Style = 0 // "docs: []" превращаем в обычный список
+	docs.Content = append(docs.Content, &docNode)
+
+	menu := yamlMapValue(yamlMapValue(top, "vue", yaml.MappingNode), "menu", yaml.SequenceNode)
+	var menuNode yaml.Node
+	if err = menuNode.Encode(map[string]string{"docName": doc.Name}); err != nil {
+		return err
+	}
+	menu.Content = append(menu.Content, &menuNode)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err = enc.Encode(&root); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// в json комментариев нет, поэтому просто перезаписываем файл. Ключи при этом сортируются
+func addDocToJsonSpec(path string, doc docScaffold) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var p map[string]interface{}
+	if err = json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	docs, _ := p["docs"].([]interface{})
+	for _, d := range docs {
+		if m, ok := d.(map[string]interface{}); ok && m["name"] == doc.Name {
+			return fmt.Errorf("%s: doc '%s' already exists", path, doc.Name)
+		}
+	}
+	p["docs"] = append(docs, doc)
+	vue, _ := p["vue"].(map[string]interface{})
+	if vue == nil {
+		vue = map[string]interface{}{}
+	}
+	menu, _ := vue["menu"].([]interface{})
+	vue["menu"] = append(menu, map[string]string{"docName": doc.Name})
+	p["vue"] = vue
+	data, err = json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+func yamlMapGet(m *yaml.Node, key string) *yaml.Node {
+	if m.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// значение по ключу. Если ключа нет (или значение пустое), то создается узел нужного типа
+func yamlMapValue(m *yaml.Node, key string, kind yaml.Kind) *yaml.Node {
+	if v := yamlMapGet(m, key); v != nil {
+		if v.Kind != kind {
+			v.Kind, v.Tag, v.Value, v.Content = kind, "", "", nil
+		}
+		return v
+	}
+	v := &yaml.Node{Kind: kind}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, v)
+	return v
+}
+
+func executeScaffold(tmplStr string, params map[string]string) []byte {
+	t := template.Must(template.New("").Delims("[[", "]]").Parse(tmplStr))
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, params); err != nil {
+		log.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func writeScaffoldFile(path string, data []byte) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
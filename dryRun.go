@@ -0,0 +1,105 @@
+package nla_framework
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/NL-A/nla_framework/types"
+	"github.com/NL-A/nla_framework/utils"
+)
+
+type (
+	// результат сравнения сгенерированного в памяти проекта с файлами на диске
+	DiffResult struct {
+		Added   []string // файлы, которых еще нет на диске
+		Changed []string // файлы, содержимое которых изменится
+		Removed []string // файлы, которые будут удалены при генерации
+		Patch   string   // unified diff по всем файлам
+	}
+)
+
+// StartDryRun генерация проекта без записи на диск. Печатает unified diff и списки добавленных/удаленных файлов
+func StartDryRun(p types.ProjectType, modifyFunc copyFileModifyFunc) {
+	fmt.Print(Diff(p, modifyFunc).String())
+}
+
+// Diff генерирует все файлы проекта в памяти и сравнивает их с текущим состоянием файлов на диске
+func Diff(p types.ProjectType, modifyFunc copyFileModifyFunc) (res DiffResult) {
+	utils.SetDryRun(true)
+	defer utils.SetDryRun(false)
+
+	res.Removed = generate(p, modifyFunc)
+
+	// сравниваем с тем, что уже лежит в output проекта (по умолчанию - на диске)
+	base := p.Generator.Output
+	if base == nil {
+		base = utils.DirOutput{}
+	}
+	files := utils.DryRunFiles()
+	names := []string{}
+	for k := range files {
+		names = append(names, k)
+	}
+	// если манифеста еще нет, то файлы в sql/model удаляются перед генерацией (см removeOldFiles). Все что не сгенерировано заново - будет удалено
+	if !utils.IsManifestExist() {
+		modelFiles, _ := base.ListFiles(project.DistPath + "/sql/model")
+		for _, path := range modelFiles {
+			path = filepath.ToSlash(filepath.Clean(path))
+			if _, ok := files[path]; !ok {
+				res.Removed = append(res.Removed, path)
+			}
+		}
+	}
+	names = append(names, res.Removed...)
+	sort.Strings(names)
+	sort.Strings(res.Removed)
+
+	var patch strings.Builder
+	for _, name := range names {
+		newData, isGenerated := files[name]
+		oldData, err := base.ReadFile(name)
+		isExist := err == nil
+		switch {
+		case isGenerated && !isExist:
+			res.Added = append(res.Added, name)
+			oldData = nil
+		case !isGenerated:
+			newData = nil
+		case utils.ByteSliceEqual(oldData, newData):
+			continue
+		default:
+			res.Changed = append(res.Changed, name)
+		}
+		patch.WriteString(utils.UnifiedDiff(name, oldData, newData))
+	}
+	res.Patch = patch.String()
+	return
+}
+
+func (r DiffResult) IsEmpty() bool {
+	return len(r.Added) == 0 && len(r.Changed) == 0 && len(r.Removed) == 0
+}
+
+// String вывод результата в виде: diff, затем списки файлов
+func (r DiffResult) String() string {
+	if r.IsEmpty() {
+		return "no changes\n"
+	}
+	var sb strings.Builder
+	sb.WriteString(r.Patch)
+	printList := func(title string, list []string) {
+		if len(list) == 0 {
+			return
+		}
+		sb.WriteString(fmt.Sprintf("\n%s (%v):\n", title, len(list)))
+		for _, v := range list {
+			sb.WriteString("\t" + v + "\n")
+		}
+	}
+	printList("added", r.Added)
+	printList("changed", r.Changed)
+	printList("removed", r.Removed)
+	return sb.String()
+}
@@ -0,0 +1,22 @@
+package nla_framework
+
+import (
+	"embed"
+	"os"
+
+	"github.com/NL-A/nla_framework/utils"
+)
+
+// файлы фреймворка встраиваются в бинарник, поэтому генератор работает и из read-only кэша модулей, и из vendor, и как скомпилированная программа
+//
+//go:embed all:templates all:sourceFiles all:webClient all:sourceFilesSQL_legacy
+var frameworkFS embed.FS
+
+func init() {
+	// для разработки самого фреймворка можно читать файлы напрямую с диска, без перекомпиляции
+	if dir := os.Getenv("NLA_FRAMEWORK_DIR"); len(dir) > 0 {
+		utils.SetFrameworkFS(os.DirFS(dir))
+		return
+	}
+	utils.SetFrameworkFS(frameworkFS)
+}
@@ -0,0 +1,173 @@
+package nla_framework
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/NL-A/nla_framework/types"
+	"github.com/NL-A/nla_framework/utils"
+)
+
+type (
+	// статистика генерации для режима watch
+	generateStat struct {
+		isFull  bool // перегенерирован весь проект
+		docs    []docStat
+		project time.Duration // файлы проекта
+		total   time.Duration
+	}
+	docStat struct {
+		name      string
+		tmplCount int
+		duration  time.Duration
+	}
+)
+
+// ключи хэшей в манифесте: "project" - все, кроме документов, и "doc:<имя документа>"
+const (
+	fingerprintProject   = "project"
+	fingerprintDocPrefix = "doc:"
+)
+
+// обратные ссылки и распарсенные шаблоны в хэш не попадают
+var fingerprintSkipTypes = []reflect.Type{reflect.TypeOf(&types.ProjectType{}), reflect.TypeOf(&types.DocType{}), reflect.TypeOf(&template.Template{})}
+
+// хэши описания проекта и каждого документа вместе с содержимым их шаблонов. Считаются после readData, до ParseTemplates
+func projectFingerprints(p types.ProjectType) map[string]string {
+	res := map[string]string{}
+
+	// роуты и меню собираются из документов и используются только в routes.js и sidemenu, которые перегенерируются всегда
+	pr := p
+	pr.Docs = nil
+	pr.Vue.Routes = nil
+	pr.Vue.Menu = nil
+	pr.Generator = types.GeneratorConfig{}
+	sources := []string{}
+	for _, path := range p.OverridePathForTemplates {
+		sources = append(sources, path)
+	}
+	for _, methods := range p.Sql.Methods {
+		for _, m := range methods {
+			sources = append(sources, m.Tmpl.Source)
+		}
+	}
+	// слоты документов попадают в копируемые файлы проекта, поэтому их изменение - полная перегенерация
+	docSlots := map[string]map[string][]string{}
+	for _, d := range p.Docs {
+		docSlots[d.Name] = d.Slots
+	}
+	res[fingerprintProject] = utils.Fingerprint(struct {
+		Project   types.ProjectType
+		DocSlots  map[string]map[string][]string
+		Sources   map[string]string
+		Framework string
+	}{pr, docSlots, sourcesFingerprint(sources), frameworkFingerprint()}, fingerprintSkipTypes...)
+
+	// хэш самого документа
+	own := map[string]string{}
+	for _, d := range p.Docs {
+		sources := []string{}
+		for _, t := range d.Templates {
+			sources = append(sources, t.Source)
+		}
+		for _, t := range d.TemplatePathOverride {
+			sources = append(sources, t.Source)
+		}
+		own[d.Name] = utils.Fingerprint(struct {
+			Doc     types.DocType
+			Sources map[string]string
+		}{d, sourcesFingerprint(sources)}, fingerprintSkipTypes...)
+	}
+	// в шаблонах документа используются поля документов, на которые он ссылается, и наоборот. Поэтому в хэш добавляем хэши связанных документов
+	linked := map[string]map[string]bool{}
+	for _, d := range p.Docs {
+		for _, fld := range d.Flds {
+			if _, ok := own[fld.Sql.Ref]; ok && fld.Sql.Ref != d.Name {
+				if linked[d.Name] == nil {
+					linked[d.Name] = map[string]bool{}
+				}
+				if linked[fld.Sql.Ref] == nil {
+					linked[fld.Sql.Ref] = map[string]bool{}
+				}
+				linked[d.Name][fld.Sql.Ref] = true
+				linked[fld.Sql.Ref][d.Name] = true
+			}
+		}
+	}
+	for name, h := range own {
+		hashes := []string{h}
+		for refName := range linked[name] {
+			hashes = append(hashes, own[refName])
+		}
+		sort.Strings(hashes[1:])
+		res[fingerprintDocPrefix+name] = utils.Fingerprint(hashes)
+	}
+	return res
+}
+
+// документы, которые изменились с прошлой генерации. nil - надо перегенерировать весь проект:
+// прошлой генерации не было, изменилось описание проекта или список документов
+func changedDocNames(prev, current map[string]string) map[string]bool {
+	if prev == nil || prev[fingerprintProject] != current[fingerprintProject] || len(prev) != len(current) {
+		return nil
+	}
+	res := map[string]bool{}
+	for k, v := range current {
+		prevV, ok := prev[k]
+		if !ok {
+			return nil
+		}
+		if prevV != v {
+			res[strings.TrimPrefix(k, fingerprintDocPrefix)] = true
+		}
+	}
+	return res
+}
+
+// хэши содержимого файлов шаблонов. Если файл не читается, то в хэш попадает ошибка - она будет выведена при парсинге шаблонов
+func sourcesFingerprint(paths []string) map[string]string {
+	res := map[string]string{}
+	for _, path := range paths {
+		if len(path) == 0 {
+			continue
+		}
+		data, err := utils.ReadSourceFile(path)
+		if err != nil {
+			res[path] = err.Error()
+			continue
+		}
+		h := sha256.Sum256(data)
+		res[path] = hex.EncodeToString(h[:])
+	}
+	return res
+}
+
+// хэш файлов фреймворка. Меняется при обновлении фреймворка или при правке файлов в NLA_FRAMEWORK_DIR
+func frameworkFingerprint() string {
+	files, err := utils.ListSourceFiles(utils.FrameworkPath(""))
+	utils.CheckErr(err, "frameworkFingerprint")
+	return utils.Fingerprint(sourcesFingerprint(files))
+}
+
+func (s generateStat) print() {
+	if !s.isFull && len(s.docs) == 0 {
+		fmt.Printf("no changes (%v)\n", s.total.Round(time.Millisecond))
+		return
+	}
+	mode := "incremental"
+	if s.isFull {
+		mode = "full"
+	}
+	fmt.Printf("%s generation:\n", mode)
+	for _, d := range s.docs {
+		fmt.Printf("  %-30s %3v templates %10v\n", d.name, d.tmplCount, d.duration.Round(time.Microsecond*100))
+	}
+	fmt.Printf("  %-30s %24v\n", "project files", s.project.Round(time.Microsecond*100))
+	fmt.Printf("  %-30s %24v\n", "total", s.total.Round(time.Millisecond))
+}
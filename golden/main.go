@@ -0,0 +1,163 @@
+// golden - регрессионная проверка генератора. Эталонный проект (см project.go) генерируется во временную директорию
+// и сравнивается с файлами в testdata/expected. Файлы фреймворка, которые копируются без изменений, в эталон не попадают.
+//
+//	go run ./golden           проверка. При расхождениях печатает список файлов и выходит с кодом 1
+//	go run ./golden -update   перезапись эталона после намеренного изменения шаблонов
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	nla "github.com/NL-A/nla_framework"
+	"github.com/NL-A/nla_framework/utils"
+)
+
+func main() {
+	log.SetFlags(0)
+	update := flag.Bool("update", false, "overwrite golden files with generated output")
+	flag.Parse()
+
+	expectedDir := filepath.Join(harnessDir(), "testdata", "expected")
+	tmpDir, err := ioutil.TempDir("", "nla_golden")
+	utils.CheckErr(err, "TempDir")
+	defer os.RemoveAll(tmpDir)
+
+	// генерируем всегда полностью: команда и форс из окружения утилиты nla не должны влиять на результат
+	_ = os.Unsetenv(nla.CommandEnv)
+	_ = os.Unsetenv(nla.ForceOverwriteEnv)
+	nla.Start(referenceProject(tmpDir), nil)
+
+	actual, err := readTree(tmpDir)
+	utils.CheckErr(err, "read generated files")
+	if *update {
+		utils.CheckErr(os.RemoveAll(expectedDir), "remove golden files")
+		for _, name := range sortedNames(actual) {
+			path := filepath.Join(expectedDir, filepath.FromSlash(name))
+			utils.CheckErr(os.MkdirAll(filepath.Dir(path), 0755), "MkdirAll")
+			utils.CheckErr(ioutil.WriteFile(path, actual[name], 0644), "write golden file")
+		}
+		fmt.Printf("golden files updated: %v files in %s\n", len(actual), expectedDir)
+		return
+	}
+
+	expected, err := readTree(expectedDir)
+	utils.CheckErr(err, "read golden files (run with -update to create them)")
+	if diff := compareTrees(expected, actual); len(diff) > 0 {
+		for _, s := range diff {
+			fmt.Println(s)
+		}
+		fmt.Printf("FAIL: %v files differ from golden. If the change is intended, run: go run ./golden -update\n", len(diff))
+		os.Exit(1)
+	}
+	fmt.Printf("ok: %v files match golden\n", len(expected))
+}
+
+// директория с исходниками harness - эталон лежит рядом с ними
+func harnessDir() string {
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		log.Fatal("golden: can't detect source directory")
+	}
+	return filepath.Dir(file)
+}
+
+// сгенерированные файлы (путь через / - содержимое). Манифест не сравнивается - в нем хэш файлов фреймворка.
+// Файлы, совпадающие с исходником фреймворка по тому же относительному пути, пропускаются
+func readTree(root string) (map[string][]byte, error) {
+	res := map[string][]byte{}
+	err := filepath.WalkDir(root, func(path string, e fs.DirEntry, err error) error {
+		if err != nil || e.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == utils.ManifestFilename {
+			return nil
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if isFrameworkCopy(rel, data) {
+			return nil
+		}
+		res[rel] = data
+		return nil
+	})
+	return res, err
+}
+
+// файл скопирован из sourceFiles или webClient без изменений
+func isFrameworkCopy(rel string, data []byte) bool {
+	candidates := []string{"sourceFiles/" + rel}
+	if strings.HasPrefix(rel, "src/") {
+		candidates = append(candidates, "webClient/quasar_2/"+strings.TrimPrefix(rel, "src/"))
+	}
+	for _, c := range candidates {
+		src, err := utils.ReadSourceFile(utils.FrameworkPath(c))
+		if err == nil && bytes.Equal(src, data) {
+			return true
+		}
+	}
+	return false
+}
+
+func compareTrees(expected, actual map[string][]byte) []string {
+	res := []string{}
+	for _, name := range sortedNames(expected) {
+		data, ok := actual[name]
+		if !ok {
+			res = append(res, "missing: "+name)
+			continue
+		}
+		if !bytes.Equal(data, expected[name]) {
+			res = append(res, fmt.Sprintf("changed: %s (%s)", name, firstDiffLine(expected[name], data)))
+		}
+	}
+	for _, name := range sortedNames(actual) {
+		if _, ok := expected[name]; !ok {
+			res = append(res, "added:   "+name)
+		}
+	}
+	return res
+}
+
+// первая отличающаяся строка - чтобы по выводу было понятно, что поменялось
+func firstDiffLine(a, b []byte) string {
+	la, lb := strings.Split(string(a), "\n"), strings.Split(string(b), "\n")
+	for i := 0; i < len(la) || i < len(lb); i++ {
+		var sa, sb string
+		if i < len(la) {
+			sa = la[i]
+		}
+		if i < len(lb) {
+			sb = lb[i]
+		}
+		if sa != sb {
+			return fmt.Sprintf("line %v: %q -> %q", i+1, strings.TrimSpace(sa), strings.TrimSpace(sb))
+		}
+	}
+	return "line endings"
+}
+
+func sortedNames(m map[string][]byte) []string {
+	res := make([]string, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
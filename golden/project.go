@@ -0,0 +1,122 @@
+package main
+
+import (
+	t "github.com/NL-A/nla_framework/types"
+)
+
+// эталонный проект: все типы полей, рекурсия, табы, тэги, машина состояний и интеграции (bitrix, odata, telegram)
+func referenceProject(outputRoot string) t.ProjectType {
+	p := t.ProjectType{Name: "golden"}
+	p.Config.LocalProjectPath = "golden/src"
+	p.Config.Vue.QuasarVersion = 2
+	p.Config.Email = t.EmailConfig{Sender: "robot@golden.ru", Host: "smtp.golden.ru", Port: 465}
+	p.Config.Postgres = t.PostrgesConfig{DbName: "golden", Port: 5432, Password: "password"}
+	p.Config.WebServer = t.WebServerConfig{Port: 3081, Url: "golden.ru"}
+	p.Config.Telegram = t.TelegramConfig{BotName: "goldenBot", Token: "123:token"}
+	p.Config.Bitrix = t.BitrixConfig{ApiUrl: "https://golden.bitrix24.ru/rest"}
+	p.Config.Odata = t.OdataConfig{Url: "https://1c.golden.ru/odata"}
+	p.Generator.OutputRoot = outputRoot
+	p.Roles = []t.ProjectRole{{Name: "manager", NameRu: "менеджер"}}
+
+	// справочник с рекурсией и импортом из Битрикс
+	city := t.DocType{Project: &p, Name: "city", NameRu: "город", IsBaseTemplates: t.DocIsBaseTemplates{Vue: true, Sql: true}}
+	city.Vue = t.DocVue{RouteName: "city", MenuIcon: "image/city.svg", BreadcrumbIcon: "location_city", Roles: []string{}, I18n: map[string]string{"listTitle": "города"}}
+	city.Flds = []t.FldType{t.GetFldTitle().SetBitrixInfo(t.BitrixFld{Name: "NAME"})}
+	city.SetIsRecursion("группы")
+	city.Integrations.Bitrix = t.DocIntegrationsBitrix{UrlName: "crm.city.list", IsDebugMode: true}
+	city.Sql.FillBaseMethods(city.Name)
+	city.Init()
+
+	// документ со всеми типами полей, табами и синхронизацией с 1С
+	client := t.DocType{Project: &p, Name: "client", NameRu: "клиент", IsBaseTemplates: t.DocIsBaseTemplates{Vue: true, Sql: true}, IsTaskAllowed: true}
+	client.Vue = t.DocVue{RouteName: "client", MenuIcon: "image/client.svg", Roles: []string{}, I18n: map[string]string{"listTitle": "клиенты"}}
+	client.Vue.Vars = map[string]map[string]string{"docItem": {"isLoading": "false", "activeTab": "'info'", "counter": "0"}}
+	client.Vue.Methods = map[string]map[string]string{"docItem": {"refresh()": "this.counter++", "clear()": "this.counter = 0", "alert(v)": "console.log(v)"}}
+	client.Vue.GloablI18n = map[string]map[string]string{"client_level": {"high": "высокий", "low": "низкий", "middle": "средний"}}
+	client.Flds = []t.FldType{
+		t.GetFldTitle(),
+		t.GetFldString("inn", "ИНН", 12, [][]int{{2, 1}}).SetSearchWeight("C").SetOdataInfo(t.OdataFld{Name: "INN"}),
+		t.GetFldString("note", "примечание", 0, [][]int{{2, 2}}).SetReadRoles("manager"),
+		t.GetFldRef("city_id", "город", "city", [][]int{{3, 1}}, "isShowLink"),
+		t.GetFldSelectString("status", "статус", 20, [][]int{{3, 2}}, []t.FldVueOptionsItem{{Label: "новый", Value: "new"}, {Label: "старый", Value: "old", Color: "red"}}, "", "isClearable"),
+		t.GetFldSelectMultiple("channels", "каналы", [][]int{{4, 1}}, []t.FldVueOptionsItem{{Label: "email", Value: "email"}, {Label: "телефон", Value: "phone"}}),
+		t.GetFldRadioString("kind", "вид", [][]int{{4, 2}}, []t.FldVueOptionsItem{{Label: "юр. лицо", Value: "legal"}, {Label: "физ. лицо", Value: "person"}}),
+		t.GetFldDate("birth_date", "дата рождения", [][]int{{5, 1}}),
+		t.GetFldDateTime("last_visit", "последний визит", [][]int{{5, 2}}),
+		t.GetFldCheckbox("is_vip", "vip", [][]int{{6, 1}}),
+		t.GetFldPhone("phone", "телефон", [][]int{{6, 2}}),
+		t.GetFldEmail("email", "email", [][]int{{7, 1}}),
+		t.GetFldInt("cnt", "количество", [][]int{{7, 2}}).SetWriteRoles("admin"),
+		t.GetFldInt64("external_id", "внешний id", [][]int{{8, 1}}),
+		t.GetFldDouble("amount", "сумма", [][]int{{8, 2}}),
+		t.GetFldUuid("guid", "guid", [][]int{{9, 1}}),
+		t.GetFldTag("tags", "тэги", [][]int{{9, 2}}),
+		t.GetFldDadataAddress("address", "адрес", [][]int{{10, 1}}),
+		t.GetFldJsonList("contacts", "контакты", [][]int{{11, 1}}, t.FldVueJsonList{Icon: "contacts", Flds: []t.FldType{
+			t.GetFldString("name", "имя", 100, [][]int{{1, 1}}),
+			t.GetFldPhone("phone", "телефон", [][]int{{1, 2}}),
+		}}),
+		t.GetFldFiles("docs", "документы", [][]int{{12, 1}}, t.FldVueFilesParams{Accept: ".pdf", MaxFileSize: 5000000}),
+		t.GetFldImg("avatar", "аватар", [][]int{{12, 2}}, t.FldVueImgParams{Accept: "image/*", Crop: "300x300", Width: 300}),
+		t.GetFldImgList("photos", "фото", [][]int{{13, 1}}, t.FldVueImgParams{Accept: "image/*", CanAddUrls: true}),
+		t.GetFldJsonbComposition("extra", "дополнительно", [][]int{{14, 1}}, "", "comp-client-extra"),
+		t.GetFldSimpleHtml([][]int{{15, 1}}, "", "<div class='text-caption'>golden</div>"),
+		t.GetFldVueCompositionRefList(&client, t.VueCompRefListWidgetParams{Label: "сделки", FldName: "deal", TableName: "deal", RefFldName: "client_id", Avatar: "image/deal.svg"}, [][]int{{16, 1}}),
+		t.GetFldLinkListWidget("client_user_link", [][]int{{16, 2}}, "", map[string]interface{}{"listTitle": "менеджеры"}),
+	}
+	client.Vue.FilterList = []t.VueDocListFilter{
+		{Label: "город", FldName: "city_id", IsRef: true, RefTable: "city"},
+		{Label: "дата рождения", FldName: "birth_date", Kind: t.VueDocListFilterKindDateRange},
+		{Label: "сумма", FldName: "amount", Kind: t.VueDocListFilterKindNumberRange},
+		{Label: "статус", FldName: "status", Kind: t.VueDocListFilterKindIn},
+		{Label: "вид", FldName: "kind", Kind: t.VueDocListFilterKindNotIn, Options: []t.FldVueOptionsItem{{Label: "юр. лицо", Value: "legal"}, {Label: "физ. лицо", Value: "person"}}},
+		{Label: "телефон", FldName: "phone", Kind: t.VueDocListFilterKindIsNull},
+		{Label: "города", FldName: "city_id", Kind: t.VueDocListFilterKindRefMulti, RefTable: "city"},
+		{Label: "тэги", FldName: "tags", Kind: t.VueDocListFilterKindArrayContains},
+	}
+	client.Vue.SortList = []t.VueDocListSort{{Label: "статус и дата", Value: "status, created_at"}, {Label: "название", Value: "title"}}
+	client.Vue.Tabs = []t.VueTab{{Title: "info", TitleRu: "инфо", TmplName: "tabInfo.vue", Icon: "assignment"}}
+	client.Integrations.Odata = t.DocIntegrationsOdata{Name: "Catalog_Clients", IsDebugMode: true}
+	client.Sql.FillBaseMethods(client.Name, "manager")
+	client.Sql.IsSearchText = true
+	client.Sql.IsHistory = true
+	client.Sql.IsFullTextSearch = true
+	client.Sql.AccessRules = map[string]t.DocSqlAccessRule{"manager": {Department: &t.DocSqlAccessDepartment{Fld: "id", LinkTable: "client_user_link", LinkFld: "client_id", UserFld: "manager_id"}}}
+	client.Init()
+
+	// уникальная связь многие-к-многим клиентов и пользователей
+	clientUserLink := t.DocType{Project: &p, Name: "client_user_link", NameRu: "менеджеры клиента", IsBaseTemplates: t.DocIsBaseTemplates{Sql: true}}
+	clientUserLink.Flds = []t.FldType{
+		t.GetFldRef("client_id", "клиент", "client", [][]int{{1, 1}}).SetIsRequired(),
+		t.GetFldRef("manager_id", "менеджер", "user", [][]int{{1, 2}}).SetIsRequired(),
+	}
+	clientUserLink.Sql.IsUniqLink = true
+	clientUserLink.Sql.FillBaseMethods(clientUserLink.Name, "manager")
+	clientUserLink.Init()
+
+	// документ с машиной состояний
+	deal := t.DocType{Project: &p, Name: "deal", NameRu: "сделка", IsBaseTemplates: t.DocIsBaseTemplates{Vue: true, Sql: true}}
+	deal.Vue = t.DocVue{RouteName: "deal", MenuIcon: "image/deal.svg", Roles: []string{}, I18n: map[string]string{"listTitle": "сделки"}}
+	deal.Flds = []t.FldType{
+		t.GetFldTitle(),
+		t.GetFldRef("client_id", "клиент", "client", [][]int{{2, 1}}).SetReadRoles("manager"),
+		t.GetFldString("state", "статус", 50, [][]int{{2, 2}}).SetDefault("'draft'"),
+		t.GetFldDouble("sum", "сумма", [][]int{{3, 1}}),
+	}
+	deal.StateMachine = &t.DocSm{States: []*t.DocSmState{
+		{Title: "draft", TitleRu: "черновик", Actions: []t.DocSmAction{{To: "in_work", Label: "в работу"}, {To: "canceled", Label: "отменить"}}},
+		{Title: "in_work", TitleRu: "в работе", Actions: []t.DocSmAction{{To: "done", Label: "завершить", UpdateFlds: []t.FldType{t.GetFldDouble("sum", "сумма", [][]int{{1, 1}})}, Conditions: []t.DocSmActionCondition{{SqlText: "if (params->>'sum')::double precision <= 0 then\n\treturn json_build_object('ok', false, 'message', 'sum is empty');\nend if;"}}}}},
+		{Title: "done", TitleRu: "завершено", IsFinal: true},
+		{Title: "canceled", TitleRu: "отменено", IsFinal: true},
+	}}
+	deal.Sql.FillBaseMethods(deal.Name, "manager")
+	deal.Sql.AccessRules = map[string]t.DocSqlAccessRule{"manager": {Department: &t.DocSqlAccessDepartment{Fld: "client_id", LinkTable: "client_user_link", UserFld: "manager_id"}}}
+	deal.Init()
+	deal.StateMachine.GenerateTmpls(&deal, map[string]interface{}{"cardTmplPath": "nla_framework:/templates/webClient/quasar_1/doc/comp/stateMachine/cardTmpl.vue", "actionBtnPath": "nla_framework:/templates/webClient/quasar_1/doc/comp/stateMachine/actionBtn.vue"})
+
+	p.Docs = []t.DocType{city, client, clientUserLink, deal}
+	p.Vue.Menu = []t.VueMenu{{DocName: "client"}, {DocName: "deal"}, {IsFolder: true, Text: "справочники", Icon: "image/folder.svg", LinkList: []t.VueMenu{{DocName: "city"}}}}
+	p.FillSideMenu()
+	p.FillVueBaseRoutes()
+	return p
+}
@@ -0,0 +1,174 @@
+package bitrix
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"golden/src/pg"
+	"golden/src/utils"
+	"net/http"
+	"time"
+)
+
+type (
+	CityFromBtx struct {
+		Title interface{} `json:"NAME"`
+	}
+
+	City struct {
+		Id    int    `json:"id"`
+		Title string `json:"title"`
+	}
+)
+
+func GetCityHistory(c *gin.Context) {
+	utils.HttpSuccess(c, "ok")
+
+	var err error
+
+	userId, _ := utils.ExtractUserIdString(c)
+
+	go func() {
+		nextId := 0
+		lastProcessedId := 0
+		for {
+			lastId := 0
+			fmt.Printf("getAllCityHistoryAndSave nextId: %v\n", nextId)
+			nextId, lastId, err = getAllCityHistoryAndSave(nextId, nil)
+			if err != nil {
+				fmt.Printf("getAllCityHistoryAndSave err %s\n", err)
+				return
+			}
+
+			// прерываем процесс когда id'шники пошли на второй круг. Определяем это по тому что новый lastId меньше последнего обработанного id'шника
+			if lastProcessedId > 0 && lastId < lastProcessedId {
+				fmt.Printf("getAllCityHistoryAndSave finished")
+				saveResultMsgToPg(userId, "City импортированы из Битрикс")
+				return
+			}
+			lastProcessedId = lastId
+			time.Sleep(100 * time.Millisecond)
+
+		}
+	}()
+}
+
+func getAllCityHistoryAndSave(startId int, errResultArr *[]errResult) (nextId, lastId int, err error) {
+
+	res := struct {
+		Result           []CityFromBtx `json:"result"`
+		Error            interface{}   `json:"error"`
+		ErrorDescription string        `json:"error_description"`
+	}{}
+	// https://crm.tian-trade.ru/rest/11161/cbwiqxom770hdgpm/crm.company.list.json?select[]=title&select[]=lead_id
+	// https://crm.tian-trade.ru/rest/11161/cbwiqxom770hdgpm/crm.company.list.json?Filter[UF_CRM_1535355557]=12431
+	selectFlds := []string{"NAME"}
+	url := fmt.Sprintf("%s/rest/%s/%s/crm.city.list?&start=%v&order[id]=asc", bitrixConfig.ApiUrl, bitrixConfig.UserId, bitrixConfig.WebhookToken, startId)
+	for _, fld := range selectFlds {
+		url = fmt.Sprintf("%s&select[]=%s", url, fld)
+	}
+	err = utils.GetJsonByUrl(url, &res)
+	if err != nil {
+		return
+	}
+
+	if len(res.ErrorDescription) > 0 {
+		return 0, 0, errors.New(fmt.Sprintf("error: %s", res.ErrorDescription))
+	}
+
+	//fmt.Printf("result length: %v\n", len(res.Result))
+	if len(res.Result) == 0 {
+		return 0, 0, nil
+	} else {
+		nextId = startId + 50
+	}
+
+	for _, v := range res.Result {
+		//fmt.Printf("process %s %s %s %s\n", v.ID, v.TITLE, v.PHONE, v.EMAIL)
+		doc, err := v.ConvertFromBitrix()
+		if err != nil {
+			fmt.Printf("ConvertFromBitrix err %s %s\n", err, v)
+			if errResultArr != nil {
+				*errResultArr = append(*errResultArr, errResult{
+					JsonParams: doc,
+					Message:    fmt.Sprintf("ConvertFromBitrix error: %s\n", err),
+				})
+			}
+			continue
+		}
+		lastId = cast.ToInt(v.BtxId)
+		if lastId == 0 {
+			fmt.Printf("cast.ToInt err %s %s\n", err, v)
+			continue
+		}
+
+		jsonData, _ := json.Marshal(doc)
+		err = pg.CallPgFunc("city_update", jsonData, doc, nil)
+		if err != nil {
+			fmt.Printf("city_update error: %s %s\n", err, jsonData)
+			if errResultArr != nil {
+				*errResultArr = append(*errResultArr, errResult{
+					JsonParams: doc,
+					Message:    fmt.Sprintf("city_update error: %s\n", err),
+				})
+			}
+			continue
+		}
+	}
+	return
+}
+
+func (btxDoc *CityFromBtx) ConvertFromBitrix() (res *City, err error) {
+	if btxDoc == nil {
+		return nil, errors.New("CityFromBtx is nil in CityFromBtx.ConvertFromBitrix")
+	}
+	res = &City{}
+
+	res.Title = cast.ToString(btxDoc.Title)
+
+	return res, nil
+}
+
+func GetCityHistoryDebug(c *gin.Context) {
+
+	var err error
+
+	nextId := 0
+	lastProcessedId := 0
+	errResultArr := []errResult{}
+	for {
+		lastId := 0
+		fmt.Printf("getAllCityHistoryAndSave nextId: %v\n", nextId)
+		nextId, lastId, err = getAllCityHistoryAndSave(nextId, &errResultArr)
+		if err != nil {
+			fmt.Printf("getAllCityHistoryAndSave err %s\n", err)
+			break
+		}
+
+		if nextId > 100 {
+			break
+		}
+
+		// прерываем процесс когда id'шники пошли на второй круг. Определяем это по тому что новый lastId меньше последнего обработанного id'шника
+		if lastProcessedId > 0 && lastId < lastProcessedId {
+			fmt.Printf("getAllCityHistoryAndSave finished")
+			break
+		}
+		lastProcessedId = lastId
+		time.Sleep(100 * time.Millisecond)
+
+	}
+	if err != nil {
+		utils.HttpError(c, http.StatusBadRequest, err.Error())
+	} else if len(errResultArr) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"ok":      false,
+			"message": errResultArr,
+		})
+	} else {
+		utils.HttpSuccess(c, fmt.Sprintf("succesfully imported: %v", nextId))
+	}
+
+}
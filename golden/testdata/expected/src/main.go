@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/gob"
+	"flag"
+
+	"golden/src/bitrix"
+	"golden/src/jobs"
+	"golden/src/odata"
+	"golden/src/pg"
+	"golden/src/sse"
+	"golden/src/tgBot"
+	"golden/src/types"
+	"golden/src/utils"
+	"golden/src/webServer"
+	"math/rand"
+	"os"
+	"time"
+)
+
+var (
+	config *types.Config
+	err    error
+)
+
+func main() {
+
+	// считываем флаг dev. Если режим разработки, то меняем глобальные переменные
+	isDev := flag.Bool("dev", false, "a bool")
+	pgPort := flag.String("pg_port", "", "an string")
+	pgPassword := flag.String("pg_pass", "", "an string")
+	dbName := flag.String("dbname", "", "an string")
+	// версии миграций с потерей данных, которые разрешено применить. Например, -approve_migrations 0003,0004
+	approveMigrations := flag.String("approve_migrations", "", "an string")
+	tgBotName := flag.String("telegram_bot_name", "", "an string")
+	tgBotToken := flag.String("telegram_bot_token", "", "an string")
+	flag.Parse()
+
+	if *isDev {
+		_ = os.Setenv("PG_PORT", "5438")
+		if len(*pgPort) > 0 {
+			_ = os.Setenv("PG_PORT", *pgPort)
+		}
+		if len(*pgPassword) > 0 {
+			_ = os.Setenv("PG_PASSWORD", *pgPassword)
+		}
+		_ = os.Setenv("PG_HOST", "localhost")
+		if len(*dbName) > 0 {
+			_ = os.Setenv("PG_DBNAME", *dbName)
+		}
+		_ = os.Setenv("IS_DEVELOPMENT", "true")
+	}
+
+	if len(*approveMigrations) > 0 {
+		_ = os.Setenv("APPROVE_MIGRATIONS", *approveMigrations)
+	}
+
+	if *isDev {
+		if len(*tgBotName) > 0 {
+			_ = os.Setenv("TELEGRAM_BOT_NAME", *tgBotName)
+		} else {
+			utils.Panic("Write 'telegram_bot_name' and 'telegram_bot_token' in go parameters for developmeent mode")
+		}
+		if len(*tgBotToken) > 0 {
+			_ = os.Setenv("TELEGRAM_BOT_TOKEN", *tgBotToken)
+		}
+	}
+	// read config.toml
+	config, err = types.ReadConfigFile("./config.toml")
+	utils.CheckErr(err, "Read config")
+
+	if os.Getenv("IS_DEVELOPMENT") != "true" {
+		time.Sleep(5 * time.Second)
+	}
+
+	// postgres
+	err = pg.StartPostgres(config.Postgres)
+	utils.CheckErr(err, "StartPostgres")
+
+	// инициализируем генератор случайных чисел
+	rand.Seed(time.Now().UnixNano())
+	//
+	gob.Register(map[string]interface{}{})
+	//
+	jobs.StartJobs()
+
+	// передаем часть конфига в utils
+	utils.SetWebServerConfig(config.WebServer)
+	utils.SetEmailConfig(config.Email)
+
+	//go pg.GenerateFakeUsers(100)
+
+	// инициализируем брокера для обработки подключений по SSE
+	sse.Init()
+
+	bitrix.SetBitrixConfig(config.Bitrix)
+	odata.SetOdataConfig(config.Odata)
+	go tgBot.Start(*config)
+
+	webServer.StartWebServer(*config)
+}
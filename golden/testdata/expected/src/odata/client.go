@@ -0,0 +1,137 @@
+package odata
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"golden/src/pg"
+	"golden/src/utils"
+	"time"
+)
+
+type (
+	ClientType struct {
+		Inn string `json:"INN" xml:"INN"`
+	}
+
+	ClientForPgType struct {
+		Id  int    `json:"id"`
+		Inn string `json:"inn"`
+	}
+)
+
+func StartClientSync(c *gin.Context) {
+	go func() {
+		resMsg := syncClientWith1C()
+		// message с результатами
+		userId, _ := utils.ExtractUserIdString(c)
+		err := saveResultMsgToPg(userId, "Синхронизация с 1С: клиент", resMsg)
+		if err != nil {
+			fmt.Printf("StartClientSync saveResultMsgToPg error: %s\n", err)
+		}
+	}()
+	utils.HttpSuccess(c, "ok")
+}
+
+func syncClientWith1C() []resultMsgType {
+	start := time.Now()
+	resMsg := newResultMsgType("Синхронизация: клиент")
+	resList, err := getClient()
+	if err != nil {
+		fmt.Printf("syncClientWith1C getClient error: %s\n", err)
+		resMsg.addErr(err.Error())
+		return []resultMsgType{resMsg}
+	}
+	cnt := 0 //счетчик записей
+	for _, v := range resList {
+		jsonStr, _ := json.Marshal(v)
+		err := pg.CallPgFunc("client_update", jsonStr, nil, nil)
+		if err != nil {
+			fmt.Printf("syncClientWith1C client_update error: %s jsonStr: %s\n", err, jsonStr)
+			resMsg.Errors = append(resMsg.Errors, fmt.Sprintf("%s uuid: %s", err, v.Uuid))
+			continue
+		}
+		cnt++
+	}
+	resMsg.addResult(fmt.Sprintf("синхронизировано записей: <strong>%v</strong>", cnt))
+	elapsed := time.Since(start)
+	resMsg.setDuration(fmt.Sprintf("%s", elapsed))
+	return []resultMsgType{resMsg}
+}
+
+func getClient() ([]ClientForPgType, error) {
+	res := []ClientForPgType{}
+	start := time.Now()
+	odataQuery := odataQueryType{
+		DocType: "Catalog_Clients",
+		Format:  "json",
+		Select:  []string{"INN"},
+		Expand:  []string{},
+		Filter:  []string{},
+		//Limit:   50,
+	}
+	targetUrl := odataQuery.buildQuery()
+	//fmt.Printf("targetUrl %s\n", targetUrl)
+	tempRes := struct {
+		Value []ClientType `json:"value"`
+	}{}
+	err := odataCallByUrl(targetUrl, "GET", "json", &tempRes, nil)
+	if err != nil {
+		return res, err
+	}
+	elapsed := time.Since(start)
+	fmt.Printf("getClient len: %v took time: %s\n", len(tempRes.Value), elapsed)
+	for _, v := range tempRes.Value {
+		c := ClientForPgType{}
+		c.Inn = v.Inn
+
+		res = append(res, c)
+	}
+	return res, nil
+}
+
+func SyncClientWith1CDebug(c *gin.Context) {
+
+	resList := []ClientForPgType{}
+	start := time.Now()
+	odataQuery := odataQueryType{
+		DocType: "Catalog_Clients",
+		Format:  "json",
+		Select:  []string{"INN"},
+		Expand:  []string{},
+		Filter:  []string{},
+		Limit:   100,
+	}
+
+	targetUrl := odataQuery.buildQuery()
+	//fmt.Printf("targetUrl %s\n", targetUrl)
+	tempRes := struct {
+		Value []ClientType `json:"value"`
+	}{}
+	err := odataCallByUrl(targetUrl, "GET", "json", &tempRes, nil)
+	if err != nil {
+		utils.HttpError(c, 400, err.Error())
+		return
+	}
+	elapsed := time.Since(start)
+	fmt.Printf("getClient len: %v took time: %s\n", len(tempRes.Value), elapsed)
+	for _, v := range tempRes.Value {
+		c := ClientForPgType{}
+		c.Inn = v.Inn
+
+		resList = append(resList, c)
+	}
+
+	cnt := 0 //счетчик записей
+	for _, v := range resList {
+		jsonStr, _ := json.Marshal(v)
+		err := pg.CallPgFunc("client_update", jsonStr, nil, nil)
+		if err != nil {
+			fmt.Printf("syncClientWith1C client_update error: %s jsonStr: %s\n", err, jsonStr)
+			continue
+		}
+		cnt++
+	}
+	utils.HttpSuccess(c, fmt.Sprintf("синхронизировано записей: %v. Время %v", cnt, time.Since(start)))
+	return
+}
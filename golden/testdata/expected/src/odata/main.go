@@ -0,0 +1,11 @@
+package odata
+
+import "golden/src/types"
+
+var (
+	odataConfig types.OdataConfig
+)
+
+func SetOdataConfig(config types.OdataConfig) {
+	odataConfig = config
+}
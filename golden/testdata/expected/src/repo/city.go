@@ -0,0 +1,70 @@
+package repo
+
+import (
+	"context"
+)
+
+type (
+	// город
+	City struct {
+		Id          int                    `json:"id"`
+		Title       string                 `json:"title,omitempty"`     // название
+		ParentId    int                    `json:"parent_id,omitempty"` // родитель
+		IsFolder    bool                   `json:"is_folder,omitempty"` // признак, что является группой
+		ParentTitle string                 `json:"parent_title,omitempty"`
+		Options     map[string]interface{} `json:"options,omitempty"`
+		CreatedAt   string                 `json:"created_at,omitempty"`
+		UpdatedAt   string                 `json:"updated_at,omitempty"`
+		Deleted     bool                   `json:"deleted,omitempty"`
+	}
+
+	// CityListParams параметры CityList. Фильтры - поля-ссылки и поля с признаком IsSearch
+	CityListParams struct {
+		ListParams
+		ParentId int `json:"parent_id,omitempty"`
+	}
+)
+
+// CityGetById запись по id
+func CityGetById(ctx context.Context, id int) (*City, error) {
+	res := &City{}
+	if err := call(ctx, "city_get_by_id", map[string]interface{}{"id": id}, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// CityList список записей
+func CityList(ctx context.Context, params CityListParams) ([]City, error) {
+	res := []City{}
+	if err := call(ctx, "city_list", params, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// CityUpdate создание (Id = 0) или изменение записи.
+// Поля с нулевыми значениями не передаются. Чтобы записать пустое значение, используйте CityUpdateFlds
+func CityUpdate(ctx context.Context, item City) (*City, error) {
+	if item.Id == 0 {
+		item.Id = -1
+	}
+	res := &City{}
+	if err := call(ctx, "city_update", item, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// CityUpdateFlds изменение отдельных полей записи. Например, CityUpdateFlds(ctx, id, map[string]interface{}{"deleted": false})
+func CityUpdateFlds(ctx context.Context, id int, flds map[string]interface{}) (*City, error) {
+	params := map[string]interface{}{"id": id}
+	for k, v := range flds {
+		params[k] = v
+	}
+	res := &City{}
+	if err := call(ctx, "city_update", params, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
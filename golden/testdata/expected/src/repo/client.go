@@ -0,0 +1,91 @@
+package repo
+
+import (
+	"context"
+)
+
+type (
+	// клиент
+	Client struct {
+		Id         int                    `json:"id"`
+		Title      string                 `json:"title,omitempty"`       // название
+		Inn        string                 `json:"inn,omitempty"`         // ИНН
+		Note       string                 `json:"note,omitempty"`        // примечание
+		CityId     int                    `json:"city_id,omitempty"`     // город
+		Status     string                 `json:"status,omitempty"`      // статус
+		Channels   []string               `json:"channels,omitempty"`    // каналы
+		Kind       string                 `json:"kind,omitempty"`        // вид
+		BirthDate  string                 `json:"birth_date,omitempty"`  // дата рождения
+		LastVisit  string                 `json:"last_visit,omitempty"`  // последний визит
+		IsVip      bool                   `json:"is_vip,omitempty"`      // vip
+		Phone      string                 `json:"phone,omitempty"`       // телефон
+		Email      string                 `json:"email,omitempty"`       // email
+		Cnt        int                    `json:"cnt,omitempty"`         // количество
+		ExternalId int64                  `json:"external_id,omitempty"` // внешний id
+		Amount     float64                `json:"amount,omitempty"`      // сумма
+		Guid       string                 `json:"guid,omitempty"`        // guid
+		Tags       []string               `json:"tags,omitempty"`        // тэги
+		Address    interface{}            `json:"address,omitempty"`     // адрес
+		Contacts   interface{}            `json:"contacts,omitempty"`    // контакты
+		Docs       interface{}            `json:"docs,omitempty"`        // документы
+		Avatar     string                 `json:"avatar,omitempty"`      // аватар
+		Photos     interface{}            `json:"photos,omitempty"`      // фото
+		Extra      interface{}            `json:"extra,omitempty"`       // дополнительно
+		CityTitle  string                 `json:"city_title,omitempty"`
+		Options    map[string]interface{} `json:"options,omitempty"`
+		CreatedAt  string                 `json:"created_at,omitempty"`
+		UpdatedAt  string                 `json:"updated_at,omitempty"`
+		Deleted    bool                   `json:"deleted,omitempty"`
+	}
+
+	// ClientListParams параметры ClientList. Фильтры - поля-ссылки и поля с признаком IsSearch
+	ClientListParams struct {
+		ListParams
+		Inn    string `json:"inn,omitempty"`
+		CityId int    `json:"city_id,omitempty"`
+	}
+)
+
+// ClientGetById запись по id
+func ClientGetById(ctx context.Context, id int) (*Client, error) {
+	res := &Client{}
+	if err := call(ctx, "client_get_by_id", map[string]interface{}{"id": id}, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ClientList список записей
+func ClientList(ctx context.Context, params ClientListParams) ([]Client, error) {
+	res := []Client{}
+	if err := call(ctx, "client_list", params, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ClientUpdate создание (Id = 0) или изменение записи.
+// Поля с нулевыми значениями не передаются. Чтобы записать пустое значение, используйте ClientUpdateFlds
+func ClientUpdate(ctx context.Context, item Client) (*Client, error) {
+	if item.Id == 0 {
+		item.Id = -1
+	}
+	res := &Client{}
+	if err := call(ctx, "client_update", item, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ClientUpdateFlds изменение отдельных полей записи. Например, ClientUpdateFlds(ctx, id, map[string]interface{}{"deleted": false})
+func ClientUpdateFlds(ctx context.Context, id int, flds map[string]interface{}) (*Client, error) {
+	params := map[string]interface{}{"id": id}
+	for k, v := range flds {
+		params[k] = v
+	}
+	res := &Client{}
+	if err := call(ctx, "client_update", params, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,71 @@
+package repo
+
+import (
+	"context"
+)
+
+type (
+	// менеджеры клиента
+	ClientUserLink struct {
+		Id           int                    `json:"id"`
+		ClientId     int                    `json:"client_id,omitempty"`  // клиент
+		ManagerId    int                    `json:"manager_id,omitempty"` // менеджер
+		ClientTitle  string                 `json:"client_title,omitempty"`
+		ManagerTitle string                 `json:"manager_title,omitempty"`
+		Options      map[string]interface{} `json:"options,omitempty"`
+		CreatedAt    string                 `json:"created_at,omitempty"`
+		UpdatedAt    string                 `json:"updated_at,omitempty"`
+		Deleted      bool                   `json:"deleted,omitempty"`
+	}
+
+	// ClientUserLinkListParams параметры ClientUserLinkList. Фильтры - поля-ссылки и поля с признаком IsSearch
+	ClientUserLinkListParams struct {
+		ListParams
+		ClientId  int `json:"client_id,omitempty"`
+		ManagerId int `json:"manager_id,omitempty"`
+	}
+)
+
+// ClientUserLinkGetById запись по id
+func ClientUserLinkGetById(ctx context.Context, id int) (*ClientUserLink, error) {
+	res := &ClientUserLink{}
+	if err := call(ctx, "client_user_link_get_by_id", map[string]interface{}{"id": id}, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ClientUserLinkList список записей
+func ClientUserLinkList(ctx context.Context, params ClientUserLinkListParams) ([]ClientUserLink, error) {
+	res := []ClientUserLink{}
+	if err := call(ctx, "client_user_link_list", params, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ClientUserLinkUpdate создание (Id = 0) или изменение записи.
+// Поля с нулевыми значениями не передаются. Чтобы записать пустое значение, используйте ClientUserLinkUpdateFlds
+func ClientUserLinkUpdate(ctx context.Context, item ClientUserLink) (*ClientUserLink, error) {
+	if item.Id == 0 {
+		item.Id = -1
+	}
+	res := &ClientUserLink{}
+	if err := call(ctx, "client_user_link_update", item, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ClientUserLinkUpdateFlds изменение отдельных полей записи. Например, ClientUserLinkUpdateFlds(ctx, id, map[string]interface{}{"deleted": false})
+func ClientUserLinkUpdateFlds(ctx context.Context, id int, flds map[string]interface{}) (*ClientUserLink, error) {
+	params := map[string]interface{}{"id": id}
+	for k, v := range flds {
+		params[k] = v
+	}
+	res := &ClientUserLink{}
+	if err := call(ctx, "client_user_link_update", params, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
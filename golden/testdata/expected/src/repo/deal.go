@@ -0,0 +1,99 @@
+package repo
+
+import (
+	"context"
+)
+
+type (
+	// сделка
+	Deal struct {
+		Id          int                    `json:"id"`
+		Title       string                 `json:"title,omitempty"`     // название
+		ClientId    int                    `json:"client_id,omitempty"` // клиент
+		State       string                 `json:"state,omitempty"`     // статус
+		Sum         float64                `json:"sum,omitempty"`       // сумма
+		ClientTitle string                 `json:"client_title,omitempty"`
+		Options     map[string]interface{} `json:"options,omitempty"`
+		CreatedAt   string                 `json:"created_at,omitempty"`
+		UpdatedAt   string                 `json:"updated_at,omitempty"`
+		Deleted     bool                   `json:"deleted,omitempty"`
+	}
+
+	// DealListParams параметры DealList. Фильтры - поля-ссылки и поля с признаком IsSearch
+	DealListParams struct {
+		ListParams
+		ClientId int `json:"client_id,omitempty"`
+	}
+
+	// DealActionParams параметры перехода машины состояний. Поля заполняются при смене состояния
+	DealActionParams struct {
+		Id         int     `json:"id"`
+		ActionName string  `json:"action_name"`
+		Sum        float64 `json:"sum,omitempty"`
+	}
+)
+
+// переходы машины состояний сделка
+const (
+	DealActionDraftToInWork   = "draft_to_in_work"
+	DealActionDraftToCanceled = "draft_to_canceled"
+	DealActionInWorkToDone    = "in_work_to_done"
+)
+
+// DealGetById запись по id
+func DealGetById(ctx context.Context, id int) (*Deal, error) {
+	res := &Deal{}
+	if err := call(ctx, "deal_get_by_id", map[string]interface{}{"id": id}, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// DealList список записей
+func DealList(ctx context.Context, params DealListParams) ([]Deal, error) {
+	res := []Deal{}
+	if err := call(ctx, "deal_list", params, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// DealUpdate создание (Id = 0) или изменение записи.
+// Поля с нулевыми значениями не передаются. Чтобы записать пустое значение, используйте DealUpdateFlds
+func DealUpdate(ctx context.Context, item Deal) (*Deal, error) {
+	if item.Id == 0 {
+		item.Id = -1
+	}
+	res := &Deal{}
+	if err := call(ctx, "deal_update", item, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// DealUpdateFlds изменение отдельных полей записи. Например, DealUpdateFlds(ctx, id, map[string]interface{}{"deleted": false})
+func DealUpdateFlds(ctx context.Context, id int, flds map[string]interface{}) (*Deal, error) {
+	params := map[string]interface{}{"id": id}
+	for k, v := range flds {
+		params[k] = v
+	}
+	res := &Deal{}
+	if err := call(ctx, "deal_update", params, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// DealAction переход в другое состояние. action - константа DealAction...
+func DealAction(ctx context.Context, id int, action string) (*Deal, error) {
+	return DealActionWithParams(ctx, DealActionParams{Id: id, ActionName: action})
+}
+
+// DealActionWithParams переход в другое состояние с заполнением полей
+func DealActionWithParams(ctx context.Context, params DealActionParams) (*Deal, error) {
+	res := &Deal{}
+	if err := call(ctx, "deal_action", params, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,84 @@
+-- получение списка город
+-- параметры:
+-- deleted         type: bool - удаленные / существующие. Дефолт: false
+-- order_by        type: string - поле для сортировки и направление сортировки. Например, orderBy: "id desc"
+-- page            type: int - номер страницы. Дефолт: 1
+-- per_page        type: int - количество записей на странице. Дефолт: 1000
+-- search_text     type: string - текстовый поиск
+-- with_total      type: bool - вернуть в meta_info.total количество записей по фильтру
+-- cursor          type: json - курсорная пагинация вместо page: meta_info.next_cursor из предыдущего ответа. Для первой страницы - null
+
+DROP FUNCTION IF EXISTS city_list(params JSONB);
+CREATE OR REPLACE FUNCTION city_list(params JSONB)
+    RETURNS JSON
+    LANGUAGE plpgsql
+AS
+$function$
+
+DECLARE
+    result       JSON;
+    condQueryStr TEXT;
+    whereStr     TEXT;
+    checkMsg     TEXT;
+    total        INT;
+    metaInfo     JSONB := '{}';
+    
+BEGIN
+
+    checkMsg = check_required_params(params, ARRAY ['user_id']);
+    IF checkMsg IS NOT NULL
+    THEN
+        RETURN checkMsg;
+    END IF;
+
+    
+
+    -- сборка условия WHERE (where_str_build - функция из папки base)
+    whereStr = where_str_build(params, 'doc', ARRAY [
+        ['ilike', 'search_text', 'search_text'],
+		['notQuoted', 'parent_id', 'doc.parent_id']
+    ]);
+
+    
+
+    -- количество записей по фильтру, без учета пагинации
+    IF (params ->> 'with_total')::bool
+    THEN
+        EXECUTE concat('SELECT count(*) FROM city AS doc ', whereStr) INTO total;
+        metaInfo = metaInfo || jsonb_build_object('total', total);
+    END IF;
+
+    -- курсорная пагинация только при сортировке по одной колонке (см list_keyset_sort). Иначе - постранично:
+    -- без next_cursor в meta_info клиент переходит на page
+    IF params ? 'cursor' AND list_keyset_sort(params) IS NULL
+    THEN
+        params = params - 'cursor';
+    END IF;
+
+    -- курсорная пагинация (keyset): записи после курсора (list_keyset_where_str - функция из папки base)
+    IF params ? 'cursor'
+    THEN
+        whereStr = whereStr || list_keyset_where_str(params, 'doc');
+    END IF;
+
+    -- финальная сборка строки с условиями выборки (build_query_part_for_list - функция из папки base)
+    condQueryStr = '' || whereStr || build_query_part_for_list(params);
+
+    EXECUTE ('
+	with t1 as (select * from city as doc ' || condQueryStr || '),
+		t2 as (select t1.*, c.title as parent_title from t1 left join city c on c.id = t1.parent_id)
+ 	select array_to_json(array_agg(t2.*)) from t2') into result;
+
+    IF params ? 'cursor'
+    THEN
+        metaInfo = metaInfo || jsonb_build_object('next_cursor', list_next_cursor(params, result));
+    END IF;
+
+    RETURN json_build_object('ok', TRUE, 'result', coalesce(result, '[]'), 'meta_info', metaInfo);
+
+END
+$function$;
+
+
+
+
@@ -0,0 +1,82 @@
+-- создание город
+
+DROP FUNCTION IF EXISTS city_update(params JSONB);
+CREATE OR REPLACE FUNCTION city_update(params JSONB)
+    RETURNS JSON
+    LANGUAGE plpgsql
+AS
+$function$
+
+DECLARE
+    cityRow     city%ROWTYPE;
+    checkMsg    TEXT;
+    result      JSONB;
+    updateValue TEXT;
+    queryStr    TEXT;
+    
+BEGIN
+
+      
+	  checkMsg = check_required_params(params, ARRAY ['btx_id']);
+	  IF checkMsg IS NOT NULL
+	  THEN
+		RETURN checkMsg;
+	  END IF;
+      -- ищем запись по btx_id, если не находим, значит это новая запись
+	  SELECT *
+	  INTO cityRow
+	  FROM city
+	  WHERE btx_id = (params ->> 'btx_id')::int;
+		
+
+    
+    
+    
+    
+
+    IF cityRow.id ISNULL THEN
+        -- проверка наличия обязательных параметров
+        checkMsg = check_required_params(params, ARRAY ['title']);
+        IF checkMsg IS NOT NULL
+        THEN
+            RETURN checkMsg;
+        END IF;
+        
+
+        EXECUTE ('INSERT INTO city (title, parent_id, is_folder, options) VALUES ($1, $2, $3, $4)  RETURNING *;')
+		INTO cityRow
+		USING
+			(params ->> 'title')::text,
+			(params ->> 'parent_id')::int,
+			(params ->> 'is_folder')::bool,
+			coalesce(params -> 'options', '{}')::jsonb;
+
+        
+
+    else
+        updateValue = '' || update_str_from_json(params, ARRAY [
+			['title', 'title', 'text'],
+            ['options', 'options', 'jsonb'],
+            ['deleted', 'deleted', 'bool']
+            ]);
+
+        queryStr = concat('UPDATE city SET ', updateValue, ' WHERE btx_id=', quote_literal(cityRow.btx_id), ' RETURNING *');
+
+        EXECUTE (queryStr)
+            INTO cityRow;
+
+        -- случай когда записи с таким id не найдено
+        IF row_to_json(cityRow) ->> 'id' ISNULL
+        THEN
+            RETURN json_build_object('ok', FALSE, 'message', 'wrong id');
+        END IF;
+
+    end if;
+
+    
+
+    RETURN city_get_by_id(jsonb_build_object('id', cityRow.id, 'user_id', (params->>'user_id')::int));
+
+END
+
+$function$;
\ No newline at end of file
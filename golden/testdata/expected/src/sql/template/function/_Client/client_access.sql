@@ -0,0 +1,20 @@
+-- доступ пользователя к записи клиент по ролям (DocSql.AccessRules)
+-- вызывается из list, get_by_id, update, action и списков тэгов. Вызовы без пользователя (user_id пустой или -1) не ограничиваются
+-- параметры:
+-- userId   type: int - id пользователя
+-- doc      type: client - запись
+
+DROP FUNCTION IF EXISTS client_access(userId INT, doc client);
+CREATE OR REPLACE FUNCTION client_access(userId INT, doc client)
+    RETURNS BOOL
+    LANGUAGE sql
+    STABLE
+AS
+$function$
+
+    SELECT userId IS NULL OR userId = -1 OR exists(SELECT 1 FROM "user" u WHERE u.id = userId AND (
+        'admin' = ANY (u.role)
+        OR ('manager' = ANY (u.role) AND doc.id IN (SELECT l.client_id FROM client_user_link l WHERE l.manager_id = userId))
+    ));
+
+$function$;
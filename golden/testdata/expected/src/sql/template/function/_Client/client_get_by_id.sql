@@ -0,0 +1,48 @@
+-- поиск клиент по id
+-- параметры:
+-- id       type: int
+
+DROP FUNCTION IF EXISTS client_get_by_id(params JSONB);
+CREATE OR REPLACE FUNCTION client_get_by_id(params JSONB)
+    RETURNS JSON
+    LANGUAGE plpgsql
+AS
+$function$
+
+DECLARE
+    clientRow         client%Rowtype;
+    checkMsg               TEXT;
+    result                 jsonb;
+BEGIN
+
+    -- проверка наличия id
+    checkMsg = check_required_params_with_func_name('client_get_by_id', params, ARRAY ['id']);
+    IF checkMsg IS NOT NULL
+    THEN
+        RETURN checkMsg;
+    END IF;
+
+    -- записи, недоступные пользователю по DocSql.AccessRules, считаются ненайденными
+    IF NOT exists(SELECT 1 FROM client doc WHERE doc.id = (params ->> 'id')::int AND client_access((params ->> 'user_id')::int, doc))
+    THEN
+        RETURN json_build_object('ok', FALSE, 'message', 'not found');
+    END IF;
+
+    with t1 as (select * from client where id = (params ->> 'id')::int),
+		t2 as (select t1.*, c.title as city_title from t1 left join city c on c.id = t1.city_id)
+ 	select row_to_json(t2.*)::jsonb - 'search_vector' into result from t2;
+
+    -- случай когда записи с таким id не найдено
+    IF result ->> 'id' ISNULL
+    THEN
+        RETURN json_build_object('ok', FALSE, 'message', 'not found');
+    END IF;
+
+    -- поля, скрытые от пользователя по ролям (FldType.Roles)
+    result = jsonb_hide_flds(result, user_denied_flds((params ->> 'user_id')::int, '{"note": ["manager"]}'::jsonb), '{}'::jsonb);
+
+    RETURN json_build_object('ok', TRUE, 'result', result);
+
+END
+
+$function$;
\ No newline at end of file
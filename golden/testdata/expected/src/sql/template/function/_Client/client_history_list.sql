@@ -0,0 +1,49 @@
+-- история изменений клиент (см DocSql.IsHistory)
+-- параметры:
+-- id              type: int - id записи client
+-- page            type: int - номер страницы. Дефолт: 1
+-- per_page        type: int - количество записей на странице. Дефолт: 100
+
+DROP FUNCTION IF EXISTS client_history_list(params JSONB);
+CREATE OR REPLACE FUNCTION client_history_list(params JSONB)
+    RETURNS JSON
+    LANGUAGE plpgsql
+AS
+$function$
+
+DECLARE
+    result   JSON;
+    checkMsg TEXT;
+    perPage  INT;
+BEGIN
+
+    checkMsg = check_required_params_with_func_name('client_history_list', params, ARRAY ['id', 'user_id']);
+    IF checkMsg IS NOT NULL
+    THEN
+        RETURN checkMsg;
+    END IF;
+
+    -- записи, недоступные пользователю по DocSql.AccessRules, считаются ненайденными
+    IF NOT exists(SELECT 1 FROM client doc WHERE doc.id = (params ->> 'id')::int AND client_access((params ->> 'user_id')::int, doc))
+    THEN
+        RETURN json_build_object('ok', FALSE, 'message', 'not found');
+    END IF;
+
+    perPage = coalesce((params ->> 'per_page')::int, 100);
+
+    SELECT array_to_json(array_agg(t)) INTO result FROM (
+        -- поля, скрытые от пользователя по ролям (FldType.Roles), не показываются и в истории
+        SELECT h.id, h.operation, jsonb_hide_flds(h.old_values, d.flds, '{}'::jsonb) AS old_values,
+               jsonb_hide_flds(h.new_values, d.flds, '{}'::jsonb) AS new_values, h.created_at, h.user_id, u.fullname AS user_fullname
+        FROM client_history h
+        LEFT JOIN "user" u ON u.id = h.user_id,
+        user_denied_flds((params ->> 'user_id')::int, '{"note": ["manager"]}'::jsonb) d(flds)
+        WHERE h.doc_id = (params ->> 'id')::int
+        ORDER BY h.id DESC
+        LIMIT perPage OFFSET (coalesce((params ->> 'page')::int, 1) - 1) * perPage
+    ) t;
+
+    RETURN json_build_object('ok', TRUE, 'result', coalesce(result, '[]'));
+
+END
+$function$;
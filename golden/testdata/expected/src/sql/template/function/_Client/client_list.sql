@@ -0,0 +1,128 @@
+-- получение списка клиент
+-- параметры:
+-- deleted         type: bool - удаленные / существующие. Дефолт: false
+-- order_by        type: string - поле для сортировки и направление сортировки. Например, orderBy: "id desc"
+-- page            type: int - номер страницы. Дефолт: 1
+-- per_page        type: int - количество записей на странице. Дефолт: 1000
+-- search_text     type: string - текстовый поиск
+-- with_total      type: bool - вернуть в meta_info.total количество записей по фильтру
+-- cursor          type: json - курсорная пагинация вместо page: meta_info.next_cursor из предыдущего ответа. Для первой страницы - null
+-- is_default_order type: bool - order_by - сортировка по умолчанию, не выбранная пользователем: при search_text сортировка по релевантности
+
+DROP FUNCTION IF EXISTS client_list(params JSONB);
+CREATE OR REPLACE FUNCTION client_list(params JSONB)
+    RETURNS JSON
+    LANGUAGE plpgsql
+AS
+$function$
+
+DECLARE
+    result       JSON;
+    condQueryStr TEXT;
+    whereStr     TEXT;
+    checkMsg     TEXT;
+    total        INT;
+    metaInfo     JSONB := '{}';
+    searchQuery  tsquery;
+    
+BEGIN
+
+    checkMsg = check_required_params(params, ARRAY ['user_id']);
+    IF checkMsg IS NOT NULL
+    THEN
+        RETURN checkMsg;
+    END IF;
+
+    -- фильтры, сортировка и поиск по полям, скрытым от пользователя по ролям (FldType.Roles), запрещены
+    checkMsg = check_denied_params(params, user_denied_flds((params ->> 'user_id')::int, '{"note": ["manager"]}'::jsonb), '{}'::jsonb);
+    IF checkMsg IS NOT NULL
+    THEN
+        RETURN checkMsg;
+    END IF;
+
+    
+
+    -- сборка условия WHERE (where_str_build - функция из папки base)
+    whereStr = where_str_build(params, 'doc', ARRAY [
+        ['format', 'search_text', 'doc.search_vector @@ search_tsquery(''russian'', %s)'],
+		['text', 'inn', 'doc.inn'],
+		['notQuoted', 'city_id', 'doc.city_id'],
+		['dateBetween', 'birth_date_between', 'doc.birth_date'],
+		['gte', 'amount_gte', 'doc.amount'],
+		['lte', 'amount_lte', 'doc.amount'],
+		['in', 'status_in', 'doc.status'],
+		['notIn', 'kind_not_in', 'doc.kind'],
+		['isNull', 'phone_is_null', 'doc.phone'],
+		['in', 'city_id_in', 'doc.city_id'],
+		['arrayContains', 'tags_contains', 'doc.tags']
+    ]);
+
+    -- ограничение записей по ролям пользователя (DocSql.AccessRules)
+    whereStr = concat(whereStr, ' AND client_access(', quote_nullable(params ->> 'user_id'), '::int, doc)');
+
+    -- полнотекстовый поиск: если сортировка не указана или клиент передал сортировку по умолчанию (is_default_order),
+    -- то сортируем по релевантности. Курсор по релевантности не строится, поэтому дальше постранично
+    searchQuery = search_tsquery('russian', params ->> 'search_text');
+    IF searchQuery IS NOT NULL AND (params ->> 'order_by' IS NULL OR coalesce((params ->> 'is_default_order')::bool, FALSE))
+    THEN
+        params = (params - 'cursor') || jsonb_build_object('order_by', format('ts_rank(doc.search_vector, %L::tsquery) DESC, doc.id DESC', searchQuery));
+    END IF;
+
+    
+
+    -- количество записей по фильтру, без учета пагинации
+    IF (params ->> 'with_total')::bool
+    THEN
+        EXECUTE concat('SELECT count(*) FROM client AS doc ', whereStr) INTO total;
+        metaInfo = metaInfo || jsonb_build_object('total', total);
+    END IF;
+
+    -- курсорная пагинация только при сортировке по одной колонке (см list_keyset_sort). Иначе - постранично:
+    -- без next_cursor в meta_info клиент переходит на page
+    IF params ? 'cursor' AND list_keyset_sort(params) IS NULL
+    THEN
+        params = params - 'cursor';
+    END IF;
+
+    -- курсорная пагинация (keyset): записи после курсора (list_keyset_where_str - функция из папки base)
+    IF params ? 'cursor'
+    THEN
+        whereStr = whereStr || list_keyset_where_str(params, 'doc');
+    END IF;
+
+    -- финальная сборка строки с условиями выборки (build_query_part_for_list - функция из папки base)
+    condQueryStr = '' || whereStr || build_query_part_for_list(params);
+
+    EXECUTE ('
+	with t1 as (select * from client as doc ' || condQueryStr || ')
+ 	select json_agg(to_jsonb(t1.*) - ''search_vector'') from t1') into result;
+
+    IF params ? 'cursor'
+    THEN
+        metaInfo = metaInfo || jsonb_build_object('next_cursor', list_next_cursor(params, result));
+    END IF;
+
+    -- релевантность и найденные фрагменты текста в meta_info каждой записи
+    IF searchQuery IS NOT NULL
+    THEN
+        SELECT json_agg(e.value || jsonb_build_object('meta_info', jsonb_build_object(
+                   'rank', ts_rank(doc.search_vector, searchQuery),
+                   'snippet', ts_headline('russian', concat_ws(' ', e.value ->> 'title', e.value ->> 'inn', e.value -> 'options' -> 'title' ->> 'city_title'), searchQuery))) ORDER BY e.ordinality)
+        INTO result
+        FROM jsonb_array_elements(result::jsonb) WITH ORDINALITY e
+                 JOIN client doc ON doc.id = (e.value ->> 'id')::int;
+    END IF;
+
+    -- поля, скрытые от пользователя по ролям (FldType.Roles)
+    SELECT json_agg(jsonb_hide_flds(e.value, h.flds, '{}'::jsonb) ORDER BY e.ordinality) INTO result
+    FROM jsonb_array_elements(result::jsonb) WITH ORDINALITY e,
+         user_denied_flds((params ->> 'user_id')::int, '{"note": ["manager"]}'::jsonb) h(flds);
+
+    RETURN json_build_object('ok', TRUE, 'result', coalesce(result, '[]'), 'meta_info', metaInfo);
+
+END
+$function$;
+
+
+
+
@@ -0,0 +1,21 @@
+-- получение списка тэгов
+-- параметры:
+
+DROP FUNCTION IF EXISTS client_tags_list(params JSONB );
+CREATE OR REPLACE FUNCTION client_tags_list(params JSONB)
+  RETURNS JSONB
+LANGUAGE plpgsql
+AS $function$
+
+DECLARE
+  result JSON;
+BEGIN
+
+  EXECUTE (
+    'SELECT array_to_json(array_agg(t.unnest)) FROM (select DISTINCT unnest(tags) from client AS doc WHERE client_access(' || quote_nullable(params ->> 'user_id') || '::int, doc)) AS t')
+  INTO result;
+
+  RETURN json_build_object('ok', TRUE, 'result', coalesce(result, '[]'));
+
+END
+$function$;
\ No newline at end of file
@@ -0,0 +1,36 @@
+-- функция триггер
+DROP FUNCTION IF EXISTS client_trigger_before() CASCADE;
+CREATE OR REPLACE FUNCTION client_trigger_before() RETURNS trigger AS
+$$
+DECLARE
+        r record;
+	cityTitle TEXT;
+
+       searchTxtVar TEXT := '';
+BEGIN
+
+        -- заполняем ref поля
+		select title into cityTitle from city where id = new.city_id;
+        
+        NEW.phone = phone_change_8_to_7(NEW.phone);
+         searchTxtVar = searchTxtVar || (NEW.tags)::text;
+        -- заполняем options.title
+        NEW.options = coalesce(OLD.options, '{}'::jsonb) || NEW.options || jsonb_build_object('title', jsonb_build_object('title', new.title, 'inn', new.inn, 'city_title', cityTitle));
+        -- заполняем search_text
+        
+        NEW.search_text = concat(new.title, ' ', new.inn, ' ', cityTitle, ' ', searchTxtVar);
+
+        -- заполняем search_vector для полнотекстового поиска
+        NEW.search_vector = setweight(to_tsvector('russian', coalesce(new.title::text, '')), 'A') ||
+            setweight(to_tsvector('russian', coalesce(new.inn::text, '')), 'C') ||
+            setweight(to_tsvector('russian', coalesce(cityTitle, '')), 'B') ||
+            setweight(to_tsvector('russian', coalesce(searchTxtVar, '')), 'D');
+
+        
+
+
+    RETURN NEW;
+END;
+
+$$ LANGUAGE plpgsql;
+
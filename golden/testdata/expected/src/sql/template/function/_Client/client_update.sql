@@ -0,0 +1,172 @@
+-- создание клиент
+
+DROP FUNCTION IF EXISTS client_update(params JSONB);
+CREATE OR REPLACE FUNCTION client_update(params JSONB)
+    RETURNS JSON
+    LANGUAGE plpgsql
+AS
+$function$
+
+DECLARE
+    clientRow     client%ROWTYPE;
+    checkMsg    TEXT;
+    result      JSONB;
+    updateValue TEXT;
+    queryStr    TEXT;
+    
+BEGIN
+
+    -- пользователь, от имени которого идет изменение. Его записывает в client_history триггер doc_history
+    PERFORM set_config('app.user_id', coalesce(params ->> 'user_id', ''), true);
+
+    -- поля, которые пользователю нельзя изменять по ролям (FldType.Roles), не обновляются
+    params = params - user_denied_flds((params ->> 'user_id')::int, '{"note": ["manager"], "cnt": ["admin"]}'::jsonb);
+
+      
+	  checkMsg = check_required_params(params, ARRAY ['uuid']);
+	  IF checkMsg IS NOT NULL
+	  THEN
+		RETURN checkMsg;
+	  END IF;
+      -- ищем запись по uuid, если не находим, значит это новая запись
+	  SELECT *
+	  INTO clientRow
+	  FROM client
+	  WHERE uuid = (params ->> 'uuid')::uuid;
+		
+
+    
+    
+    
+    
+    
+    
+    
+    
+    
+    
+    
+    
+    
+    
+    
+    
+    if char_length((params->>'guid')::text) = 0 then
+        params = params || jsonb_build_object('guid', '00000000-0000-0000-0000-000000000000');
+    end if;
+    
+    
+    
+    
+    
+    
+    
+    
+    
+    
+
+    IF clientRow.id ISNULL THEN
+        -- проверка наличия обязательных параметров
+        checkMsg = check_required_params(params, ARRAY ['title']);
+        IF checkMsg IS NOT NULL
+        THEN
+            RETURN checkMsg;
+        END IF;
+        
+
+        EXECUTE ('INSERT INTO client (title, inn, note, city_id, status, channels, kind, birth_date, last_visit, is_vip, phone, email, cnt, external_id, amount, guid, tags, address, contacts, docs, avatar, photos, extra, options) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)  RETURNING *;')
+		INTO clientRow
+		USING
+			(params ->> 'title')::text,
+			(params ->> 'inn')::text,
+			(params ->> 'note')::text,
+			(params ->> 'city_id')::int,
+			(params ->> 'status')::text,
+			text_array_from_json(params -> 'channels'),
+			(params ->> 'kind')::text,
+			(params ->> 'birth_date')::timestamp,
+			(params ->> 'last_visit')::timestamp,
+			(params ->> 'is_vip')::bool,
+			(params ->> 'phone')::text,
+			(params ->> 'email')::text,
+			(params ->> 'cnt')::int,
+			(params ->> 'external_id')::int,
+			(params ->> 'amount')::double precision,
+			(params ->> 'guid')::uuid,
+			text_array_from_json(params -> 'tags'),
+			(params -> 'address')::jsonb,
+			(params -> 'contacts')::jsonb,
+			(params -> 'docs')::jsonb,
+			(params ->> 'avatar')::text,
+			(params -> 'photos')::jsonb,
+			(params -> 'extra')::jsonb,
+			coalesce(params -> 'options', '{}')::jsonb;
+
+        
+
+    else
+        -- записи, недоступные пользователю по DocSql.AccessRules, считаются ненайденными
+        IF NOT exists(SELECT 1 FROM client doc WHERE doc.id = clientRow.id AND client_access((params ->> 'user_id')::int, doc))
+        THEN
+            RETURN json_build_object('ok', FALSE, 'message', 'wrong id');
+        END IF;
+
+        updateValue = '' || update_str_from_json(params, ARRAY [
+			['title', 'title', 'text'],
+			['inn', 'inn', 'text'],
+			['note', 'note', 'text'],
+			['city_id', 'city_id', 'number'],
+			['status', 'status', 'text'],
+			['channels', 'channels', 'jsonArrayText'],
+			['kind', 'kind', 'text'],
+			['birth_date', 'birth_date', 'timestamp'],
+			['last_visit', 'last_visit', 'timestamp'],
+			['is_vip', 'is_vip', 'bool'],
+			['phone', 'phone', 'text'],
+			['email', 'email', 'text'],
+			['cnt', 'cnt', 'number'],
+			['external_id', 'external_id', 'number'],
+			['amount', 'amount', 'number'],
+			['guid', 'guid', 'text'],
+			['tags', 'tags', 'jsonArrayText'],
+			['address', 'address', 'jsonb'],
+			['contacts', 'contacts', 'jsonb'],
+			['docs', 'docs', 'jsonb'],
+			['avatar', 'avatar', 'text'],
+			['photos', 'photos', 'jsonb'],
+			['extra', 'extra', 'jsonb'],
+            ['options', 'options', 'jsonb'],
+            ['deleted', 'deleted', 'bool']
+            ]);
+
+        queryStr = concat('UPDATE client SET ', updateValue, ' WHERE uuid=', quote_literal(clientRow.uuid), ' RETURNING *');
+
+        EXECUTE (queryStr)
+            INTO clientRow;
+
+        -- случай когда записи с таким id не найдено
+        IF row_to_json(clientRow) ->> 'id' ISNULL
+        THEN
+            RETURN json_build_object('ok', FALSE, 'message', 'wrong id');
+        END IF;
+
+        -- запись должна остаться доступной пользователю по DocSql.AccessRules (например, нельзя передать ее другому менеджеру)
+        IF NOT client_access((params ->> 'user_id')::int, clientRow)
+        THEN
+            RAISE EXCEPTION USING ERRCODE = 'NL403', MESSAGE = 'access denied';
+        END IF;
+
+    end if;
+
+    
+
+    RETURN client_get_by_id(jsonb_build_object('id', clientRow.id, 'user_id', (params->>'user_id')::int));
+
+EXCEPTION
+    -- запись стала недоступна пользователю по DocSql.AccessRules
+    WHEN SQLSTATE 'NL403' THEN
+        RETURN json_build_object('ok', FALSE, 'message', SQLERRM);
+
+END
+
+$function$;
\ No newline at end of file
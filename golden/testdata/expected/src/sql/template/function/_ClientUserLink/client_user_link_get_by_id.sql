@@ -0,0 +1,40 @@
+-- поиск менеджеры клиента по id
+-- параметры:
+-- id       type: int
+
+DROP FUNCTION IF EXISTS client_user_link_get_by_id(params JSONB);
+CREATE OR REPLACE FUNCTION client_user_link_get_by_id(params JSONB)
+    RETURNS JSON
+    LANGUAGE plpgsql
+AS
+$function$
+
+DECLARE
+    client_user_linkRow         client_user_link%Rowtype;
+    checkMsg               TEXT;
+    result                 jsonb;
+BEGIN
+
+    -- проверка наличия id
+    checkMsg = check_required_params_with_func_name('client_user_link_get_by_id', params, ARRAY ['id']);
+    IF checkMsg IS NOT NULL
+    THEN
+        RETURN checkMsg;
+    END IF;
+
+    with t1 as (select * from client_user_link where id = (params ->> 'id')::int),
+		t2 as (select t1.*, c.title as client_title from t1 left join client c on c.id = t1.client_id),
+		t3 as (select t2.*, c.title as manager_title from t2 left join "user" c on c.id = t2.manager_id)
+ 	select row_to_json(t3.*)::jsonb into result from t3;
+
+    -- случай когда записи с таким id не найдено
+    IF result ->> 'id' ISNULL
+    THEN
+        RETURN json_build_object('ok', FALSE, 'message', 'not found');
+    END IF;
+
+    RETURN json_build_object('ok', TRUE, 'result', result);
+
+END
+
+$function$;
\ No newline at end of file
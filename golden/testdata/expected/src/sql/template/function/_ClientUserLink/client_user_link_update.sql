@@ -0,0 +1,77 @@
+-- создание менеджеры клиента
+
+DROP FUNCTION IF EXISTS client_user_link_update(params JSONB);
+CREATE OR REPLACE FUNCTION client_user_link_update(params JSONB)
+    RETURNS JSON
+    LANGUAGE plpgsql
+AS
+$function$
+
+DECLARE
+    client_user_linkRow     client_user_link%ROWTYPE;
+    checkMsg    TEXT;
+    result      JSONB;
+    updateValue TEXT;
+    queryStr    TEXT;
+    
+BEGIN
+
+    
+    -- проверка наличия id
+    checkMsg = check_required_params(params, ARRAY ['id']);
+    IF checkMsg IS NOT NULL
+    THEN
+        RETURN checkMsg;
+    END IF;
+	
+
+    
+    
+    
+
+    if (params ->> 'id')::int = -1 then
+        -- проверка наличия обязательных параметров
+        checkMsg = check_required_params(params, ARRAY ['client_id', 'manager_id']);
+        IF checkMsg IS NOT NULL
+        THEN
+            RETURN checkMsg;
+        END IF;
+        
+
+        EXECUTE ('INSERT INTO client_user_link (client_id, manager_id, options) VALUES ($1, $2, $3)  ON CONFLICT (client_id, manager_id) DO UPDATE SET options=$3, deleted=false RETURNING *;')
+		INTO client_user_linkRow
+		USING
+			(params ->> 'client_id')::int,
+			(params ->> 'manager_id')::int,
+			coalesce(params -> 'options', '{}')::jsonb;
+
+        
+
+    else
+        updateValue = '' || update_str_from_json(params, ARRAY [
+			['client_id', 'client_id', 'number'],
+			['manager_id', 'manager_id', 'number'],
+            ['options', 'options', 'jsonb'],
+            ['deleted', 'deleted', 'bool']
+            ]);
+
+        queryStr = concat('UPDATE client_user_link SET ', updateValue, ' WHERE id=', params ->> 'id', ' RETURNING *;');
+
+        EXECUTE (queryStr)
+            INTO client_user_linkRow;
+
+        -- случай когда записи с таким id не найдено
+        IF row_to_json(client_user_linkRow) ->> 'id' ISNULL
+        THEN
+            RETURN json_build_object('ok', FALSE, 'message', 'wrong id');
+        END IF;
+
+    end if;
+
+    
+
+    RETURN client_user_link_get_by_id(jsonb_build_object('id', client_user_linkRow.id, 'user_id', (params->>'user_id')::int));
+
+END
+
+$function$;
\ No newline at end of file
@@ -0,0 +1,147 @@
+-- вызов action in state machine: сделка
+-- параметры:
+
+DROP FUNCTION IF EXISTS deal_action(params JSONB);
+CREATE OR REPLACE FUNCTION deal_action(params JSONB)
+    RETURNS JSON
+    LANGUAGE plpgsql
+AS
+$function$
+
+DECLARE
+    r           deal%ROWTYPE;
+    rJson       jsonb;
+    result      json;
+    checkMsg    TEXT;
+    updateValue TEXT;
+    newStateName TEXT;
+    allowedStates TEXT[];
+    updateFlds  text[];
+    copyToParamsFlds  text[];
+    copyFldName  text;
+    arrFlds     VARCHAR[] := '{{options, options, jsonb}}'::VARCHAR[];
+    m           VARCHAR[];
+    clientTitle TEXT;
+	
+BEGIN
+
+    -- поля, которые пользователю нельзя изменять по ролям (FldType.Roles), не обновляются
+    params = params - user_denied_flds((params ->> 'user_id')::int, '{"client_id": ["manager"]}'::jsonb);
+
+    -- проверка наличия id
+    checkMsg = check_required_params(params, ARRAY ['id', 'action_name', 'user_id']);
+    IF checkMsg IS NOT NULL
+    THEN
+        RETURN checkMsg;
+    END IF;
+
+    select * into r from deal where id = (params ->> 'id')::int;
+    IF r.id ISNULL
+    THEN
+        RETURN json_build_object('ok', FALSE, 'message', 'wrong id');
+    END IF;
+
+    -- записи, недоступные пользователю по DocSql.AccessRules, считаются ненайденными
+    IF NOT exists(SELECT 1 FROM deal doc WHERE doc.id = r.id AND deal_access((params ->> 'user_id')::int, doc))
+    THEN
+        RETURN json_build_object('ok', FALSE, 'message', 'wrong id');
+    END IF;
+    -- создаем json объект из записи, чтобы можно было обращаться к значениям колонок через название переменных
+    rJson = row_to_json(r)::jsonb;
+
+    if params->'options'->'states' isnull then
+        params = jsonb_set(params, '{options, states}'::text[], '[]'::jsonb);
+    end if;
+
+    case params->>'action_name'
+
+		when 'draft_to_in_work' then
+			newStateName = 'in_work';
+			allowedStates = '{draft}'::text[];
+			copyToParamsFlds = '{}'::text[];
+			updateFlds = ARRAY ['state']::text[];
+			 
+			
+
+		when 'draft_to_canceled' then
+			newStateName = 'canceled';
+			allowedStates = '{draft}'::text[];
+			copyToParamsFlds = '{}'::text[];
+			updateFlds = ARRAY ['state']::text[];
+			 
+			
+
+		when 'in_work_to_done' then
+			newStateName = 'done';
+			allowedStates = '{in_work}'::text[];
+			copyToParamsFlds = '{}'::text[];
+			updateFlds = ARRAY ['state', 'sum']::text[];
+			if (params->>'sum')::double precision <= 0 then
+	return json_build_object('ok', false, 'message', 'sum is empty');
+end if;
+ 
+			
+
+        else
+            RETURN json_build_object('ok', FALSE, 'message', 'wrong action name');
+        end case;
+
+
+    -- проверка что экшен из того стейта, в котором сейчас находится документ
+    if array_length(allowedStates, 1) > 0 AND r.state != ALL(allowedStates) then
+        RETURN json_build_object('ok', FALSE, 'message', 'wrong action for current state');
+    end if;
+
+    --записываем название нового стейта
+    params = params || jsonb_build_object('state', newStateName);
+
+    -- если это смена стейта.
+    if r.state != newStateName then
+        -- сразу сохраняем коммент, потому что это поле есть во всех стейтах
+        params = params || jsonb_set(params, '{options, states, 0}'::text[] || '{comment}'::text[], rJson -> 'comment');
+
+        -- копируем в options значения необходимых полей из предыдущего стейта
+        if array_length(copyToParamsFlds, 1) > 0 then
+            FOREACH copyFldName IN ARRAY copyToParamsFlds
+                LOOP
+                    params = params || jsonb_set(params, '{options, states, 0}'::text[] || copyFldName, rJson -> copyFldName);
+                    
+			-- в случае обновления ссылки добавляем название
+			if copyFldName = 'client_id' AND (rJson ->> copyFldName)::int notnull then
+				select title into clientTitle from client where id = (rJson ->> copyFldName)::int;
+				params = params || jsonb_set(params, '{options, states, 0}'::text[] || '{client_title}'::text[], to_jsonb(clientTitle));
+			end if;
+		
+                END LOOP;
+        end if;
+    -- прописываем кто изменил статус и когда
+--     params = params || jsonb_build_object('options', options_add_fld((params->>'user_id')::int, params->'options', 'states', jsonb_build_object('state', newStateName)));
+    params = params || jsonb_build_object('options', jsonb_insert( params->'options', '{states, 0}'::text[], jsonb_build_object('state', newStateName, 'user_id', (params->>'user_id')::int, 'date', now() at time zone 'Europe/Moscow')));
+
+    end if;
+
+    -- оставляем только поля, которые указаны в updateFlds, котрые отфильтрованы в зависимости от текущего стейта
+    FOREACH m SLICE 1 IN ARRAY ARRAY [
+			['title', 'title', 'text'],
+			['client_id', 'client_id', 'number'],
+			['state', 'state', 'text'],
+			['sum', 'sum', 'number'],
+        ['options', 'options', 'jsonb'],
+        ['deleted', 'deleted', 'bool']
+        ]
+        loop
+            IF m[1] = ANY (updateFlds) then
+                arrFlds = arrFlds || m;
+            end if;
+        end loop;
+
+    EXECUTE (concat('UPDATE deal SET ', '' || update_str_from_json(params, arrFlds), ' WHERE id=', params ->> 'id', ' RETURNING *;'))
+        INTO r;
+
+    -- действия в случае успешного выполнения action
+    
+
+    RETURN json_build_object('ok', TRUE, 'result', row_to_json(r) :: JSONB);
+END
+
+$function$;
\ No newline at end of file
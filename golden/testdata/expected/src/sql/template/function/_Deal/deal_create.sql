@@ -0,0 +1,58 @@
+-- создание in state machine: сделка
+-- параметры:
+
+DROP FUNCTION IF EXISTS deal_create(params JSONB);
+CREATE OR REPLACE FUNCTION deal_create(params JSONB)
+    RETURNS JSON
+    LANGUAGE plpgsql
+AS
+$function$
+
+DECLARE
+    dealRow     deal%ROWTYPE;
+    checkMsg    TEXT;
+ 
+BEGIN
+
+    -- поля, которые пользователю нельзя изменять по ролям (FldType.Roles), не обновляются
+    params = params - user_denied_flds((params ->> 'user_id')::int, '{"client_id": ["manager"]}'::jsonb);
+
+    
+    -- проверка наличия id
+    checkMsg = check_required_params(params, ARRAY ['id']);
+    IF checkMsg IS NOT NULL
+    THEN
+        RETURN checkMsg;
+    END IF;
+	
+
+    
+
+    -- проверка наличия обязательных параметров
+    checkMsg = check_required_params(params, ARRAY [ 'title' ]);
+    IF checkMsg IS NOT NULL
+    THEN
+        RETURN checkMsg;
+    END IF;
+    -- прописываем кто изменил статус и когда
+    params = params || jsonb_build_object('state', 'draft', 'options', options_add_fld((params->>'user_id')::int, coalesce(params->'options', '{}'::jsonb), 'states',
+                                                                     jsonb_build_object('state', 'draft', 'date', now() at time zone 'Europe/Moscow')));
+
+    
+
+    EXECUTE ('INSERT INTO deal (title, client_id, state, sum, options) VALUES ($1, $2, $3, $4, $5)  RETURNING *;')
+		INTO dealRow
+		USING
+			(params ->> 'title')::text,
+			(params ->> 'client_id')::int,
+			coalesce((params ->> 'state')::text, 'draft')::text,
+			(params ->> 'sum')::double precision,
+			coalesce(params -> 'options', '{}')::jsonb;
+
+    
+
+    RETURN json_build_object('ok', TRUE, 'result', row_to_json(dealRow) :: JSONB);
+
+END
+
+$function$;
\ No newline at end of file
@@ -0,0 +1,48 @@
+-- поиск сделка по id
+-- параметры:
+-- id       type: int
+
+DROP FUNCTION IF EXISTS deal_get_by_id(params JSONB);
+CREATE OR REPLACE FUNCTION deal_get_by_id(params JSONB)
+    RETURNS JSON
+    LANGUAGE plpgsql
+AS
+$function$
+
+DECLARE
+    dealRow         deal%Rowtype;
+    checkMsg               TEXT;
+    result                 jsonb;
+BEGIN
+
+    -- проверка наличия id
+    checkMsg = check_required_params_with_func_name('deal_get_by_id', params, ARRAY ['id']);
+    IF checkMsg IS NOT NULL
+    THEN
+        RETURN checkMsg;
+    END IF;
+
+    -- записи, недоступные пользователю по DocSql.AccessRules, считаются ненайденными
+    IF NOT exists(SELECT 1 FROM deal doc WHERE doc.id = (params ->> 'id')::int AND deal_access((params ->> 'user_id')::int, doc))
+    THEN
+        RETURN json_build_object('ok', FALSE, 'message', 'not found');
+    END IF;
+
+    with t1 as (select * from deal where id = (params ->> 'id')::int),
+		t2 as (select t1.*, c.title as client_title from t1 left join client c on c.id = t1.client_id)
+ 	select row_to_json(t2.*)::jsonb into result from t2;
+
+    -- случай когда записи с таким id не найдено
+    IF result ->> 'id' ISNULL
+    THEN
+        RETURN json_build_object('ok', FALSE, 'message', 'not found');
+    END IF;
+
+    -- поля, скрытые от пользователя по ролям (FldType.Roles)
+    result = jsonb_hide_flds(result, user_denied_flds((params ->> 'user_id')::int, '{"client_id": ["manager"]}'::jsonb), '{"client_id": ["client_title"]}'::jsonb);
+
+    RETURN json_build_object('ok', TRUE, 'result', result);
+
+END
+
+$function$;
\ No newline at end of file
@@ -0,0 +1,95 @@
+-- update в случае если документ реализует поведение state machine
+-- создание сделка
+-- параметры:
+
+DROP FUNCTION IF EXISTS deal_update(params JSONB);
+CREATE OR REPLACE FUNCTION deal_update(params JSONB)
+    RETURNS JSON
+    LANGUAGE plpgsql
+AS
+$function$
+
+DECLARE
+    r           deal%ROWTYPE;
+    rNew        deal%ROWTYPE;
+    checkMsg    TEXT;
+    result      JSONB;
+    updateValue TEXT;
+    queryStr    TEXT;
+    updateFlds  text[];
+    arrFlds     VARCHAR[] := '{{options, options, jsonb}}'::VARCHAR[];
+    m           VARCHAR[];
+BEGIN
+
+    -- поля, которые пользователю нельзя изменять по ролям (FldType.Roles), не обновляются
+    params = params - user_denied_flds((params ->> 'user_id')::int, '{"client_id": ["manager"]}'::jsonb);
+
+    -- проверка наличия id
+    checkMsg = check_required_params(params, ARRAY ['id', 'user_id']);
+    IF checkMsg IS NOT NULL
+    THEN
+        RETURN checkMsg;
+    END IF;
+
+    select * into r from deal where id = (params ->> 'id')::int;
+    IF r.id ISNULL
+    THEN
+        RETURN json_build_object('ok', FALSE, 'message', 'wrong id');
+    END IF;
+
+    -- записи, недоступные пользователю по DocSql.AccessRules, считаются ненайденными
+    IF NOT exists(SELECT 1 FROM deal doc WHERE doc.id = r.id AND deal_access((params ->> 'user_id')::int, doc))
+    THEN
+        RETURN json_build_object('ok', FALSE, 'message', 'wrong id');
+    END IF;
+
+    case r.state
+		when 'draft' then
+			updateFlds = ARRAY ['deleted']::text[];
+		when 'in_work' then
+			updateFlds = ARRAY ['deleted']::text[];
+		when 'done' then
+			updateFlds = ARRAY ['deleted']::text[];
+		when 'canceled' then
+			updateFlds = ARRAY ['deleted']::text[];
+
+        else
+            RETURN json_build_object('ok', FALSE, 'message', 'wrong stateName in deal_update');
+        end case;
+
+    -- оставляем только поля, которые указаны в updateFlds, котрые отфильтрованы в зависимости от текущего стейта
+    FOREACH m SLICE 1 IN ARRAY ARRAY [
+			['title', 'title', 'text'],
+			['client_id', 'client_id', 'number'],
+			['state', 'state', 'text'],
+			['sum', 'sum', 'number'],
+        ['options', 'options', 'jsonb'],
+        ['deleted', 'deleted', 'bool']
+        ]
+        loop
+            IF m[1] = ANY (updateFlds) then
+                arrFlds = arrFlds || m;
+            end if;
+        end loop;
+
+    EXECUTE (concat('UPDATE deal SET ', '' || update_str_from_json(params, arrFlds), ' WHERE id=', params ->> 'id', ' RETURNING *;'))
+        INTO rNew;
+
+    -- запись должна остаться доступной пользователю по DocSql.AccessRules (например, нельзя передать ее другому менеджеру)
+    IF NOT deal_access((params ->> 'user_id')::int, rNew)
+    THEN
+        RAISE EXCEPTION USING ERRCODE = 'NL403', MESSAGE = 'access denied';
+    END IF;
+
+    
+
+    RETURN deal_get_by_id(params);
+
+EXCEPTION
+    -- запись стала недоступна пользователю по DocSql.AccessRules
+    WHEN SQLSTATE 'NL403' THEN
+        RETURN json_build_object('ok', FALSE, 'message', SQLERRM);
+
+END
+
+$function$;
\ No newline at end of file
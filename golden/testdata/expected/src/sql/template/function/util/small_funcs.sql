@@ -0,0 +1,229 @@
+CREATE OR REPLACE FUNCTION random(NUMERIC, NUMERIC)
+  RETURNS NUMERIC AS
+$$
+SELECT ($1 + ($2 - $1) * random()) :: NUMERIC;
+$$ LANGUAGE 'sql' VOLATILE;
+
+-- функция конвертации json массива в текстовый массив
+DROP FUNCTION IF EXISTS text_array_from_json(jsonArr JSONB );
+CREATE OR REPLACE FUNCTION text_array_from_json(jsonArr JSONB)
+  RETURNS TEXT []
+LANGUAGE plpgsql
+AS $function$
+BEGIN
+
+  IF jsonArr ISNULL OR jsonArr = 'null'
+  THEN RETURN NULL;
+  END IF;
+
+  RETURN COALESCE((SELECT array_agg(e)
+                   FROM jsonb_array_elements_text(jsonArr) e), '{}' :: TEXT []);
+END
+$function$;
+
+-- функция конвертации json массива в массив целых чисел
+DROP FUNCTION IF EXISTS int_array_from_json(jsonArr JSONB );
+CREATE OR REPLACE FUNCTION int_array_from_json(jsonArr JSONB)
+  RETURNS INT []
+LANGUAGE plpgsql
+AS $function$
+BEGIN
+
+  IF jsonArr ISNULL OR jsonArr = 'null'
+  THEN RETURN NULL;
+  END IF;
+
+  RETURN COALESCE((SELECT array_agg(e) :: INT []
+                   FROM jsonb_array_elements_text(jsonArr) e), '{}' :: INT []);
+END
+$function$;
+
+-- функция конвертации json массива в массив дробных чисел
+DROP FUNCTION IF EXISTS double_array_from_json(jsonArr JSONB );
+CREATE OR REPLACE FUNCTION double_array_from_json(jsonArr JSONB)
+  RETURNS double precision []
+LANGUAGE plpgsql
+AS $function$
+BEGIN
+
+  IF jsonArr ISNULL OR jsonArr = 'null'
+  THEN RETURN NULL;
+  END IF;
+
+  RETURN COALESCE((SELECT array_agg(e) :: double precision []
+                   FROM jsonb_array_elements_text(jsonArr) e), '{}' :: double precision []);
+END
+$function$;
+
+-- функция для модификации options - используется в функции first_raw_transition_order_update
+DROP FUNCTION IF EXISTS options_add_fld(userId int, options JSONB, fldName text, jsonObj jsonb);
+CREATE OR REPLACE FUNCTION options_add_fld(userId int, options JSONB, fldName text, jsonObj jsonb)
+    RETURNS JSON
+    LANGUAGE plpgsql
+AS
+$function$
+
+DECLARE
+BEGIN
+    return jsonb_set(options, string_to_array(fldName, ''), coalesce(options -> fldName, '[]'::jsonb) ||
+                                                            (jsonObj || jsonb_build_object('user_id', userId, 'date', now() at time zone 'Europe/Moscow')));
+END
+$function$;
+
+-- количество дней, которое надо прибавить чтобы получить следующий рабочий день
+DROP FUNCTION IF EXISTS next_business_day(timestamp);
+CREATE OR REPLACE FUNCTION next_business_day(timestamp)
+    RETURNS interval
+    LANGUAGE plpgsql
+AS
+$function$
+DECLARE
+    weekday integer;
+BEGIN
+    weekday := extract(dow from $1);
+    IF weekday = 0 THEN
+        return format('%s days', 2);
+    ELSIF weekday = 6 THEN
+        return format('%s days', 3);
+    ELSE
+        return format('%s days', 1);
+    END IF;
+END;
+$function$;
+
+DROP FUNCTION IF EXISTS add_business_day(from_date date, num_days int);
+create or replace function add_business_day(from_date date, num_days int)
+    returns date
+as $function$
+select d
+from (
+         select d::date, row_number() over (order by d)
+         from generate_series(from_date+ 1, from_date+ num_days* 2+ 5, '1d') d
+         where
+                 extract('dow' from d) not in (0, 6)
+     ) s
+where row_number = num_days
+$function$ language sql;
+
+-- запрос для полнотекстового поиска (DocSql.IsFullTextSearch). Строку разбирает websearch_to_tsquery (слова через AND,
+-- "фраза", or, -слово), затем к каждому слову добавляется поиск по началу (:*), чтобы поиск работал при вводе части слова.
+-- Текст запроса экранирует сам postgres, поэтому спецсимволы во вводе не ломают запрос. Для пустой строки возвращает null
+DROP FUNCTION IF EXISTS search_tsquery(config regconfig, txt text);
+CREATE OR REPLACE FUNCTION search_tsquery(config regconfig, txt text)
+    RETURNS tsquery
+    LANGUAGE sql
+    IMMUTABLE
+AS
+$function$
+    SELECT CASE WHEN numnode(q) > 0 THEN regexp_replace(q::text, '''(\s|$)', ''':*\1', 'g')::tsquery END
+    FROM websearch_to_tsquery(config, coalesce(txt, '')) q;
+$function$;
+
+-- проверка, что пользователь имеет одну из ролей
+DROP FUNCTION IF EXISTS is_user_role(userId int, roles text[]);
+CREATE OR REPLACE FUNCTION is_user_role(userId int, roles text[])
+    RETURNS bool
+    LANGUAGE plpgsql
+AS
+$function$
+DECLARE
+BEGIN
+    return (select  EXISTS (SELECT 1 FROM "user" where id=userId AND role && roles));
+END;
+$function$;
+
+-- поля документа, недоступные пользователю по ролям (FldType.Roles). fldRoles - поле: роли, например {"inn": ["manager"]}
+-- admin и вызовы без пользователя (user_id пустой или -1) имеют доступ ко всем полям
+DROP FUNCTION IF EXISTS user_denied_flds(userId int, fldRoles jsonb);
+CREATE OR REPLACE FUNCTION user_denied_flds(userId int, fldRoles jsonb)
+    RETURNS text[]
+    LANGUAGE sql
+    STABLE
+AS
+$function$
+    SELECT coalesce(array_agg(f.key), '{}')
+    FROM jsonb_each(fldRoles) f
+    WHERE userId IS NOT NULL AND userId != -1
+      AND NOT is_user_role(userId, ARRAY ['admin'] || ARRAY(SELECT jsonb_array_elements_text(f.value)));
+$function$;
+
+-- удаление из записи полей, скрытых по ролям. denied - поля из user_denied_flds, fldKeys - поле: ключи записи, которые вычисляются
+-- из него, например {"manager_id": ["manager_title", "options.title.manager_title"]}. Ключ через точку - путь внутри jsonb
+DROP FUNCTION IF EXISTS jsonb_hide_flds(doc jsonb, denied text[], fldKeys jsonb);
+CREATE OR REPLACE FUNCTION jsonb_hide_flds(doc jsonb, denied text[], fldKeys jsonb)
+    RETURNS jsonb
+    LANGUAGE plpgsql
+    IMMUTABLE
+AS
+$function$
+DECLARE
+    k    text;
+    path text[];
+BEGIN
+    FOREACH k IN ARRAY denied || ARRAY(SELECT jsonb_array_elements_text(fldKeys -> f) FROM unnest(denied) f)
+        LOOP
+            path = string_to_array(k, '.');
+            -- путь удаляется только внутри объекта (например, в истории options хранится строкой)
+            IF jsonb_typeof(doc #> path[1:cardinality(path) - 1]) = 'object'
+            THEN
+                doc = doc #- path;
+            END IF;
+        END LOOP;
+    RETURN doc;
+END;
+$function$;
+
+-- проверка, что пользователь имеет одну из ролей
+DROP FUNCTION IF EXISTS is_admin(params jsonb);
+CREATE OR REPLACE FUNCTION is_admin(params jsonb)
+    RETURNS bool
+    LANGUAGE plpgsql
+AS
+$function$
+DECLARE
+    userId int;
+BEGIN
+    userId = (params->>'user_id');
+    if userId isnull then
+        raise exception 'is_admin missed user_id params';
+    end if;
+    return (select  EXISTS (SELECT 1 FROM "user" where id=userId AND role && '{admin}'::text[]));
+END;
+$function$;
+
+-- отправка сообщение пользователю в телеграм
+DROP FUNCTION IF EXISTS send_msg_to_user_telegram(userId int, msg text);
+CREATE OR REPLACE FUNCTION send_msg_to_user_telegram(userId int, msg text)
+    RETURNS void
+    LANGUAGE plpgsql
+AS
+$function$
+DECLARE
+    tgId text;
+BEGIN
+    select options->>'telegram_id' into tgId from "user" where id=userId;
+    if tgId notnull then
+        PERFORM pg_notify('events', jsonb_build_object('table', 'send_msg_to_user_telegram', 'telegram_id', tgId, 'msg', msg):: TEXT);
+    end if;
+END;
+$function$;
+
+-- отправка сообщение пользователю в телеграм
+DROP FUNCTION IF EXISTS phone_change_8_to_7(phone text);
+CREATE OR REPLACE FUNCTION phone_change_8_to_7(phone text)
+    RETURNS text
+    LANGUAGE plpgsql
+AS
+$function$
+BEGIN
+    phone = regexp_replace(phone, '[^0-9]+', '', 'g');
+    if starts_with(phone, '8') then
+        return '7' || substr(phone, 2);
+    end if;
+    return phone;
+END;
+$function$;
+
+
+
+
@@ -0,0 +1,67 @@
+// сгенерировано nla_framework по списку методов pgFuncList (webServer/apiCallPgFunc.go). Не редактировать
+import config from 'src/app/plugins/config'
+import type * as t from './types'
+
+// вызов метода через /api/call_pg_func. При ok = false - reject с текстом ошибки
+export function callPgMethod<T = any>(method: string, params: Record<string, any> = {}): Promise<T> {
+  const headers: Record<string, string> = {'Content-Type': 'application/json'}
+  const authToken = localStorage.getItem(config.appName)
+  if (authToken) headers['Auth-token'] = authToken
+  return fetch(`${config.apiUrl()}/api/call_pg_func`, {method: 'POST', headers, body: JSON.stringify({method, params})})
+    .then(res => res.json())
+    .then(res => res.ok ? res.result as T : Promise.reject(new Error(res.message)))
+}
+
+// вызов метода *_list вместе с meta_info (with_total, cursor)
+export function callPgListMethod<T = any>(method: string, params: t.ListParams = {}): Promise<{result: T[], meta_info: t.ListMetaInfo}> {
+  const headers: Record<string, string> = {'Content-Type': 'application/json'}
+  const authToken = localStorage.getItem(config.appName)
+  if (authToken) headers['Auth-token'] = authToken
+  return fetch(`${config.apiUrl()}/api/call_pg_func`, {method: 'POST', headers, body: JSON.stringify({method, params})})
+    .then(res => res.json())
+    .then(res => res.ok ? {result: res.result || [], meta_info: res.meta_info || {}} : Promise.reject(new Error(res.message)))
+}
+
+export const userUpdate = (params: Record<string, any> = {}): Promise<any> => callPgMethod('user_update', params)
+
+export const userList = (params: Record<string, any> = {}): Promise<any> => callPgMethod('user_list', params)
+
+export const userGetById = (params: Record<string, any> = {}): Promise<any> => callPgMethod('user_get_by_id', params)
+
+export const userGetByIdForUi = (params: Record<string, any> = {}): Promise<any> => callPgMethod('user_get_by_id_for_ui', params)
+
+export const currentUserUpdate = (params: Record<string, any> = {}): Promise<any> => callPgMethod('current_user_update', params)
+
+export const currentUserGetAuthProviders = (params: Record<string, any> = {}): Promise<any> => callPgMethod('current_user_get_auth_providers', params)
+
+export const cityGetById = (params: {id: number}): Promise<t.City> => callPgMethod('city_get_by_id', params)
+
+export const cityList = (params: t.CityListParams = {}): Promise<t.City[]> => callPgMethod('city_list', params)
+
+export const cityUpdate = (params: t.CityUpdateParams): Promise<t.City> => callPgMethod('city_update', params)
+
+export const clientGetById = (params: {id: number}): Promise<t.Client> => callPgMethod('client_get_by_id', params)
+
+export const clientHistoryList = (params: {id: number, page?: number, per_page?: number}): Promise<t.DocHistoryItem[]> => callPgMethod('client_history_list', params)
+
+export const clientList = (params: t.ClientListParams = {}): Promise<t.Client[]> => callPgMethod('client_list', params)
+
+export const clientTagsList = (params: Record<string, any> = {}): Promise<any> => callPgMethod('client_tags_list', params)
+
+export const clientUpdate = (params: t.ClientUpdateParams): Promise<t.Client> => callPgMethod('client_update', params)
+
+export const clientUserLinkGetById = (params: {id: number}): Promise<t.ClientUserLink> => callPgMethod('client_user_link_get_by_id', params)
+
+export const clientUserLinkList = (params: t.ClientUserLinkListParams = {}): Promise<t.ClientUserLink[]> => callPgMethod('client_user_link_list', params)
+
+export const clientUserLinkUpdate = (params: t.ClientUserLinkUpdateParams): Promise<t.ClientUserLink> => callPgMethod('client_user_link_update', params)
+
+export const dealAction = (params: t.DealActionParams): Promise<t.Deal> => callPgMethod('deal_action', params)
+
+export const dealCreate = (params: t.DealUpdateParams): Promise<t.Deal> => callPgMethod('deal_create', params)
+
+export const dealGetById = (params: {id: number}): Promise<t.Deal> => callPgMethod('deal_get_by_id', params)
+
+export const dealList = (params: t.DealListParams = {}): Promise<t.Deal[]> => callPgMethod('deal_list', params)
+
+export const dealUpdate = (params: t.DealUpdateParams): Promise<t.Deal> => callPgMethod('deal_update', params)
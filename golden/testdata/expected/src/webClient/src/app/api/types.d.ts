@@ -0,0 +1,196 @@
+// сгенерировано nla_framework по описанию документов. Не редактировать
+
+// общие параметры методов *_list
+export interface ListParams {
+  deleted?: boolean
+  order_by?: string
+  page?: number
+  per_page?: number
+  search_text?: string
+  with_total?: boolean // общее количество записей по фильтру в meta_info.total
+  cursor?: ListCursor | null // курсорная пагинация: null - первая страница, далее meta_info.next_cursor
+  is_default_order?: boolean // order_by не выбран пользователем: при search_text сортировка по релевантности
+}
+
+// позиция последней записи страницы для курсорной пагинации
+export interface ListCursor {
+  value: any
+  id: number
+}
+
+// meta_info ответа методов *_list
+export interface ListMetaInfo {
+  total?: number
+  next_cursor?: ListCursor | null // null - записей больше нет
+}
+
+// запись истории изменений документа (методы *_history_list)
+export interface DocHistoryItem {
+  id: number
+  operation: 'INSERT' | 'UPDATE' | 'DELETE'
+  old_values: Record<string, any> | null // прежние значения измененных полей
+  new_values: Record<string, any> | null // новые значения измененных полей
+  created_at: string
+  user_id: number | null
+  user_fullname: string | null
+}
+
+// город
+export interface City {
+  id: number
+  title: string // название
+  parent_id?: number // родитель
+  parent_title?: string
+  is_folder?: boolean // признак, что является группой
+  options?: Record<string, any>
+  created_at: string
+  updated_at: string
+  deleted: boolean
+}
+
+export interface CityListParams extends ListParams {
+  parent_id?: number
+}
+
+export interface CityUpdateParams {
+  id: number // -1 - создание новой записи
+  title?: string
+  parent_id?: number
+  is_folder?: boolean
+}
+
+export type ClientStatus = 'new' | 'old'
+
+export type ClientChannels = 'email' | 'phone'
+
+export type ClientKind = 'legal' | 'person'
+
+// клиент
+export interface Client {
+  id: number
+  title: string // название
+  inn?: string // ИНН
+  note?: string // примечание
+  city_id?: number // город
+  city_title?: string
+  status?: ClientStatus // статус
+  channels?: ClientChannels[] // каналы
+  kind?: ClientKind // вид
+  birth_date?: string // дата рождения
+  last_visit?: string // последний визит
+  is_vip?: boolean // vip
+  phone?: string // телефон
+  email?: string // email
+  cnt?: number // количество
+  external_id?: number // внешний id
+  amount?: number // сумма
+  guid?: string // guid
+  tags?: string[] // тэги
+  address?: any // адрес
+  contacts?: any // контакты
+  docs?: any // документы
+  avatar?: string // аватар
+  photos?: any // фото
+  extra?: any // дополнительно
+  options?: Record<string, any>
+  meta_info?: {rank: number, snippet: string} // в list при полнотекстовом поиске по search_text
+  created_at: string
+  updated_at: string
+  deleted: boolean
+}
+
+export interface ClientListParams extends ListParams {
+  inn?: string
+  city_id?: number
+  birth_date_between?: [string | null, string | null]
+  amount_gte?: number
+  amount_lte?: number
+  status_in?: ClientStatus[]
+  kind_not_in?: ClientKind[]
+  phone_is_null?: boolean
+  city_id_in?: number[]
+  tags_contains?: string | string[]
+}
+
+export interface ClientUpdateParams {
+  id: number // -1 - создание новой записи
+  title?: string
+  inn?: string
+  note?: string
+  city_id?: number
+  status?: ClientStatus
+  channels?: ClientChannels[]
+  kind?: ClientKind
+  birth_date?: string
+  last_visit?: string
+  is_vip?: boolean
+  phone?: string
+  email?: string
+  cnt?: number
+  external_id?: number
+  amount?: number
+  guid?: string
+  tags?: string[]
+  address?: any
+  contacts?: any
+  docs?: any
+  avatar?: string
+  photos?: any
+  extra?: any
+}
+
+// менеджеры клиента
+export interface ClientUserLink {
+  id: number
+  client_id: number // клиент
+  client_title?: string
+  manager_id: number // менеджер
+  manager_title?: string
+  options?: Record<string, any>
+  created_at: string
+  updated_at: string
+  deleted: boolean
+}
+
+export interface ClientUserLinkListParams extends ListParams {
+  client_id?: number
+  manager_id?: number
+}
+
+export interface ClientUserLinkUpdateParams {
+  id: number // -1 - создание новой записи
+  client_id?: number
+  manager_id?: number
+}
+
+// сделка
+export interface Deal {
+  id: number
+  title: string // название
+  client_id?: number // клиент
+  client_title?: string
+  state?: string // статус
+  sum?: number // сумма
+  options?: Record<string, any>
+  created_at: string
+  updated_at: string
+  deleted: boolean
+}
+
+export interface DealListParams extends ListParams {
+  client_id?: number
+}
+
+export interface DealUpdateParams {
+  id: number // -1 - создание новой записи
+  title?: string
+  client_id?: number
+  state?: string
+  sum?: number
+}
+
+export interface DealActionParams {
+  id: number
+  action_name: 'draft_to_in_work' | 'draft_to_canceled' | 'in_work_to_done'
+  sum?: number
+}
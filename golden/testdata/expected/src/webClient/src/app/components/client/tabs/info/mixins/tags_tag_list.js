@@ -0,0 +1,38 @@
+export default {
+    data () {
+        return {
+            tagsFilterOptions: [],
+            tagsOptions: [],
+        }
+    },
+    methods: {
+        tagsCreateValue (val, done) {
+            if (val.length > 0) {
+                if (!this.tagsOptions.includes(val)) {
+                    this.tagsOptions.push(val)
+                }
+                done(val, 'toggle')
+            }
+        },
+        tagsFilterFn (val, update) {
+            update(() => {
+                if (val === '') {
+                    this.tagsFilterOptions = this.tagsOptions
+                }
+                else {
+                    const needle = val.toLowerCase()
+                    this.tagsFilterOptions = this.tagsOptions.filter(
+                        v => v.toLowerCase().indexOf(needle) > -1
+                    )
+                }
+            })
+        }
+    },
+    mounted() {
+        this.$utils.postCallPgMethod({method: 'client_tags_list', params: {}}).subscribe(res => {
+            if (res.ok) {
+                this.tagsOptions = res.result
+            }
+        })
+    },
+}
@@ -0,0 +1,20 @@
+export default {
+  appName: 'golden',
+  uiAppName: '',
+  apiUrl: () => process.env.NODE_ENV === 'development' ? 'http://localhost:3081' : 'https://golden.ru',
+  wsUrl: () => process.env.NODE_ENV === 'development' ? 'ws://localhost:3081' : 'wss://golden.ru',
+  isEmailAuth: {
+    firstName: true,
+    lastName: true,
+  },
+  logoSrc: '',
+  dadataToken: '1cf3a086e3dbe1306ed142d2b5fbc1b324b8eb60',
+  // yandexMetrikaId: 54433825,
+  breadcrumbIcons: {
+    // codeGenerate slot:breadcrumbIcons
+    city: 'location_city',
+  },
+  // codeGenerate slot:pluginsConfig
+  telegram: {botName: 'goldenBot', token: '123:token'},
+  tablesForTask: {"client":"клиент"},
+}
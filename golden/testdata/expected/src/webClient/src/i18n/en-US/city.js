@@ -0,0 +1,9 @@
+
+export default {
+		is_folder: 'is folder',
+		name: 'city',
+		name_plural: 'city',
+		name_plural_deleted: 'deleted city',
+		parent_id: 'parent ',
+		title: 'title',
+}
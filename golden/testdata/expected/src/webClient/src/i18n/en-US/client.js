@@ -0,0 +1,29 @@
+
+export default {
+		address: 'address',
+		amount: 'amount',
+		avatar: 'avatar',
+		birth_date: 'birth date',
+		channels: 'channels',
+		city_id: 'city ',
+		cnt: 'cnt',
+		contacts: 'contacts',
+		docs: 'docs',
+		email: 'email',
+		external_id: 'external ',
+		extra: 'extra',
+		guid: 'gu',
+		inn: 'inn',
+		is_vip: 'is vip',
+		kind: 'kind',
+		last_visit: 'last visit',
+		name: 'client',
+		name_plural: 'client',
+		name_plural_deleted: 'deleted client',
+		note: 'note',
+		phone: 'phone',
+		photos: 'photos',
+		status: 'status',
+		tags: 'tags',
+		title: 'title',
+}
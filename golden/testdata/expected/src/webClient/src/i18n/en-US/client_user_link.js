@@ -0,0 +1,8 @@
+
+export default {
+		client_id: 'client ',
+		manager_id: 'manager ',
+		name: 'client user link',
+		name_plural: 'client user link',
+		name_plural_deleted: 'deleted client user link',
+}
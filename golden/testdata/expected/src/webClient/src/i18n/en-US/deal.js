@@ -0,0 +1,10 @@
+
+export default {
+		client_id: 'client ',
+		name: 'deal',
+		name_plural: 'deal',
+		name_plural_deleted: 'deleted deal',
+		state: 'state',
+		sum: 'sum',
+		title: 'title',
+}
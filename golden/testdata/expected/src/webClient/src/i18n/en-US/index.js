@@ -0,0 +1,92 @@
+import city from './city'
+import client from './client'
+import client_user_link from './client_user_link'
+import deal from './deal'
+
+export default {
+	auth: {
+		authorization: 'authorization',
+		check_user_email_message: 'The link is no longer active. Possible reasons: <br/> <br/> - Your email has already been successfully confirmed - try to enter the system with your username and password <br/> - The email address has expired. Need to register again',
+		forgot_password: 'forgot password',
+		invalid_email: '"email" field is filled incorrectly',
+		invalid_first_name: 'you must fill in the "first name" field',
+		invalid_last_name: 'you must fill in the "last name" field',
+		invalid_password_must_be_more_7: 'password must be more than 7 characters',
+		invalid_password_wrong_repeat: 're-entered password does not match the first option',
+		invalid_user_already_exist: 'user with this email is already registered',
+		login: 'login',
+		password: 'password',
+		password_recovery: 'password recovery',
+		password_recovery_go_to_login: 'go to authorization',
+		password_recovery_message: 'Check the email specified in the registration. A letter should come with a link for password recovery.',
+		password_recovery_success_message: 'password changed successfully',
+		password_repeat: 'repeat password',
+		register: 'register',
+		register_message: 'Check the email specified in the registration. A letter should come to confirm the mailing address.',
+		registration: 'registration',
+		user_not_found: 'user not found',
+		wrong_password: 'wrong password',
+	},
+	menu: {
+		city: 'city',
+		client: 'client',
+		client_user_link: 'client user link',
+		deal: 'deal',
+		user: 'users',
+	},
+	message: {
+		add_link: 'add link',
+		add_photo: 'add photo',
+		cancel: 'cancel',
+		created_at: 'date',
+		delete: 'delete',
+		dialog_delete_title: 'Confirm',
+		edit: 'edit',
+		file: 'file',
+		files: 'files',
+		filter_active: 'active',
+		filter_deleted: 'deleted',
+		fld_not_filled: 'field not_filled',
+		ok: 'ok',
+		only_files_no_larger_than: 'only files no larger than',
+		only_files_with_extension: 'only files with the extension',
+		photo: 'photo',
+		restore: 'restore',
+		save: 'save',
+		saved: 'saved',
+		select_file_for_upload: 'select file for upload',
+		send: 'send',
+		title: 'title',
+		upload_error: 'upload error',
+		upload_photo: 'upload photo',
+	},
+	profile: {
+		avatar: 'photo',
+		breadcrumb_label: 'Profile edit',
+		exit: 'exit',
+		first_name: 'first name',
+		last_name: 'last name',
+		phone: 'phone',
+	},
+	user: {
+		email: 'email',
+		first_name: 'first name',
+		grade: 'grade',
+		last_name: 'last name',
+		name_plural: 'users',
+		name_plural_deleted: 'deleted users',
+		phone: 'phone',
+		photo: 'photo',
+		role_admin: 'admin',
+		role_student: 'user',
+		roles: 'roles',
+		state: 'state',
+		state_fired: 'fired',
+		state_waiting_auth: 'waiting auth',
+		state_working: 'working',
+	},
+	city,
+	client,
+	client_user_link,
+	deal,
+}
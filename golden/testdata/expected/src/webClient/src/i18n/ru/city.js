@@ -0,0 +1,9 @@
+
+export default {
+		is_folder: 'признак, что является группой',
+		name: 'город',
+		name_plural: 'города',
+		name_plural_deleted: 'удаленные города',
+		parent_id: 'родитель',
+		title: 'название',
+}
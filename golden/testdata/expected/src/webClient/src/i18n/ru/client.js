@@ -0,0 +1,29 @@
+
+export default {
+		address: 'адрес',
+		amount: 'сумма',
+		avatar: 'аватар',
+		birth_date: 'дата рождения',
+		channels: 'каналы',
+		city_id: 'город',
+		cnt: 'количество',
+		contacts: 'контакты',
+		docs: 'документы',
+		email: 'email',
+		external_id: 'внешний id',
+		extra: 'дополнительно',
+		guid: 'guid',
+		inn: 'ИНН',
+		is_vip: 'vip',
+		kind: 'вид',
+		last_visit: 'последний визит',
+		name: 'клиент',
+		name_plural: 'клиенты',
+		name_plural_deleted: 'удаленные клиенты',
+		note: 'примечание',
+		phone: 'телефон',
+		photos: 'фото',
+		status: 'статус',
+		tags: 'тэги',
+		title: 'название',
+}
@@ -0,0 +1,8 @@
+
+export default {
+		client_id: 'клиент',
+		manager_id: 'менеджер',
+		name: 'менеджеры клиента',
+		name_plural: 'Менеджеры клиента',
+		name_plural_deleted: 'удаленные Менеджеры клиента',
+}
@@ -0,0 +1,10 @@
+
+export default {
+		client_id: 'клиент',
+		name: 'сделка',
+		name_plural: 'сделки',
+		name_plural_deleted: 'удаленные сделки',
+		state: 'статус',
+		sum: 'сумма',
+		title: 'название',
+}
@@ -0,0 +1,92 @@
+import city from './city'
+import client from './client'
+import client_user_link from './client_user_link'
+import deal from './deal'
+
+export default {
+	auth: {
+		authorization: 'авторизация',
+		check_user_email_message: 'Ссылка уже неактивна. Возможные причины: <br/><br/>- Ваш email уже успешно подтвержден - попробуйте войти в систему с логином и паролем <br/>- Истекло время подтверждения почтового адреса. Нужно регистрироваться снова',
+		forgot_password: 'забыли пароль',
+		invalid_email: 'поле "email" заполнено неверно',
+		invalid_first_name: 'необходимо заполнить поле "имя"',
+		invalid_last_name: 'необходимо заполнить поле "фамилия"',
+		invalid_password_must_be_more_7: 'пароль должен быть больше 7 знаков',
+		invalid_password_wrong_repeat: 'повторно введенный пароль не совпадает с первым вариантом',
+		invalid_user_already_exist: 'пользователь с таким email уже зарегестрирован',
+		login: 'войти',
+		password: 'пароль',
+		password_recovery: 'восстановление пароля',
+		password_recovery_go_to_login: 'перейти к авторизации',
+		password_recovery_message: 'Проверьте указанный в регистрации email. Должно прийти письмо с ссылкой для восстановления пароля.',
+		password_recovery_success_message: 'пароль успешно изменен',
+		password_repeat: 'повторите пароль',
+		register: 'зарегистрироваться',
+		register_message: 'Проверьте указанный в регистрации email. Должно прийти письмо для подтверждения почтового адреса.',
+		registration: 'регистрация',
+		user_not_found: 'пользователь с таким email не найден',
+		wrong_password: 'неверный пароль',
+	},
+	menu: {
+		city: 'города',
+		client: 'клиенты',
+		client_user_link: 'Менеджеры клиента',
+		deal: 'сделки',
+		user: 'пользователи',
+	},
+	message: {
+		add_link: 'добавить ссылку',
+		add_photo: 'добавить фото',
+		cancel: 'отмена',
+		created_at: 'дата',
+		delete: 'удалить',
+		dialog_delete_title: 'Подтвердите',
+		edit: 'редактировать',
+		file: 'файл',
+		files: 'файлы',
+		filter_active: 'активные',
+		filter_deleted: 'удаленные',
+		fld_not_filled: 'не заполнено поле',
+		ok: 'ok',
+		only_files_no_larger_than: 'допустимы только файлы не больше',
+		only_files_with_extension: 'допустимы только файлы с раширением',
+		photo: 'фото',
+		restore: 'восстановить',
+		save: 'сохранить',
+		saved: 'изменения сохранены',
+		select_file_for_upload: 'Выберите файл для загрузки',
+		send: 'отправить',
+		title: 'название',
+		upload_error: 'ошибка загрузки',
+		upload_photo: 'загрузить фото',
+	},
+	profile: {
+		avatar: 'фото',
+		breadcrumb_label: 'Редактирование профиля',
+		exit: 'выйти',
+		first_name: 'имя',
+		last_name: 'фамилия',
+		phone: 'телефон',
+	},
+	user: {
+		email: 'email',
+		first_name: 'имя',
+		grade: 'должность',
+		last_name: 'фамилия',
+		name_plural: 'пользователи',
+		name_plural_deleted: 'удаленные пользователи',
+		phone: 'телефон',
+		photo: 'фото',
+		role_admin: 'админ',
+		role_student: 'сотрудник',
+		roles: 'роли',
+		state: 'статус',
+		state_fired: 'уволен',
+		state_waiting_auth: 'ожидает авторизации',
+		state_working: 'работает',
+	},
+	city,
+	client,
+	client_user_link,
+	deal,
+}
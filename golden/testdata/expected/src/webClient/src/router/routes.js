@@ -0,0 +1,31 @@
+const routes = [
+  {path: '/', component: () => import(`../app/components/home.vue`)},
+  {
+    path: '/check_user_email',
+    component: () => import(`../app/components/auth/email/checkUserEmail.vue`),
+  },
+  {
+    path: '/email_auth_recover_password',
+    component: () => import(`../app/components/auth/email/emailAuthRecoverPassword.vue`),
+  },
+  {path: '/users', component: () => import(`../app/components/users/index.vue`)},
+  {path: '/users/:id', component: () => import(`../app/components/users/item.vue`), props: true},
+  {path: '/profile', component: () => import(`../app/components/currentUser/profile.vue`)},
+  // codeGenerate slot:routes
+  {path: '/city', component: () => import(`../app/components/city/index.vue`), props: true},
+  {path: '/city/:id', component: () => import(`../app/components/city/item.vue`), props: true},
+  {path: '/city/:parent_id/:id', component: () => import(`../app/components/city/item.vue`), props: true},
+  {path: '/client', component: () => import(`../app/components/client/index.vue`), props: true},
+  {path: '/client/:id', component: () => import(`../app/components/client/item.vue`), props: true},
+  {path: '/deal', component: () => import(`../app/components/deal/index.vue`), props: true},
+  {path: '/deal/:id', component: () => import(`../app/components/deal/item.vue`), props: true},
+  // Always leave this as last one,
+  // but you can also remove it
+  {
+    path: '/:catchAll(.*)*',
+    component: () => import('../app/components/error404.vue')
+  }
+]
+
+
+export default routes
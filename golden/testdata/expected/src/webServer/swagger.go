@@ -0,0 +1,37 @@
+package webServer
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// страница Swagger UI для docs/openapi.json. Подключается только в режиме разработки (см. StartWebServer)
+const swaggerUiHtml = `<!DOCTYPE html>
+<html lang="ru">
+<head>
+    <meta charset="utf-8">
+    <title>API</title>
+    <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+<script>
+    window.ui = SwaggerUIBundle({
+        url: '/swagger/openapi.json',
+        dom_id: '#swagger-ui',
+        // токен берем из localStorage приложения, чтобы не вводить его вручную после входа
+        requestInterceptor: (req) => {
+            const token = localStorage.getItem('golden')
+            if (token && !req.headers['Auth-token']) req.headers['Auth-token'] = token
+            return req
+        },
+    })
+</script>
+</body>
+</html>`
+
+func swaggerUi(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerUiHtml))
+}
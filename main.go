@@ -1,22 +1,17 @@
 package nla_framework
 
 import (
-	"encoding/json"
-	"errors"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"os"
-	"path"
 	"path/filepath"
-	"runtime"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/NL-A/nla_framework/templates"
 	"github.com/NL-A/nla_framework/types"
 	"github.com/NL-A/nla_framework/utils"
-	"github.com/spf13/cast"
 	//"github.com/otiai10/copy"
 )
 
@@ -25,6 +20,15 @@ type (
 	copyFileModifyFunc func(path string, file []byte) []byte
 )
 
+// команды утилиты nla. Для проектов, описанных в go коде, утилита запускает 'go run .' и передает команду через переменную окружения
+const (
+	CommandEnv        = "NLA_COMMAND"
+	ForceOverwriteEnv = "NLA_FORCE_OVERWRITE"
+	CommandGenerate   = "generate"
+	CommandValidate   = "validate"
+	CommandDiff       = "diff"
+)
+
 var (
 	project types.ProjectType
 	tmplMap map[string]*template.Template
@@ -40,7 +44,13 @@ func readData(p types.ProjectType) {
 	}
 	// передаем project в папку types, чтобы иметь доступ из функций шаблонов к проекту
 	templates.SetProject(&project)
-	project.DistPath = "../src"
+	// корень генерируемого проекта
+	if len(project.Generator.OutputRoot) == 0 {
+		project.Generator.OutputRoot = types.DefaultOutputRoot
+	}
+	project.DistPath = project.Generator.OutputRoot + "/src"
+	// go код и sql методы плагинов генератора (интеграции с Битрикс, 1С, Telegram и пр)
+	project.ApplyPlugins()
 	project.FillDocTemplatesFields()
 	project.GenerateGrid()
 	project.FillVueFlds()
@@ -52,46 +62,38 @@ func readData(p types.ProjectType) {
 		// проставляем дефолтное время сервера, если не задано в настройках проекта
 		project.Config.Postgres.TimeZone = "Europe/Moscow"
 	}
-	// проверяем что название проекта без пробелов
-	if strings.Contains(p.Name, " ") {
-		utils.CheckErr(errors.New(fmt.Sprintf("Wrong project name: '%s'. Remove spaces.", p.Name)), "")
-	}
-	// проверки правил для fld
-	for _, d := range p.Docs {
-		for _, fld := range d.Flds {
-			// проверяем чтобы не было поля user_id, потому что это служебное поле
-			if fld.Name == "user_id" {
-				utils.CheckErr(errors.New("field with name 'user_id' is not allowed. Rename field. "), "doc: "+d.Name)
-			}
-			for _, v := range fld.Vue.Options {
-				if strings.Contains(cast.ToString(v.Value), " ") {
-					utils.CheckErr(errors.New(fmt.Sprintf("field with name '%s' contains value '%s'. Remove spaces from value.", fld.Name, v.Value)), "doc: "+d.Name)
-				}
-			}
-		}
-	}
-	// проверяем что если авторизация через email, то должны быть заполнены необходимые поля
-	if p.Config.Auth.ByEmail {
-		if len(p.Config.Email.Sender) == 0 || len(p.Config.Email.Host) == 0 || p.Config.Email.Port == 0 {
-			utils.CheckErr(errors.New("in Config.Email fill fields: 'Sender', 'Host', 'Port'"), "")
-		}
-	}
-	// проверка что если документ - это уникальная связь двух таблиц, то в нем поле title если есть, то не должно быть уникальным
-	for _, d := range p.Docs {
-		if d.Sql.IsUniqLink {
-			for _, fld := range d.Flds {
-				if fld.Name == "title" && fld.Sql.IsUniq {
-					utils.CheckErr(errors.New("field 'title' must be not uniq. Remove fld 'title' or t.GetFldTitle().SetIsNotUniq()"), "doc: "+d.Name)
-				}
-			}
-		}
-	}
-
 	// передаем project в папку types, чтобы иметь доступ из функций шаблонов к проекту
 	types.SetProject(&project)
 }
 
+// Start генерация проекта. Если проект запущен через утилиту nla (cmd/nla), то команда передается в переменной окружения CommandEnv:
+// generate (по умолчанию), validate, diff или watch
 func Start(p types.ProjectType, modifyFunc copyFileModifyFunc) {
+	if len(os.Getenv(ForceOverwriteEnv)) > 0 {
+		p.Generator.IsForceOverwrite = true
+	}
+	switch os.Getenv(CommandEnv) {
+	case CommandValidate:
+		if !Validate(p) {
+			os.Exit(1)
+		}
+	case CommandDiff:
+		StartDryRun(p, modifyFunc)
+	case "", CommandGenerate:
+		generate(p, modifyFunc)
+	case CommandWatch:
+		// генерируем измененные документы и ждем следующего изменения. Описание проекта в go коде надо перекомпилировать,
+		// поэтому проект завершается, а утилита nla запускает его заново
+		err := watchOnce(p, modifyFunc, true)
+		utils.CheckErr(err, "watch")
+		os.Exit(WatchRestartExitCode)
+	default:
+		log.Fatalf("unknown %s: '%s'", CommandEnv, os.Getenv(CommandEnv))
+	}
+}
+
+// дефолтные настройки проекта, которые проставляются перед проверкой и генерацией
+func setDefaults(p *types.ProjectType) {
 	// проставляем дефолтную авторизацию по email
 	if !p.Config.Auth.ByPhone {
 		p.Config.Auth.ByEmail = true
@@ -118,217 +120,192 @@ func Start(p types.ProjectType, modifyFunc copyFileModifyFunc) {
 	//		p.Config.Backup.ToYandexDisk.PostgresDockerName = p.Config.Postgres.DbName
 	//	}
 	//}
+}
+
+// генерация всех файлов проекта. Куда пишутся файлы (на диск или в память) определяется режимом utils.SetDryRun
+// Возвращает список удаленных файлов, которые больше не генерируются
+func generate(p types.ProjectType, modifyFunc copyFileModifyFunc) (removed []string) {
+	setDefaults(&p)
 
+	// проверяем описание проекта. В случае ошибок печатаем полный отчет и выходим
+	validateOrExit(p)
+
+	removed, _ = generateFiles(p, modifyFunc, false)
+	return
+}
+
+// генерация файлов по проверенному описанию проекта. Если isIncremental, то перегенерируются только документы,
+// описание или шаблоны которых изменились с прошлой генерации (см fingerprints.go), и зависящие от них файлы проекта
+func generateFiles(p types.ProjectType, modifyFunc copyFileModifyFunc, isIncremental bool) (removed []string, stat generateStat) {
+	start := time.Now()
 	// читаем данные для проекта
 	readData(p)
+	fingerprints := projectFingerprints(project)
+
+	// читаем манифест прошлой генерации
+	utils.SetOutput(project.Generator.Output)
+	err := utils.StartManifest(project.Generator.OutputRoot, project.Generator.IsForceOverwrite)
+	utils.CheckErr(err, "StartManifest")
+
+	// список документов для перегенерации. nil - генерируем весь проект
+	var changedDocs map[string]bool
+	if isIncremental {
+		changedDocs = changedDocNames(utils.ManifestFingerprints(), fingerprints)
+	}
+	stat.isFull = changedDocs == nil
+	if !stat.isFull {
+		if len(changedDocs) == 0 {
+			stat.total = time.Since(start)
+			return
+		}
+		utils.SetManifestIncremental()
+	}
+
 	// читаем темплейты
 	tmplMap = templates.ParseTemplates(project)
 
-	// удаляем старые файлы
-	removeOldFiles(project.DistPath)
+	// удаляем старые файлы. Если есть манифест, то устаревшие файлы удаляются по нему в конце генерации
+	if !utils.IsManifestExist() {
+		removeOldFiles(project.DistPath)
+	}
 
-	// генерим файлы для проекта
-	templates.WriteProjectFiles(project, tmplMap)
+	// генерим файлы для проекта. Локализация документов заполняется здесь же, поэтому до шаблонов документов
+	projectStart := time.Now()
+	if stat.isFull {
+		templates.WriteProjectFiles(project, tmplMap)
+	} else {
+		templates.WriteDocDependentFiles(project)
+	}
+	stat.project = time.Since(projectStart)
 
-	// генерим файлы для документов
-	for _, d := range p.Docs {
-		for _, dt := range d.Templates {
+	// генерим файлы для документов. Файлы документа записываются в манифест отдельной группой, чтобы при
+	// инкрементальной генерации удалять устаревшие файлы только перегенерированных документов
+	for _, d := range project.Docs {
+		if !stat.isFull && !changedDocs[d.Name] {
+			continue
+		}
+		docStart := time.Now()
+		utils.SetManifestGroup(d.Name)
+		for _, tName := range utils.SortedKeys(d.Templates) {
+			dt := d.Templates[tName]
 			err := templates.ExecuteToFile(dt.Tmpl, d, dt.DistPath, dt.DistFilename)
 			utils.CheckErr(err, fmt.Sprintf("'%s' ExecuteToFile '%s'", d.Name, dt.DistFilename))
 		}
+		stat.docs = append(stat.docs, docStat{name: d.Name, tmplCount: len(d.Templates), duration: time.Since(docStart)})
 	}
+	utils.SetManifestGroup("")
 
-	// копируем файлы проекта (которые не шаблоны)
-	err := copyFiles(project, getCurrentDir()+"/sourceFiles", "../", modifyFunc)
-	utils.CheckErr(err, "Copy sourceFiles")
-
-	// отдельно копируем webClient в зависимости от версии quasar-framework
-	err = copyFiles(project, fmt.Sprintf("%s/webClient/quasar_%v", getCurrentDir(), project.GetQuasarVersion()), "../src/", modifyFunc)
-	utils.CheckErr(err, "Copy sourceFiles")
+	projectStart = time.Now()
+	webClientSource := fmt.Sprintf("%s/webClient/quasar_%v", getCurrentDir(), project.GetQuasarVersion())
+	slotContent := project.SlotContent()
+	if stat.isFull {
+		// копируем файлы проекта (которые не шаблоны)
+		err = copyFiles(project, slotContent, getCurrentDir()+"/sourceFiles", project.Generator.OutputRoot+"/", modifyFunc)
+		utils.CheckErr(err, "Copy sourceFiles")
 
-	// в случае если quasar-framework v1 то копируем часть устаревших sql файлов. Для поддержания кода старых проектов
-	if p.GetQuasarVersion() == 1 {
-		err = copyFiles(project, getCurrentDir()+"/sourceFilesSQL_legacy", "../src/sql/", modifyFunc)
+		// отдельно копируем webClient в зависимости от версии quasar-framework
+		err = copyFiles(project, slotContent, webClientSource, project.DistPath+"/", modifyFunc)
 		utils.CheckErr(err, "Copy sourceFiles")
+
+		// в случае если quasar-framework v1 то копируем часть устаревших sql файлов. Для поддержания кода старых проектов
+		if project.GetQuasarVersion() == 1 {
+			err = copyFiles(project, slotContent, getCurrentDir()+"/sourceFilesSQL_legacy", project.DistPath+"/sql/", modifyFunc)
+			utils.CheckErr(err, "Copy sourceFiles")
+		}
+	} else {
+		// из копируемых файлов от документов зависят только роуты, боковое меню и config.js
+		for _, path := range []string{"/webClient/src/router/routes.js", "/webClient/src/app/components/sidemenu/index.vue", "/webClient/src/app/plugins/config.js"} {
+			err = copyFile(project, slotContent, webClientSource, webClientSource+path, project.DistPath+"/", modifyFunc)
+			utils.CheckErr(err, "Copy "+path)
+		}
 	}
 
 	templates.OtherTemplatesGenerate(project)
-}
+	stat.project += time.Since(projectStart)
 
-// функция для копирования файлов с возможностью модификаации содержимого файлов
-func copyFiles(p types.ProjectType, source, dist string, modifyFunc copyFileModifyFunc) (err error) {
-	err = filepath.Walk(source,
-		func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				return err
-			}
-			if !info.IsDir() {
-				file, err := ioutil.ReadFile(path)
-				if err != nil {
-					return err
-				}
-				// для windows заменяем слэши в пути на обратные
-				dirPath := strings.TrimSuffix(strings.TrimPrefix(strings.Replace(path, "\\", "/", -1), source), info.Name())
-				// создаем директории
-				err = os.MkdirAll(dist+dirPath, os.ModePerm)
-				if err != nil {
-					return err
-				}
-				// заменяем ссылки в go файлах
-				if strings.HasSuffix(info.Name(), ".go") {
-					file = []byte(strings.Replace(string(file), "github.com/NL-A/nla_framework", p.Config.LocalProjectPath, -1))
-				}
-				// изменение config.js
-				if strings.HasSuffix(path, "app"+string(os.PathSeparator)+"plugins"+string(os.PathSeparator)+"config.js") {
-					file = configJsModify(p, file)
-				}
-				// изменение sidemenu/index.vue
-				if strings.HasSuffix(path, "components"+string(os.PathSeparator)+"sidemenu"+string(os.PathSeparator)+"index.vue") {
-					file = []byte(strings.Replace(string(file), "// for codeGenerate ##sidemenu_slot1", sidemenuJsModify(), -1))
-				}
-				// изменение routes.js
-				if strings.HasSuffix(path, "src"+string(os.PathSeparator)+"router"+string(os.PathSeparator)+"routes.js") {
-					file = []byte(strings.Replace(string(file), "// for codeGenerate ##routes_slot1", routesJsModify(), -1))
-				}
-				// изменение _Task/main.toml - дописываем дополнительные методы
-				if strings.HasSuffix(path, "_Task"+string(os.PathSeparator)+"main.toml") {
-					insertText := "# for codeGenerate task_methods_slot"
-					if project.Sql.Methods != nil {
-						isMethodsExist := false
-						for _, v := range project.Sql.Methods["task"] {
-							isMethodsExist = true
-							insertText = fmt.Sprintf("%s\n\t\"%s\",", insertText, v.Name)
-						}
-						if isMethodsExist {
-							file = []byte(strings.Replace(string(file), "# for codeGenerate task_methods_slot", insertText, -1))
-						}
-					}
-				}
-				// изменение index.template.html
-				if strings.HasSuffix(path, "src"+string(os.PathSeparator)+"index.template.html") {
-					file = []byte(strings.Replace(string(file), "[[appName]]", p.Name, -1))
-				}
-				// изменение loginPage.vue и home.vue
-				if strings.HasSuffix(path, "loginPage.vue") || strings.HasSuffix(path, "home.vue") {
-					file = []byte(strings.Replace(string(file), "[[appLogoSrc]]", p.Config.Logo, -1))
-				}
-				// проставляем Config.Postgres.TimeZone в sql файлах
-				if strings.HasSuffix(path, ".sql") {
-					file = []byte(strings.Replace(string(file), "[[Config.Postgres.TimeZone]]", p.Config.Postgres.TimeZone, -1))
-				}
-				// добавляем в триггер для задач дополнительные блоки
-				if strings.HasSuffix(path, "trigger_task_update_table_name.sql") {
-					insertText := "-- for codeGenerate #trigger_task_update_table_name_slot"
-					if project.Sql.Methods != nil {
-						isMethodsExist := false
-						for _, v := range project.Sql.Methods["task"] {
-							isMethodsExist = true
-							if txt, ok := v.Params["trigger_task_update_table_name.sql"]; ok {
-								insertText = fmt.Sprintf("%s\n%s", insertText, txt)
-							}
-						}
-						if isMethodsExist {
-							file = []byte(strings.Replace(string(file), "-- for codeGenerate #trigger_task_update_table_name_slot", insertText, -1))
-						}
-					}
-				}
-				// применяем модификатор для текста файла
-				if modifyFunc != nil {
-					file = modifyFunc(dirPath+info.Name(), file)
-				}
-				// если файл в директории webClient/.quasar/ уже существует, то не перезаписываем в любом случае
-				if strings.Contains(dist+dirPath+info.Name(), "webClient/.quasar/") {
-					return nil
-				}
-				// для оптимизации записи файлов webClient (чтобы ускорить рестарт quasar), проверяем что файл изменен и только в этом случае его перезаписываем
-				if strings.Contains(dist+dirPath+info.Name(), "webClient") {
-					if existFile, err := ioutil.ReadFile(dist + dirPath + info.Name()); err == nil {
-						isEqual := utils.ByteSliceEqual(existFile, file)
-						if isEqual {
-							return nil
-						}
-						//fmt.Printf("file changed: %s not equal\n", dist+dirPath+info.Name())
-					}
-				}
-				// записываем файл по новому пути
-				err = ioutil.WriteFile(dist+dirPath+info.Name(), file, 0644)
-				if err != nil {
-					return err
-				}
-			}
-			return nil
-		})
+	// удаляем файлы, которые больше не генерируются, и сохраняем манифест
+	utils.SetManifestFingerprints(fingerprints)
+	removed, err = utils.FinishManifest()
+	utils.CheckErr(err, "FinishManifest")
+	if !utils.IsDryRun() {
+		for _, f := range removed {
+			fmt.Printf("removed: %s\n", f)
+		}
+	}
+	for _, f := range utils.ManifestHandEditedFiles() {
+		fmt.Printf("WARNING: file '%s' was edited manually and is not overwritten. Use Generator.IsForceOverwrite to overwrite it\n", f)
+	}
+	stat.total = time.Since(start)
 	return
 }
 
-func removeOldFiles(distPath string) {
-	// удаляем модели в sql, потому что могла изменится нумерация файлов и тогда риск дублирования
-	err := os.RemoveAll(distPath + "/sql/model")
-	utils.CheckErr(err, "removeOldFiles")
+// функция для копирования файлов с возможностью модификаации содержимого файлов
+func copyFiles(p types.ProjectType, slotContent map[string][]string, source, dist string, modifyFunc copyFileModifyFunc) (err error) {
+	// файлы фреймворка читаются из встроенной файловой системы (см embed.go), остальные - с диска
+	files, err := utils.ListSourceFiles(source)
+	if err != nil {
+		return
+	}
+	for _, path := range files {
+		if err = copyFile(p, slotContent, source, path, dist, modifyFunc); err != nil {
+			return
+		}
+	}
+	return
 }
 
-func configJsModify(p types.ProjectType, file []byte) (res []byte) {
-	jsTablesForTask := func() string {
-		res := map[string]string{}
-		for _, d := range project.Docs {
-			if d.IsTaskAllowed {
-				res[d.Name] = d.NameRu
-			}
-		}
-		jsonStr, _ := json.Marshal(res)
-		return string(jsonStr)
+// копирование одного файла из source в dist с модификацией содержимого. path - полный путь к файлу внутри source.
+// slotContent - содержимое слотов (см types.ProjectType.SlotContent)
+func copyFile(p types.ProjectType, slotContent map[string][]string, source, path, dist string, modifyFunc copyFileModifyFunc) error {
+	file, err := utils.ReadSourceFile(path)
+	if err != nil {
+		return err
 	}
-	breadcrumbIcons := []string{}
-	for _, d := range p.Docs {
-		if len(d.Vue.BreadcrumbIcon) > 0 {
-			breadcrumbIcons = append(breadcrumbIcons, fmt.Sprintf("%s: '%s'", d.Name, d.Vue.BreadcrumbIcon))
-		}
+	name := filepath.Base(path)
+	dirPath := strings.TrimSuffix(strings.TrimPrefix(path, strings.Replace(source, "\\", "/", -1)), name)
+	// заменяем ссылки в go файлах
+	if strings.HasSuffix(name, ".go") {
+		file = []byte(strings.Replace(string(file), "github.com/NL-A/nla_framework", p.Config.LocalProjectPath, -1))
 	}
-	fileStr := string(file)
-	fileStr = strings.Replace(fileStr, "[[appName]]", p.Name, -1)
-	fileStr = strings.Replace(fileStr, "[[uiAppName]]", p.Vue.UiAppName, -1)
-	fileStr = strings.Replace(fileStr, "[[webPort]]", fmt.Sprintf("%v", p.Config.WebServer.Port), -1)
-	fileStr = strings.Replace(fileStr, "[[url]]", strings.TrimPrefix(p.Config.WebServer.Url, "https://"), -1)
-	// возможен вариант, что адрес в конфиге записан с http, тогда так его и оставляем. Иначе убираем префикс https://
-	if strings.HasPrefix(p.Config.WebServer.Url, "http") {
-		fileStr = strings.Replace(fileStr, "[[urlWithHttp]]", p.Config.WebServer.Url, -1)
-	} else {
-		if len(p.Config.WebServer.Url) > 0 {
-			fileStr = strings.Replace(fileStr, "[[urlWithHttp]]", "https://"+p.Config.WebServer.Url, -1)
-		} else {
-			fileStr = strings.Replace(fileStr, "[[urlWithHttp]]", "", -1)
-		}
+	// вставляем содержимое именованных слотов (см slots.go)
+	file, err = types.FillSlots(file, slotContent)
+	if err != nil {
+		return fmt.Errorf("%s: %s", path, err)
 	}
-	fileStr = strings.Replace(fileStr, "[[logoSrc]]", p.Config.Logo, -1)
-	fileStr = strings.Replace(fileStr, "[[dadataToken]]", p.Config.Vue.DadataToken, -1)
-	fileStr = strings.Replace(fileStr, "[[breadcrumbIcons]]", strings.Join(breadcrumbIcons, ",\n"), -1)
-	// проставляем список таблиц, к которым можно прикреплять задачи
-	fileStr = strings.Replace(fileStr, "[[codoGeneratedTablesForTask]]", jsTablesForTask(), -1)
-	fileStr = strings.Replace(fileStr, "[[telegramConfig]]", func() string {
-		if p.IsTelegramIntegration() {
-			return fmt.Sprintf("telegram: {botName: '%s', token: '%s'},", p.Config.Telegram.BotName, p.Config.Telegram.Token)
-		}
-		return ""
-	}(), -1)
-	return []byte(fileStr)
+	// применяем модификатор для текста файла
+	if modifyFunc != nil {
+		file = modifyFunc(dirPath+name, file)
+	}
+	// если файл в директории webClient/.quasar/ уже существует, то не перезаписываем в любом случае
+	if strings.Contains(dist+dirPath+name, "webClient/.quasar/") {
+		return nil
+	}
+	// записываем файл по новому пути. Неизмененные файлы не перезаписываются (см utils.WriteFile)
+	return utils.WriteFile(dist+dirPath+name, file)
+}
+
+func removeOldFiles(distPath string) {
+	// удаляем модели в sql, потому что могла изменится нумерация файлов и тогда риск дублирования
+	err := utils.RemoveAll(distPath + "/sql/model")
+	utils.CheckErr(err, "removeOldFiles")
 }
 
 // функция по добавлению routes
-func routesJsModify() string {
-	res := "// for codeGenerate ##routes_slot1"
-	for _, r := range project.Vue.Routes {
+func routesJsModify(p types.ProjectType) []string {
+	res := []string{}
+	for _, r := range p.Vue.Routes {
 		if len(r) < 2 {
 			log.Fatalf("routesJsModify project.Vue.Route route array %v length < 2", r)
 		}
-		res = fmt.Sprintf("%s\n\t{path: '/%s', component: () => import(`../app/components/%s`), props: true},", res, r[0], r[1])
+		res = append(res, fmt.Sprintf("{path: '/%s', component: () => import(`../app/components/%s`), props: true},", r[0], r[1]))
 		//{path: '/users/:id', component: () => import(`../app/components/users/item.vue`), props: true},
 	}
 	return res
 }
 
 // функция для построения бокового меню во Vue
-func sidemenuJsModify() string {
-	res := "// for codeGenerate ##sidemenu_slot1\n"
+func sidemenuJsModify(p types.ProjectType) []string {
+	res := []string{}
 	printMenuItem := func(m types.VueMenu) string {
 		roles := ""
 		if m.Roles != nil && len(m.Roles) > 0 {
@@ -338,32 +315,29 @@ func sidemenuJsModify() string {
 		if len(m.ConditionalFunc) > 0 {
 			resStr = fmt.Sprintf("%s, conditionalFunc: %s", resStr, m.ConditionalFunc)
 		}
-		return fmt.Sprintf("{%s},\n", resStr)
+		return fmt.Sprintf("{%s},", resStr)
 	}
-	for _, m := range project.Vue.Menu {
+	for _, m := range p.Vue.Menu {
 		if !m.IsFolder {
-			res = fmt.Sprintf("%s\n\t\t\t\t\t\t\t\t\t%s", res, printMenuItem(m))
+			res = append(res, printMenuItem(m))
 			// {icon: 'people', text: 'Пользователи', url: '/users', role: ['admin']},
 		} else {
-			linkList := "\t\t\t\t\t\t\t\t[\n"
+			linkList := "["
 			for _, m1 := range m.LinkList {
-				linkList = fmt.Sprintf("%s\t\t\t\t\t\t\t\t%s", linkList, printMenuItem(m1))
+				linkList = fmt.Sprintf("%s\n    %s", linkList, printMenuItem(m1))
 			}
-			linkList = linkList + "],"
+			linkList = linkList + "\n]"
 			roles := ""
 			if m.Roles != nil && len(m.Roles) > 0 {
 				roles = fmt.Sprintf(`'%s'`, strings.Join(m.Roles, `', '`))
 			}
-			res = fmt.Sprintf("%s{isFolder: true, icon: '%s', text: '%s', roles: [%s], linkList: %s},\n", res, m.Icon, m.Text, roles, linkList)
+			res = append(res, fmt.Sprintf("{isFolder: true, icon: '%s', text: '%s', roles: [%s], linkList: %s},", m.Icon, m.Text, roles, linkList))
 		}
 	}
 	return res
 }
 
+// корень фреймворка. Файлы фреймворка читаются из embed.FS (см embed.go)
 func getCurrentDir() string {
-	_, filename, _, ok := runtime.Caller(0)
-	if !ok {
-		log.Fatalf("ParseTemplates runtime.Caller: No caller information")
-	}
-	return path.Dir(filename)
+	return utils.FrameworkPathPrefix
 }
@@ -0,0 +1,221 @@
+package pgimport
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/NL-A/nla_framework/types"
+	"github.com/NL-A/nla_framework/utils"
+	"github.com/serenize/snaker"
+)
+
+// колонки, которые фреймворк создает в каждой таблице сам
+var frameworkColumns = map[string]bool{"id": true, "search_text": true, "search_vector": true, "options": true, "created_at": true, "updated_at": true, "deleted": true}
+
+// служебная таблица версий миграций - не документ
+const migrationsTable = "schema_migrations"
+
+// DocTables таблицы, по которым создаются документы: без служебных таблиц фреймворка.
+// only - ограничение списка таблиц (пусто - все). Порядок такой, что таблица, на которую ссылаются, идет раньше ссылающейся
+func (s *Schema) DocTables(only []string) []*Table {
+	isOnly := map[string]bool{}
+	for _, v := range only {
+		isOnly[v] = true
+	}
+	byName := map[string]*Table{}
+	names := []string{}
+	for _, t := range s.Tables {
+		if t.Name == migrationsTable || utils.CheckContainsSliceStr(t.Name, types.SystemTables...) || (len(only) > 0 && !isOnly[t.Name]) {
+			continue
+		}
+		byName[t.Name] = t
+		names = append(names, t.Name)
+	}
+	sort.Strings(names)
+	res := []*Table{}
+	visited := map[string]bool{}
+	var visit func(name string)
+	visit = func(name string) {
+		if visited[name] {
+			return
+		}
+		visited[name] = true
+		t := byName[name]
+		for _, fk := range t.Fks {
+			if _, ok := byName[fk.RefTable]; ok {
+				visit(fk.RefTable)
+			}
+		}
+		res = append(res, t)
+	}
+	for _, name := range names {
+		visit(name)
+	}
+	return res
+}
+
+// Package название go пакета документа - как у 'nla doc add'
+func (t *Table) Package() string {
+	return strings.ToLower(snaker.SnakeToCamelLower(t.Name))
+}
+
+// GenerateDoc go код описания документа по таблице. pathPrefix - DocType.PathPrefix
+func GenerateDoc(t *Table, pathPrefix string) ([]byte, error) {
+	nameRu := t.Comment
+	if len(nameRu) == 0 {
+		nameRu = t.Name
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "package %s\n\n", t.Package())
+	b.WriteString("import (\n\tt \"github.com/NL-A/nla_framework/types\"\n)\n\n")
+	fmt.Fprintf(&b, "const (\n\tname = %q\n\tnameRu = %q\n)\n\n", t.Name, nameRu)
+	b.WriteString("func GetDoc(p *t.ProjectType) t.DocType {\n\tdoc := t.DocType{\n\t\tProject: p,\n\t\tName: name,\n\t\tNameRu: nameRu,\n")
+	fmt.Fprintf(&b, "\t\tPathPrefix: %q,\n", pathPrefix)
+	b.WriteString("\t\tFlds: []t.FldType{\n")
+	// без title ссылки на документ не работают: list и get_by_id берут title записи, на которую ссылаются
+	if todo := titleTodo(t); len(todo) > 0 {
+		fmt.Fprintf(&b, "\t\t\t// TODO: %s\n", todo)
+	}
+	row := 1
+	for _, c := range t.Columns {
+		if frameworkColumns[c.Name] {
+			continue
+		}
+		if c.Name == "title" {
+			// title всегда в первой строке
+			fmt.Fprintf(&b, "\t\t\t%s,\n", titleFld(c))
+			continue
+		}
+		row++
+		fld, todo := columnFld(c, row)
+		if len(todo) > 0 {
+			fmt.Fprintf(&b, "\t\t\t// TODO: %s\n", todo)
+		}
+		fmt.Fprintf(&b, "\t\t\t%s,\n", fld)
+	}
+	b.WriteString("\t\t},\n\t\tIsBaseTemplates: t.DocIsBaseTemplates{Vue: true, Sql: true},\n\t\tTemplates: map[string]*t.DocTemplate{},\n\t}\n")
+	b.WriteString("\tdoc.Vue = t.DocVue{RouteName: name, MenuIcon: \"image/file.svg\", Roles: []string{}, I18n: map[string]string{\"listTitle\": nameRu}}\n")
+	b.WriteString("\tdoc.Sql.FillBaseMethods(doc.Name)\n")
+	for i, ch := range t.Checks {
+		name := ch.Name
+		if len(name) == 0 {
+			name = fmt.Sprintf("%s_check%v", t.Name, i+1)
+		}
+		fmt.Fprintf(&b, "\tdoc.Sql.CheckConstrains = append(doc.Sql.CheckConstrains, t.DocSqlCheckConstraint{Name: %q, CheckConditions: %q})\n", name, ch.Expr)
+	}
+	for _, u := range t.Uniques {
+		fmt.Fprintf(&b, "\tdoc.Sql.UniqConstrains = append(doc.Sql.UniqConstrains, t.DocSqlUniqConstraint{Name: %q, UniqConditions: %q})\n", u.Name, u.Expr)
+	}
+	b.WriteString("\tdoc.Init()\n\treturn doc\n}\n")
+	return format.Source(b.Bytes())
+}
+
+// колонки, которые обычно хранят название записи. Подсказка для таблиц без колонки title
+var titleColumnCandidates = []string{"name", "full_name", "fullname", "caption", "label"}
+
+// подсказка для таблицы без колонки title. Пустая строка, если title есть
+func titleTodo(t *Table) string {
+	for _, c := range t.Columns {
+		if c.Name == "title" {
+			return ""
+		}
+	}
+	for _, name := range titleColumnCandidates {
+		for _, c := range t.Columns {
+			if c.Name == name {
+				return fmt.Sprintf("в таблице нет колонки title. Переименуйте %[1]s в title: замените поле %[1]s на t.GetFldTitle().SetPrevNames(%[1]q)", name)
+			}
+		}
+	}
+	return "в таблице нет колонки title. Добавьте t.GetFldTitle(), иначе ссылки на документ не пройдут проверку"
+}
+
+// поле title: GetFldTitle уже обязательное и уникальное с размером 150
+func titleFld(c *Column) string {
+	res := "t.GetFldTitle()"
+	if len(c.Comment) > 0 && c.Comment != "название" {
+		res = fmt.Sprintf("t.GetFldTitle(\"\", %q)", "name_ru:"+c.Comment)
+	}
+	if !c.IsUniq {
+		res += ".SetIsNotUniq()"
+	}
+	if size := c.Size(); size > 0 && size != 150 {
+		res += fmt.Sprintf(".SetSqlSize(%v)", size)
+	}
+	return res
+}
+
+// вызов конструктора поля по колонке. Второе значение - пояснение для типов, которые переносятся не полностью
+func columnFld(c *Column, row int) (string, string) {
+	nameRu := c.Comment
+	if len(nameRu) == 0 {
+		nameRu = c.Name
+	}
+	rowCol := fmt.Sprintf("[][]int{{%v, 1}}", row)
+	args := fmt.Sprintf("%q, %q", c.Name, nameRu)
+	var res, todo string
+	typ := c.Type
+	switch {
+	case len(c.Ref) > 0:
+		res = fmt.Sprintf("t.GetFldRef(%s, %q, %s)", args, c.Ref, rowCol)
+	case len(c.EnumVals) > 0:
+		size := 0
+		options := []string{}
+		for _, v := range c.EnumVals {
+			if len(v) > size {
+				size = len(v)
+			}
+			options = append(options, fmt.Sprintf("{Label: %q, Value: %q}", v, v))
+		}
+		res = fmt.Sprintf("t.GetFldSelectString(%s, %v, %s, []t.FldVueOptionsItem{%s})", args, size, rowCol, strings.Join(options, ", "))
+		todo = fmt.Sprintf("в базе это enum %s - в документе колонка будет строкой", c.Type)
+	case strings.HasSuffix(typ, "[]"):
+		fldType := "t.FldTypeTextArray"
+		switch strings.TrimSuffix(typ, "[]") {
+		case "integer", "int", "int4", "smallint", "bigint", "int8":
+			fldType = "t.FldTypeIntArray"
+		case "double precision", "numeric", "real", "float8":
+			fldType = "t.FldTypeDoubleArray"
+		}
+		res = fmt.Sprintf("t.FldType{Name: %q, NameRu: %q, Type: %s, Vue: t.FldVue{RowCol: %s}}.SetIsHide()", c.Name, nameRu, fldType, rowCol)
+		todo = fmt.Sprintf("массив %s - нет стандартного компонента для формы, поле скрыто", c.Type)
+	case strings.HasPrefix(typ, "character varying"), strings.HasPrefix(typ, "varchar"), strings.HasPrefix(typ, "character"), strings.HasPrefix(typ, "char"):
+		res = fmt.Sprintf("t.GetFldString(%s, %v, %s)", args, c.Size(), rowCol)
+	case typ == "text", typ == "citext":
+		res = fmt.Sprintf("t.GetFldString(%s, 0, %s)", args, rowCol)
+	case typ == "integer", typ == "int", typ == "int4", typ == "smallint", typ == "int2", typ == "serial":
+		res = fmt.Sprintf("t.GetFldInt(%s, %s)", args, rowCol)
+	case typ == "bigint", typ == "int8", typ == "bigserial":
+		res = fmt.Sprintf("t.GetFldInt64(%s, %s)", args, rowCol)
+	case strings.HasPrefix(typ, "numeric"), strings.HasPrefix(typ, "decimal"), typ == "double precision", typ == "real", typ == "float8", typ == "money":
+		res = fmt.Sprintf("t.GetFldDouble(%s, %s)", args, rowCol)
+	case typ == "date":
+		res = fmt.Sprintf("t.GetFldDate(%s, %s)", args, rowCol)
+	case strings.HasPrefix(typ, "timestamp"):
+		res = fmt.Sprintf("t.GetFldDateTime(%s, %s)", args, rowCol)
+	case typ == "boolean", typ == "bool":
+		res = fmt.Sprintf("t.GetFldCheckbox(%s, %s)", args, rowCol)
+	case typ == "uuid":
+		res = fmt.Sprintf("t.GetFldUuid(%s, %s)", args, rowCol)
+	case typ == "jsonb", typ == "json":
+		res = fmt.Sprintf("t.FldType{Name: %q, NameRu: %q, Type: t.FldTypeJsonb, Vue: t.FldVue{RowCol: %s}}.SetIsHide()", c.Name, nameRu, rowCol)
+		todo = "jsonb - выберите компонент (GetFldJsonList, GetFldJsonbComposition...), пока поле скрыто"
+	default:
+		res = fmt.Sprintf("t.GetFldString(%s, 0, %s)", args, rowCol)
+		todo = fmt.Sprintf("неизвестный тип %s - поле создано как text", c.Type)
+	}
+	if c.NotNull && !c.IsPKey {
+		res += ".SetIsRequired()"
+	}
+	if def := c.DefaultValue(); len(def) > 0 {
+		res += fmt.Sprintf(".SetDefault(%s)", strconv.Quote(def))
+	}
+	if c.IsUniq {
+		res += ".SetIsUniq()"
+	}
+	return res, todo
+}
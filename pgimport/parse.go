@@ -0,0 +1,396 @@
+// Package pgimport импорт описания документов из дампа схемы postgres (pg_dump --schema-only).
+// Подключение к базе не нужно: разбираются CREATE TABLE, CREATE TYPE ... AS ENUM, ALTER TABLE ... ADD CONSTRAINT,
+// CREATE UNIQUE INDEX и COMMENT ON. По таблицам генерируется go код DocType с полями через GetFld... (см generate.go)
+package pgimport
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type (
+	Schema struct {
+		Tables []*Table
+		Enums  map[string][]string // название типа (без схемы) - значения
+	}
+
+	Table struct {
+		Name    string // без схемы
+		Comment string
+		Columns []*Column
+		Checks  []Constraint // CHECK (...)
+		Uniques []Constraint // UNIQUE (...) по нескольким колонкам. Уникальность по одной колонке - Column.IsUniq
+		Fks     []ForeignKey
+	}
+
+	Column struct {
+		Name     string
+		Type     string // тип как в дампе, в нижнем регистре: character varying(150), integer, timestamp with time zone...
+		NotNull  bool
+		Default  string
+		Comment  string
+		IsUniq   bool
+		IsPKey   bool
+		Ref      string // таблица, на которую ссылается колонка (FOREIGN KEY по одной колонке)
+		EnumVals []string
+	}
+
+	Constraint struct {
+		Name string
+		Expr string // для CHECK - условие, для UNIQUE - список колонок через запятую
+	}
+
+	ForeignKey struct {
+		Name     string
+		Columns  []string
+		RefTable string
+	}
+)
+
+var (
+	createTableRe  = regexp.MustCompile(`(?is)^CREATE\s+(?:UNLOGGED\s+)?TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?(\S+)\s*\((.*)\)`)
+	createEnumRe   = regexp.MustCompile(`(?is)^CREATE\s+TYPE\s+(\S+)\s+AS\s+ENUM\s*\((.*)\)`)
+	alterAddRe     = regexp.MustCompile(`(?is)^ALTER\s+TABLE\s+(?:ONLY\s+)?(\S+)\s+ADD\s+CONSTRAINT\s+(\S+)\s+(.*)$`)
+	uniqIndexRe    = regexp.MustCompile(`(?is)^CREATE\s+UNIQUE\s+INDEX\s+\S+\s+ON\s+(?:ONLY\s+)?(\S+)\s+(?:USING\s+\w+\s+)?\((.*?)\)\s*(WHERE\s+.*)?$`)
+	commentTableRe = regexp.MustCompile(`(?is)^COMMENT\s+ON\s+TABLE\s+(\S+)\s+IS\s+'(.*)'$`)
+	commentColRe   = regexp.MustCompile(`(?is)^COMMENT\s+ON\s+COLUMN\s+(\S+)\s+IS\s+'(.*)'$`)
+	fkRe           = regexp.MustCompile(`(?is)^FOREIGN\s+KEY\s*\((.*?)\)\s*REFERENCES\s+([^\s(]+)`)
+	columnRe       = regexp.MustCompile(`(?is)^("[^"]+"|\S+)\s+(.*?)(\s+(?:NOT\s+NULL|NULL|DEFAULT|COLLATE|CONSTRAINT|CHECK|REFERENCES|PRIMARY\s+KEY|UNIQUE|GENERATED)\b.*)?$`)
+	defaultRe      = regexp.MustCompile(`(?is)\bDEFAULT\s+(.*?)(\s+(?:NOT\s+NULL|NULL|COLLATE|CONSTRAINT|CHECK|REFERENCES|PRIMARY\s+KEY|UNIQUE|GENERATED)\b.*)?$`)
+	castRe         = regexp.MustCompile(`::[\w\s]+(\[\])?(\(\d+\))?$`)
+	checkOptsRe    = regexp.MustCompile(`(?is)(\s+(NOT\s+VALID|NO\s+INHERIT))+$`)
+)
+
+// Parse разбор дампа. Неизвестные инструкции (функции, триггеры, гранты и т.п.) пропускаются
+func Parse(src string) (*Schema, error) {
+	s := &Schema{Enums: map[string][]string{}}
+	tables := map[string]*Table{}
+	for _, stmt := range splitStatements(src) {
+		if m := createEnumRe.FindStringSubmatch(stmt); m != nil {
+			vals := []string{}
+			for _, v := range splitTopLevel(m[2]) {
+				vals = append(vals, unquote(v))
+			}
+			s.Enums[unqualify(m[1])] = vals
+			continue
+		}
+		if m := createTableRe.FindStringSubmatch(stmt); m != nil {
+			t := &Table{Name: unqualify(m[1])}
+			for _, item := range splitTopLevel(m[2]) {
+				if err := t.addItem(item); err != nil {
+					return nil, fmt.Errorf("table %s: %s", t.Name, err)
+				}
+			}
+			tables[t.Name] = t
+			s.Tables = append(s.Tables, t)
+		}
+	}
+	// ограничения и комментарии в дампе идут после всех таблиц
+	for _, stmt := range splitStatements(src) {
+		if m := alterAddRe.FindStringSubmatch(stmt); m != nil {
+			if t, ok := tables[unqualify(m[1])]; ok {
+				t.addConstraint(unquoteIdent(m[2]), m[3])
+			}
+			continue
+		}
+		if m := uniqIndexRe.FindStringSubmatch(stmt); m != nil {
+			// частичный индекс (WHERE) не переносим - в описании документа такой уникальности нет
+			if t, ok := tables[unqualify(m[1])]; ok && len(m[3]) == 0 {
+				t.addUnique("", m[2])
+			}
+			continue
+		}
+		if m := commentTableRe.FindStringSubmatch(stmt); m != nil {
+			if t, ok := tables[unqualify(m[1])]; ok {
+				t.Comment = unescape(m[2])
+			}
+			continue
+		}
+		if m := commentColRe.FindStringSubmatch(stmt); m != nil {
+			path := strings.Split(m[1], ".")
+			if len(path) < 2 {
+				continue
+			}
+			if t, ok := tables[unquoteIdent(path[len(path)-2])]; ok {
+				if c := t.Column(unquoteIdent(path[len(path)-1])); c != nil {
+					c.Comment = unescape(m[2])
+				}
+			}
+		}
+	}
+	for _, t := range s.Tables {
+		for _, c := range t.Columns {
+			c.EnumVals = s.Enums[unqualify(c.Type)]
+		}
+	}
+	return s, nil
+}
+
+// Column колонка по имени
+func (t *Table) Column(name string) *Column {
+	for _, c := range t.Columns {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// колонка или ограничение внутри CREATE TABLE
+func (t *Table) addItem(item string) error {
+	item = strings.TrimSpace(item)
+	if len(item) == 0 {
+		return nil
+	}
+	upper := strings.ToUpper(item)
+	if strings.HasPrefix(upper, "CONSTRAINT ") {
+		fields := strings.Fields(item)
+		if len(fields) < 3 {
+			return fmt.Errorf("wrong constraint: %s", item)
+		}
+		t.addConstraint(unquoteIdent(fields[1]), strings.TrimSpace(item[strings.Index(item, fields[1])+len(fields[1]):]))
+		return nil
+	}
+	for _, prefix := range []string{"CHECK", "UNIQUE", "PRIMARY KEY", "FOREIGN KEY"} {
+		if strings.HasPrefix(upper, prefix) {
+			t.addConstraint("", item)
+			return nil
+		}
+	}
+	m := columnRe.FindStringSubmatch(item)
+	if m == nil {
+		return fmt.Errorf("can't parse column: %s", item)
+	}
+	c := &Column{Name: unquoteIdent(m[1]), Type: strings.ToLower(strings.TrimSpace(m[2]))}
+	rest := strings.ToUpper(m[3])
+	c.NotNull = strings.Contains(rest, "NOT NULL") || strings.Contains(rest, "PRIMARY KEY")
+	c.IsPKey = strings.Contains(rest, "PRIMARY KEY")
+	c.IsUniq = regexp.MustCompile(`\bUNIQUE\b`).MatchString(rest)
+	if d := defaultRe.FindStringSubmatch(m[3]); d != nil {
+		c.Default = strings.TrimSpace(d[1])
+	}
+	t.Columns = append(t.Columns, c)
+	return nil
+}
+
+// ограничение из CREATE TABLE или ALTER TABLE ... ADD CONSTRAINT. def - текст после имени: CHECK (...), UNIQUE (...) и т.д.
+func (t *Table) addConstraint(name, def string) {
+	def = checkOptsRe.ReplaceAllString(strings.TrimSpace(def), "")
+	upper := strings.ToUpper(def)
+	switch {
+	case strings.HasPrefix(upper, "CHECK"):
+		t.Checks = append(t.Checks, Constraint{Name: name, Expr: stripParens(strings.TrimSpace(def[len("CHECK"):]))})
+	case strings.HasPrefix(upper, "UNIQUE"):
+		t.addUnique(name, stripParens(strings.TrimSpace(def[len("UNIQUE"):])))
+	case strings.HasPrefix(upper, "PRIMARY KEY"):
+		for _, col := range splitTopLevel(stripParens(strings.TrimSpace(def[len("PRIMARY KEY"):]))) {
+			if c := t.Column(unquoteIdent(col)); c != nil {
+				c.IsPKey = true
+			}
+		}
+	case strings.HasPrefix(upper, "FOREIGN KEY"):
+		m := fkRe.FindStringSubmatch(def)
+		if m == nil {
+			return
+		}
+		fk := ForeignKey{Name: name, RefTable: unqualify(m[2])}
+		for _, col := range splitTopLevel(m[1]) {
+			fk.Columns = append(fk.Columns, unquoteIdent(col))
+		}
+		if len(fk.Columns) == 1 {
+			if c := t.Column(fk.Columns[0]); c != nil {
+				c.Ref = fk.RefTable
+			}
+		}
+		t.Fks = append(t.Fks, fk)
+	}
+}
+
+func (t *Table) addUnique(name, cols string) {
+	arr := []string{}
+	for _, col := range splitTopLevel(cols) {
+		arr = append(arr, unquoteIdent(col))
+	}
+	if len(arr) == 1 {
+		if c := t.Column(arr[0]); c != nil {
+			c.IsUniq = true
+			return
+		}
+	}
+	if len(name) == 0 {
+		name = fmt.Sprintf("%s_%s_key", t.Name, strings.Join(arr, "_"))
+	}
+	t.Uniques = append(t.Uniques, Constraint{Name: name, Expr: strings.Join(arr, ", ")})
+}
+
+// Size размер для character varying(n) / character(n). 0 - не указан
+func (c Column) Size() int {
+	if strings.HasPrefix(c.Type, "char") || strings.HasPrefix(c.Type, "varchar") {
+		if m := regexp.MustCompile(`\((\d+)\)`).FindStringSubmatch(c.Type); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			return n
+		}
+	}
+	return 0
+}
+
+// DefaultValue значение по умолчанию без приведения типа: 'draft'::character varying -> 'draft'.
+// Автоинкремент (nextval) не переносится
+func (c Column) DefaultValue() string {
+	if strings.HasPrefix(strings.ToLower(c.Default), "nextval(") {
+		return ""
+	}
+	return castRe.ReplaceAllString(c.Default, "")
+}
+
+// разбиение дампа на инструкции по ';' с учетом строк, идентификаторов в кавычках, $$-блоков и комментариев
+func splitStatements(src string) []string {
+	res := []string{}
+	var cur strings.Builder
+	flush := func() {
+		if s := strings.TrimSpace(cur.String()); len(s) > 0 {
+			res = append(res, s)
+		}
+		cur.Reset()
+	}
+	for i := 0; i < len(src); i++ {
+		ch := src[i]
+		switch {
+		case ch == '-' && i+1 < len(src) && src[i+1] == '-':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			cur.WriteByte('\n')
+		case ch == '/' && i+1 < len(src) && src[i+1] == '*':
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				i = len(src)
+			} else {
+				i += end + 3
+			}
+		case ch == '\'' || ch == '"':
+			j := i + 1
+			for j < len(src) {
+				if src[j] == ch {
+					// '' внутри строки - экранированная кавычка
+					if j+1 < len(src) && src[j+1] == ch {
+						j += 2
+						continue
+					}
+					break
+				}
+				j++
+			}
+			cur.WriteString(src[i:min(j+1, len(src))])
+			i = j
+		case ch == '$':
+			// $tag$ ... $tag$
+			if m := regexp.MustCompile(`^\$[A-Za-z_]*\$`).FindString(src[i:]); len(m) > 0 {
+				end := strings.Index(src[i+len(m):], m)
+				if end < 0 {
+					end = len(src) - i - len(m)
+				}
+				cur.WriteString(src[i:min(i+len(m)+end+len(m), len(src))])
+				i += len(m) + end + len(m) - 1
+				continue
+			}
+			cur.WriteByte(ch)
+		case ch == ';':
+			flush()
+		default:
+			cur.WriteByte(ch)
+		}
+	}
+	flush()
+	return res
+}
+
+// разбиение по запятым верхнего уровня (без учета запятых в скобках и строках)
+func splitTopLevel(s string) []string {
+	res := []string{}
+	depth, start := 0, 0
+	inStr := byte(0)
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case inStr != 0:
+			if ch == inStr {
+				inStr = 0
+			}
+		case ch == '\'' || ch == '"':
+			inStr = ch
+		case ch == '(':
+			depth++
+		case ch == ')':
+			depth--
+		case ch == ',' && depth == 0:
+			res = append(res, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(s[start:]); len(last) > 0 {
+		res = append(res, last)
+	}
+	return res
+}
+
+// (expr) -> expr. pg_dump оборачивает условия CHECK в двойные скобки
+func stripParens(s string) string {
+	s = strings.TrimSpace(s)
+	for strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") && len(splitTopLevel(s)) == 1 && closingParen(s) == len(s)-1 {
+		s = strings.TrimSpace(s[1 : len(s)-1])
+	}
+	return s
+}
+
+// позиция скобки, закрывающей первую открывающую
+func closingParen(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// public.client -> client
+func unqualify(name string) string {
+	arr := strings.Split(name, ".")
+	return unquoteIdent(arr[len(arr)-1])
+}
+
+func unquoteIdent(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) > 1 && strings.HasPrefix(s, `"`) && strings.HasSuffix(s, `"`) {
+		return strings.ReplaceAll(s[1:len(s)-1], `""`, `"`)
+	}
+	return s
+}
+
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) > 1 && strings.HasPrefix(s, "'") && strings.HasSuffix(s, "'") {
+		return unescape(s[1 : len(s)-1])
+	}
+	return s
+}
+
+func unescape(s string) string {
+	return strings.ReplaceAll(s, "''", "'")
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
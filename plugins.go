@@ -0,0 +1,8 @@
+package nla_framework
+
+// встроенные плагины генератора. Сторонние плагины подключаются так же: импортом пакета, который вызывает types.RegisterPlugin в init()
+import (
+	_ "github.com/NL-A/nla_framework/plugins/bitrix"
+	_ "github.com/NL-A/nla_framework/plugins/odata"
+	_ "github.com/NL-A/nla_framework/plugins/telegram"
+)
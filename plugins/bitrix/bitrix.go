@@ -0,0 +1,151 @@
+// Package bitrix интеграция с Битрикс24: импорт документов через REST api.
+// Включается, если заполнен Config.Bitrix.ApiUrl. Документ участвует в импорте, если заполнен DocType.Integrations.Bitrix.UrlName
+package bitrix
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"text/template"
+
+	"github.com/NL-A/nla_framework/types"
+	"github.com/NL-A/nla_framework/utils"
+	"github.com/iancoleman/strcase"
+	"github.com/serenize/snaker"
+)
+
+const Name = "bitrix"
+
+type plugin struct {
+	types.PluginBase
+}
+
+func init() {
+	types.RegisterPlugin(plugin{})
+}
+
+func (plugin) Name() string {
+	return Name
+}
+
+func (plugin) IsEnabled(p types.ProjectType) bool {
+	return p.IsBitrixIntegration()
+}
+
+func (plugin) ProjectTemplates(p types.ProjectType) []types.PluginTemplate {
+	return []types.PluginTemplate{
+		{Source: utils.FrameworkPath("templates/integrations/bitrix/bitrixMain.go"), Dist: "/bitrix/main.go"},
+	}
+}
+
+func (plugin) DocTemplates(p types.ProjectType, d types.DocType) map[string]*types.DocTemplate {
+	if !d.IsBitrixIntegration() {
+		return nil
+	}
+	sourcePath := utils.FrameworkPath("templates/integrations/bitrix/bitrixDoc.go")
+	// проверяем возможность того, что путь к шаблону был переопределен внутри документа
+	if tmpl, ok := d.TemplatePathOverride["bitrixDoc.go"]; ok && len(tmpl.Source) > 0 {
+		sourcePath = tmpl.Source
+	}
+	docName := d.Name
+	funcMap := template.FuncMap{
+		"LocalProjectPath": func() string { return p.Config.LocalProjectPath },
+		"DocNameCamel":     func() string { return snaker.SnakeToCamel(docName) },
+		"IsBtxFld": func(fld types.FldType) bool {
+			return len(Fld(d, fld).Name) > 0
+		},
+		"GetBtxFldName": func(fld types.FldType) string {
+			return Fld(d, fld).Name
+		},
+		"GetBtxFldType": func(fld types.FldType) string {
+			t := Fld(d, fld).Type
+			if len(t) == 0 {
+				return "interface{}"
+			}
+			return t
+		},
+		"CastToGoType": func(fld types.FldType) string {
+			return CastToGoType(fld, Fld(d, fld).CastToGoType)
+		},
+	}
+	return map[string]*types.DocTemplate{
+		"webClient_comp_bitrixDoc.go": {Source: sourcePath, DistPath: p.DistPath + "/bitrix", DistFilename: snaker.SnakeToCamelLower(d.Name) + ".go", FuncMap: funcMap},
+	}
+}
+
+func (plugin) ConfigSections(p types.ProjectType) []types.PluginConfigSection {
+	return []types.PluginConfigSection{{Name: "bitrix", Flds: []types.PluginConfigFld{{Name: "apiUrl"}, {Name: "userId"}, {Name: "webhookToken"}}}}
+}
+
+func (plugin) Go(p types.ProjectType) types.ProjectGo {
+	pkg := p.Config.LocalProjectPath + "/bitrix"
+	api := []string{"// импорт данных из Битрикс", `btxRoute := apiRoute.Group("/bitrix")`}
+	notAuth := []string{}
+	for _, d := range p.Docs {
+		if d.IsBitrixIntegration() {
+			api = append(api, fmt.Sprintf(`btxRoute.POST("/import_%s", bitrix.Get%sHistory)`, d.Name, strcase.ToCamel(d.Name)))
+		}
+		// отладочные методы для импорта данных из Битрикс
+		if d.IsBitrixIntegrationDebugMode() {
+			notAuth = append(notAuth, fmt.Sprintf(`r.GET("/bitrix/import_%s", bitrix.Get%sHistoryDebug)`, d.Name, strcase.ToCamel(d.Name)))
+		}
+	}
+	return types.ProjectGo{
+		MainGoImports:             []string{pkg},
+		HooksBeforeStartWebServer: []string{"bitrix.SetBitrixConfig(config.Bitrix)"},
+		Routes:                    types.ProjectGoRoutes{Imports: []string{pkg}, Api: []string{strings.Join(api, "\n\t\t")}, NotAuth: notAuth},
+	}
+}
+
+func (plugin) Validate(p types.ProjectType) []types.ValidationError {
+	res := []types.ValidationError{}
+	for _, d := range p.Docs {
+		if d.IsBitrixIntegration() && !p.IsBitrixIntegration() {
+			res = append(res, types.ValidationError{Doc: d.Name, Path: fmt.Sprintf("Docs[%s].Integrations.Bitrix", d.Name), Msg: "doc has bitrix integration, but Config.Bitrix.ApiUrl is empty"})
+		}
+	}
+	return res
+}
+
+// Fld описание поля в Битрикс (см FldType.SetBitrixInfo)
+func Fld(d types.DocType, fld types.FldType) types.BitrixFld {
+	if btxFldInt, ok := fld.IntegrationData[Name]; ok {
+		if btxFld, ok := btxFldInt.(types.BitrixFld); ok {
+			return btxFld
+		}
+		log.Fatalf("bitrix plugin doc: '%s' fld: '%s' not BitrixFld", d.Name, fld.Name)
+	}
+	return types.BitrixFld{}
+}
+
+// CastToGoType приведение значения из внешней системы к типу поля. Если задан custom, то используется он
+func CastToGoType(fld types.FldType, custom string) string {
+	fName := strcase.ToCamel(fld.Name)
+	// если в описании поля указан способ приведения к типу, то используем его
+	if len(custom) > 0 {
+		return custom
+	}
+	switch fld.Type {
+	case types.FldTypeText, types.FldTypeString:
+		return fmt.Sprintf("res.%[1]s = cast.ToString(btxDoc.%[1]s)", fName)
+	case types.FldTypeInt:
+		return fmt.Sprintf("res.%[1]s = cast.ToInt(btxDoc.%[1]s)", fName)
+	case types.FldTypeInt64:
+		return fmt.Sprintf("res.%[1]s = cast.ToInt64(btxDoc.%[1]s)", fName)
+	case types.FldTypeDouble:
+		return fmt.Sprintf("res.%[1]s = cast.ToFloat64(btxDoc.%[1]s)", fName)
+	case types.FldTypeIntArray:
+		return fmt.Sprintf(`res.%[1]s = []int{}
+				intSlice%[1]s, err := cast.ToIntSliceE(btxDoc.%[1]s)
+				if err == nil {
+					res.%[1]s = intSlice%[1]s
+				}`, fName)
+	case types.FldTypeTextArray:
+		return fmt.Sprintf(`res.%[1]s = []string{}
+				txtSlice%[1]s, err := cast.ToStringSliceE(btxDoc.%[1]s)
+				if err == nil {
+					res.%[1]s = txtSlice%[1]s
+				}`, fName)
+	}
+	return "`!!! CastToGoType not found for type: " + fld.Type + " fld: " + fld.Name + "`"
+}
@@ -0,0 +1,131 @@
+// Package odata интеграция с 1С через OData: синхронизация документов.
+// Включается, если заполнен Config.Odata.Url. Документ синхронизируется, если заполнен DocType.Integrations.Odata.Name
+package odata
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"text/template"
+
+	"github.com/NL-A/nla_framework/plugins/bitrix"
+	"github.com/NL-A/nla_framework/types"
+	"github.com/NL-A/nla_framework/utils"
+	"github.com/iancoleman/strcase"
+	"github.com/serenize/snaker"
+)
+
+const Name = "odata"
+
+type plugin struct {
+	types.PluginBase
+}
+
+func init() {
+	types.RegisterPlugin(plugin{})
+}
+
+func (plugin) Name() string {
+	return Name
+}
+
+func (plugin) IsEnabled(p types.ProjectType) bool {
+	return p.IsOdataIntegration()
+}
+
+func (plugin) ProjectTemplates(p types.ProjectType) []types.PluginTemplate {
+	return []types.PluginTemplate{
+		{Source: utils.FrameworkPath("templates/integrations/odata/main.go"), Dist: "/odata/main.go"},
+		{Source: utils.FrameworkPath("templates/integrations/odata/odataQueryType.go"), Dist: "/odata/odataQueryType.go"},
+	}
+}
+
+func (plugin) DocTemplates(p types.ProjectType, d types.DocType) map[string]*types.DocTemplate {
+	if !d.IsOdataIntegration() {
+		return nil
+	}
+	sourcePath := utils.FrameworkPath("templates/integrations/odata/odataDoc.go")
+	// проверяем возможность того, что путь к шаблону был переопределен внутри документа
+	if tmpl, ok := d.TemplatePathOverride["odataDoc.go"]; ok && len(tmpl.Source) > 0 {
+		sourcePath = tmpl.Source
+	}
+	docName := d.Name
+	odataName := d.Integrations.Odata.Name
+	odataFldNames := []string{}
+	for _, fld := range d.Flds {
+		odataName := Fld(d, fld).Name
+		if len(odataName) > 0 {
+			odataFldNames = append(odataFldNames, odataName)
+		}
+	}
+	funcMap := template.FuncMap{
+		"LocalProjectPath": func() string { return p.Config.LocalProjectPath },
+		"DocNameCamel":     func() string { return snaker.SnakeToCamel(docName) },
+		"GetOdataName":     func() string { return odataName },
+		"GetOdataFldNames": func() []string { return odataFldNames },
+		"IsOdataFld": func(fld types.FldType) bool {
+			return len(Fld(d, fld).Name) > 0
+		},
+		"GetOdataFldName": func(fld types.FldType) string {
+			return Fld(d, fld).Name
+		},
+		"GetOdataFldType": func(fld types.FldType) string {
+			t := Fld(d, fld).Type
+			if len(t) == 0 {
+				return fld.GoType()
+			}
+			return t
+		},
+		"CastToGoType": func(fld types.FldType) string {
+			return bitrix.CastToGoType(fld, bitrix.Fld(d, fld).CastToGoType)
+		},
+	}
+	return map[string]*types.DocTemplate{
+		"webClient_comp_odataDoc.go": {Source: sourcePath, DistPath: p.DistPath + "/odata", DistFilename: snaker.SnakeToCamelLower(d.Name) + ".go", FuncMap: funcMap},
+	}
+}
+
+func (plugin) ConfigSections(p types.ProjectType) []types.PluginConfigSection {
+	return []types.PluginConfigSection{{Name: "odata", Flds: []types.PluginConfigFld{{Name: "url"}, {Name: "login"}, {Name: "password"}, {Name: "exchangePlanName"}, {Name: "exchangePlanGuid"}}}}
+}
+
+func (plugin) Go(p types.ProjectType) types.ProjectGo {
+	pkg := p.Config.LocalProjectPath + "/odata"
+	api := []string{"// импорт данных из 1С Odata", `odataRoute := apiRoute.Group("/odata")`}
+	notAuth := []string{}
+	for _, d := range p.Docs {
+		if d.IsOdataIntegration() {
+			api = append(api, fmt.Sprintf(`odataRoute.POST("/import_%s", odata.Start%sSync)`, d.Name, strcase.ToCamel(d.Name)))
+		}
+		// отладочные методы для импорта данных из 1С
+		if d.IsOdataIntegrationDebugMode() {
+			notAuth = append(notAuth, fmt.Sprintf(`r.GET("/odata/import_%s", odata.Sync%sWith1CDebug)`, d.Name, strcase.ToCamel(d.Name)))
+		}
+	}
+	return types.ProjectGo{
+		MainGoImports:             []string{pkg},
+		HooksBeforeStartWebServer: []string{"odata.SetOdataConfig(config.Odata)"},
+		Routes:                    types.ProjectGoRoutes{Imports: []string{pkg}, Api: []string{strings.Join(api, "\n\t\t")}, NotAuth: notAuth},
+	}
+}
+
+func (plugin) Validate(p types.ProjectType) []types.ValidationError {
+	res := []types.ValidationError{}
+	for _, d := range p.Docs {
+		if d.IsOdataIntegration() && !p.IsOdataIntegration() {
+			res = append(res, types.ValidationError{Doc: d.Name, Path: fmt.Sprintf("Docs[%s].Integrations.Odata", d.Name), Msg: "doc has odata integration, but Config.Odata.Url is empty"})
+		}
+	}
+	return res
+}
+
+// Fld описание поля в 1С (см FldType.SetOdataInfo)
+func Fld(d types.DocType, fld types.FldType) types.OdataFld {
+	if odataFldInt, ok := fld.IntegrationData[Name]; ok {
+		if odataFld, ok := odataFldInt.(types.OdataFld); ok {
+			return odataFld
+		}
+		log.Fatalf("odata plugin doc: '%s' fld: '%s' not OdataFld", d.Name, fld.Name)
+	}
+	return types.OdataFld{}
+}
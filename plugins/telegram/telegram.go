@@ -0,0 +1,74 @@
+// Package telegram авторизация через Telegram и телеграм бот. Включается, если заполнен Config.Telegram.Token
+package telegram
+
+import (
+	"fmt"
+
+	"github.com/NL-A/nla_framework/types"
+	"github.com/NL-A/nla_framework/utils"
+)
+
+const Name = "telegram"
+
+type plugin struct {
+	types.PluginBase
+}
+
+func init() {
+	types.RegisterPlugin(plugin{})
+}
+
+func (plugin) Name() string {
+	return Name
+}
+
+func (plugin) IsEnabled(p types.ProjectType) bool {
+	return p.IsTelegramIntegration()
+}
+
+func (plugin) ProjectTemplates(p types.ProjectType) []types.PluginTemplate {
+	return []types.PluginTemplate{
+		{Source: utils.FrameworkPath("templates/integrations/telegram/telegramAuth.go"), Dist: "/webServer/telegramAuth.go"},
+		{Source: utils.FrameworkPath("templates/integrations/telegram/user_telegram_auth.sql"), Dist: "/sql/template/function/_User/user_telegram_auth.sql"},
+		{Source: utils.FrameworkPath("templates/integrations/telegram/user_get_by_telegram_id.sql"), Dist: "/sql/template/function/_User/user_get_by_telegram_id.sql"},
+		{Source: utils.FrameworkPath("templates/project/tgBot/main.go"), Dist: "/tgBot/main.go"},
+	}
+}
+
+func (plugin) ConfigSections(p types.ProjectType) []types.PluginConfigSection {
+	// переменные окружения перезаписывают значения из config.toml
+	return []types.PluginConfigSection{{Name: "telegram", Flds: []types.PluginConfigFld{{Name: "botName", Env: "TG_BOT_NAME"}, {Name: "token", Env: "TELEGRAM_BOT_TOKEN"}}}}
+}
+
+func (plugin) Slots(p types.ProjectType) map[string][]string {
+	return map[string][]string{"pluginsConfig": {fmt.Sprintf("telegram: {botName: '%s', token: '%s'},", p.Config.Telegram.BotName, p.Config.Telegram.Token)}}
+}
+
+func (plugin) Go(p types.ProjectType) types.ProjectGo {
+	return types.ProjectGo{
+		MainGoImports: []string{p.Config.LocalProjectPath + "/tgBot"},
+		// в режиме разработки имя бота и токен передаются параметрами запуска
+		Flags: []types.ProjectGoFlag{{
+			Desc: "tgBotName := flag.String(\"telegram_bot_name\", \"\", \"an string\")\n\ttgBotToken := flag.String(\"telegram_bot_token\", \"\", \"an string\")",
+			ProcessBlock: `if *isDev {
+		if len(*tgBotName) > 0 {
+			_ = os.Setenv("TELEGRAM_BOT_NAME", *tgBotName)
+		} else {
+			utils.Panic("Write 'telegram_bot_name' and 'telegram_bot_token' in go parameters for developmeent mode")
+		}
+		if len(*tgBotToken) > 0 {
+			_ = os.Setenv("TELEGRAM_BOT_TOKEN", *tgBotToken)
+		}
+	}`,
+		}},
+		HooksBeforeStartWebServer: []string{"go tgBot.Start(*config)"},
+		Routes:                    types.ProjectGoRoutes{Api: []string{`apiRoute.POST("/telegram_auth", telegramAuth(config.Telegram))`}},
+	}
+}
+
+func (plugin) Validate(p types.ProjectType) []types.ValidationError {
+	if p.IsTelegramIntegration() && len(p.Config.Telegram.BotName) == 0 {
+		return []types.ValidationError{{Path: "Config.Telegram.BotName", Msg: "telegram integration requires bot name"}}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package nla_framework
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/NL-A/nla_framework/types"
+)
+
+// стандартные слоты в копируемых файлах фреймворка (см types.SlotType)
+func init() {
+	value := func(name string, fn func(p types.ProjectType) string) {
+		types.RegisterSlot(types.SlotType{Name: name, Content: func(p types.ProjectType) []string { return []string{fn(p)} }})
+	}
+	// app/plugins/config.js
+	value("appName", func(p types.ProjectType) string { return p.Name })
+	value("uiAppName", func(p types.ProjectType) string { return p.Vue.UiAppName })
+	value("webPort", func(p types.ProjectType) string { return fmt.Sprintf("%v", p.Config.WebServer.Port) })
+	value("url", func(p types.ProjectType) string { return strings.TrimPrefix(p.Config.WebServer.Url, "https://") })
+	value("urlWithHttp", func(p types.ProjectType) string {
+		// возможен вариант, что адрес в конфиге записан с http, тогда так его и оставляем. Иначе добавляем префикс https://
+		if strings.HasPrefix(p.Config.WebServer.Url, "http") || len(p.Config.WebServer.Url) == 0 {
+			return p.Config.WebServer.Url
+		}
+		return "https://" + p.Config.WebServer.Url
+	})
+	value("logoSrc", func(p types.ProjectType) string { return p.Config.Logo })
+	value("dadataToken", func(p types.ProjectType) string { return p.Config.Vue.DadataToken })
+	// список таблиц, к которым можно прикреплять задачи
+	value("tablesForTask", func(p types.ProjectType) string {
+		res := map[string]string{}
+		for _, d := range p.Docs {
+			if d.IsTaskAllowed {
+				res[d.Name] = d.NameRu
+			}
+		}
+		jsonStr, _ := json.Marshal(res)
+		return string(jsonStr)
+	})
+	types.RegisterSlot(types.SlotType{Name: "breadcrumbIcons", Content: func(p types.ProjectType) []string {
+		res := []string{}
+		for _, d := range p.Docs {
+			if len(d.Vue.BreadcrumbIcon) > 0 {
+				res = append(res, fmt.Sprintf("%s: '%s',", d.Name, d.Vue.BreadcrumbIcon))
+			}
+		}
+		return res
+	}})
+	// настройки включенных плагинов генератора (см GeneratorPlugin.Slots)
+	types.RegisterSlot(types.SlotType{Name: "pluginsConfig"})
+
+	// router/routes.js и sidemenu/index.vue
+	types.RegisterSlot(types.SlotType{Name: "routes", Content: routesJsModify})
+	types.RegisterSlot(types.SlotType{Name: "sidemenu", Content: sidemenuJsModify})
+
+	// sql
+	value("pgTimeZone", func(p types.ProjectType) string { return p.Config.Postgres.TimeZone })
+	// дополнительные методы для задач - в _Task/main.toml и блоки в триггер задач (DocSqlMethod.Params["trigger_task_update_table_name.sql"])
+	types.RegisterSlot(types.SlotType{Name: "task_methods", Content: func(p types.ProjectType) []string {
+		res := []string{}
+		for _, v := range p.Sql.Methods["task"] {
+			res = append(res, fmt.Sprintf("\"%s\",", v.Name))
+		}
+		return res
+	}})
+	types.RegisterSlot(types.SlotType{Name: "trigger_task_update_table_name", Content: func(p types.ProjectType) []string {
+		res := []string{}
+		for _, v := range p.Sql.Methods["task"] {
+			if txt, ok := v.Params["trigger_task_update_table_name.sql"]; ok {
+				res = append(res, txt)
+			}
+		}
+		return res
+	}})
+	types.RegisterSlot(types.SlotType{Name: "trigger_chat_update_table_name"})
+}
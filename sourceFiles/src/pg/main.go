@@ -12,15 +12,26 @@ var Pg *sql.DB
 
 func StartPostgres(config types.Postgres) error {
 	var err error
+	dbinfo := fmt.Sprintf("postgres://%s:%s@%s:%v/%s?sslmode=disable", config.User, config.Password, config.Host, config.Port, config.DbName)
+	// миграции схемы применяются к существующей базе до обновления таблиц и функций
+	isNewDb, err := migrateExistingDb(dbinfo)
+	if err != nil {
+		return err
+	}
 	// создаем базу
 	pgGenerate.Start(false)
 	// создаем подключение к базе
-	dbinfo := fmt.Sprintf("postgres://%s:%s@%s:%v/%s?sslmode=disable", config.User, config.Password, config.Host, config.Port, config.DbName)
 	Pg, err = sql.Open("postgres", dbinfo)
 	err = Pg.Ping()
 	if err != nil {
 		return err
 	}
+	// в новой базе таблицы созданы по актуальной модели - миграции только отмечаем как примененные
+	if isNewDb {
+		if err = markMigrationsApplied(Pg); err != nil {
+			return err
+		}
+	}
 	// подписываемся на канал обновлений
 	go pgListen(config)
 	return nil
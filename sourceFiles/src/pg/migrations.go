@@ -0,0 +1,171 @@
+package pg
+
+import (
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// миграции схемы из sql/migrations. Генерируются nla_framework по изменениям sql/model (см schema_snapshot.json)
+const migrationsDir = "./sql/migrations"
+
+// строка в миграции с описанием шага, при котором теряются данные
+const migrationDestructivePrefix = "-- destructive: "
+
+type migration struct {
+	Version     string
+	Name        string
+	Sql         string
+	Destructive []string
+}
+
+var migrationFileRe = regexp.MustCompile(`^(\d+)_.*\.up\.sql$`)
+
+// применение миграций к существующей базе. Вызывается до pgGenerate, который обновляет таблицы и функции по актуальной модели.
+// Возвращает true, если базы еще нет или в ней нет таблиц - тогда таблицы создаются сразу по актуальной модели и миграции не нужны
+func migrateExistingDb(dbinfo string) (isNewDb bool, err error) {
+	db, err := sql.Open("postgres", dbinfo)
+	if err != nil {
+		return false, err
+	}
+	defer db.Close()
+	if err = db.Ping(); err != nil {
+		// базу создаст pgGenerate
+		if strings.Contains(err.Error(), "does not exist") {
+			return true, nil
+		}
+		return false, err
+	}
+	var cnt int
+	err = db.QueryRow(`select count(*) from information_schema.tables where table_schema = 'public' and table_name != 'schema_migrations'`).Scan(&cnt)
+	if err != nil {
+		return false, err
+	}
+	if cnt == 0 {
+		return true, nil
+	}
+	return false, applyMigrations(db)
+}
+
+// для новой базы все миграции отмечаются как примененные без выполнения
+func markMigrationsApplied(db *sql.DB) error {
+	list, err := readMigrations()
+	if err != nil {
+		return err
+	}
+	if err = createMigrationsTable(db); err != nil {
+		return err
+	}
+	for _, m := range list {
+		_, err = db.Exec(`insert into schema_migrations (version, name) values ($1, $2) on conflict do nothing`, m.Version, m.Name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyMigrations(db *sql.DB) error {
+	list, err := readMigrations()
+	if err != nil || len(list) == 0 {
+		return err
+	}
+	if err = createMigrationsTable(db); err != nil {
+		return err
+	}
+	applied := map[string]bool{}
+	rows, err := db.Query(`select version from schema_migrations`)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var v string
+		if err = rows.Scan(&v); err != nil {
+			rows.Close()
+			return err
+		}
+		applied[v] = true
+	}
+	rows.Close()
+
+	// миграции с потерей данных выполняются только если их версия указана в APPROVE_MIGRATIONS (флаг -approve_migrations)
+	approved := map[string]bool{}
+	for _, v := range strings.Split(os.Getenv("APPROVE_MIGRATIONS"), ",") {
+		approved[strings.TrimSpace(v)] = true
+	}
+	for _, m := range list {
+		if applied[m.Version] {
+			continue
+		}
+		if len(m.Destructive) > 0 && !approved[m.Version] {
+			return fmt.Errorf("migration %s contains destructive steps:\n\t%s\ncheck the data and restart with -approve_migrations %s", m.Name, strings.Join(m.Destructive, "\n\t"), m.Version)
+		}
+		if err = applyMigration(db, m); err != nil {
+			return fmt.Errorf("migration %s: %s", m.Name, err)
+		}
+		fmt.Printf("migration applied: %s\n", m.Name)
+	}
+	return nil
+}
+
+// миграция и запись в schema_migrations в одной транзакции
+func applyMigration(db *sql.DB, m migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err = tx.Exec(m.Sql); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err = tx.Exec(`insert into schema_migrations (version, name) values ($1, $2)`, m.Version, m.Name); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func createMigrationsTable(db *sql.DB) error {
+	_, err := db.Exec(`create table if not exists schema_migrations (
+		version    text primary key,
+		name       text not null,
+		applied_at timestamp with time zone not null default now()
+	)`)
+	return err
+}
+
+// файлы *.up.sql, отсортированные по номеру версии
+func readMigrations() ([]migration, error) {
+	files, err := ioutil.ReadDir(migrationsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	res := []migration{}
+	for _, f := range files {
+		match := migrationFileRe.FindStringSubmatch(f.Name())
+		if f.IsDir() || match == nil {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(migrationsDir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		m := migration{Version: match[1], Name: strings.TrimSuffix(f.Name(), ".up.sql"), Sql: string(data)}
+		for _, line := range strings.Split(m.Sql, "\n") {
+			if strings.HasPrefix(line, migrationDestructivePrefix) {
+				m.Destructive = append(m.Destructive, strings.TrimPrefix(line, migrationDestructivePrefix))
+			}
+		}
+		res = append(res, m)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Version < res[j].Version })
+	return res, nil
+}
@@ -1,6 +1,7 @@
 package pg
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -35,6 +36,11 @@ func CallPgFuncWithStruct(funcName string, jsonStruct, res interface{}) error {
 }
 
 func CallPgFunc(funcName string, jsonStr []byte, res interface{}, metaInfo interface{}) (err error) {
+	return CallPgFuncContext(context.Background(), funcName, jsonStr, res, metaInfo)
+}
+
+// CallPgFuncContext вызов pg функции с контекстом. При отмене контекста запрос к базе прерывается
+func CallPgFuncContext(ctx context.Context, funcName string, jsonStr []byte, res interface{}, metaInfo interface{}) (err error) {
 
 	var queryRes []byte
 	var queryStr string
@@ -48,7 +54,7 @@ func CallPgFunc(funcName string, jsonStr []byte, res interface{}, metaInfo inter
 
 	//fmt.Printf("funcName: %s, queryStr: %s\n", funcName, queryStr)
 
-	err = Pg.QueryRow(queryStr).Scan(&queryRes)
+	err = Pg.QueryRowContext(ctx, queryStr).Scan(&queryRes)
 	if err != nil {
 		return
 	}
@@ -0,0 +1,74 @@
+// Package repo типизированный доступ к sql методам документов (get_by_id, list, update, action).
+// Для каждого документа генерируется свой файл со структурой записи и функциями, например ClientGetById, ClientList, ClientUpdate.
+// Используется в go коде проекта (jobs, интеграции, telegram бот) вместо вызова pg.CallPgFunc с самописными структурами.
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/NL-A/nla_framework/pg"
+)
+
+// SystemUserId id пользователя, от имени которого вызываются методы, если в контексте пользователь не указан (см WithUserId)
+const SystemUserId = -1
+
+type (
+	// Error ошибка вызова sql метода. Общая для всех функций пакета
+	Error struct {
+		Method string // название pg функции, например client_update
+		Err    error  // сообщение из postgres (поле message при ok = false) или ошибка запроса к базе
+	}
+
+	// ListParams общие параметры методов *_list
+	ListParams struct {
+		Deleted    bool   `json:"deleted,omitempty"`
+		OrderBy    string `json:"order_by,omitempty"` // поле и направление сортировки, например "id desc"
+		Page       int    `json:"page,omitempty"`
+		PerPage    int    `json:"per_page,omitempty"`
+		SearchText string `json:"search_text,omitempty"`
+	}
+
+	ctxUserIdKey struct{}
+)
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Method, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// WithUserId контекст с id пользователя, от имени которого вызываются методы. Передается в sql функции как user_id
+func WithUserId(ctx context.Context, userId int64) context.Context {
+	return context.WithValue(ctx, ctxUserIdKey{}, userId)
+}
+
+func userIdFromContext(ctx context.Context) int64 {
+	if v, ok := ctx.Value(ctxUserIdKey{}).(int64); ok {
+		return v
+	}
+	return SystemUserId
+}
+
+// вызов pg функции: параметры -> json + user_id из контекста, результат -> res
+func call(ctx context.Context, method string, params interface{}, res interface{}) error {
+	jsonStr, err := json.Marshal(params)
+	if err != nil {
+		return &Error{method, err}
+	}
+	m := map[string]interface{}{}
+	if err = json.Unmarshal(jsonStr, &m); err != nil {
+		return &Error{method, err}
+	}
+	m["user_id"] = userIdFromContext(ctx)
+	if jsonStr, err = json.Marshal(m); err != nil {
+		return &Error{method, err}
+	}
+	if err = pg.CallPgFuncContext(ctx, method, jsonStr, res, nil); err != nil {
+		return &Error{method, err}
+	}
+	return nil
+}
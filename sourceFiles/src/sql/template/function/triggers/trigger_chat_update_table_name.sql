@@ -7,7 +7,7 @@ BEGIN
     IF (TG_OP = 'INSERT') THEN
         -- заполняем table_options
         NEW.table_options = '{}'::jsonb;
-        -- for codeGenerate #trigger_task_update_table_name_slot
+        -- codeGenerate slot:trigger_chat_update_table_name
     end if;
 
     if (TG_OP = 'UPDATE') then
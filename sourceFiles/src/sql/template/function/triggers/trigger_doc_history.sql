@@ -0,0 +1,42 @@
+-- функция записи изменений документа в таблицу <таблица>_history (см DocSql.IsHistory)
+-- в историю пишутся только измененные поля: старое и новое значение, пользователь и тип операции
+
+CREATE OR REPLACE FUNCTION doc_history() RETURNS TRIGGER AS
+$$
+DECLARE
+    oldValues HSTORE;
+    newValues HSTORE;
+    docId     INT;
+    userId    INT;
+BEGIN
+
+    IF (TG_OP = 'DELETE')
+    THEN
+        docId = OLD.id;
+        oldValues = hstore(OLD) - ARRAY ['id', 'updated_at', 'created_at', 'search_text', 'search_vector'];
+    ELSIF (TG_OP = 'INSERT')
+    THEN
+        docId = NEW.id;
+        newValues = hstore(NEW) - ARRAY ['id', 'updated_at', 'created_at', 'search_text', 'search_vector'];
+    ELSE
+        docId = NEW.id;
+        -- считаем дельту между старой и новой версией
+        newValues = hstore(NEW) - hstore(OLD) - ARRAY ['updated_at', 'search_text', 'search_vector'];
+        IF newValues = ''::HSTORE
+        THEN
+            RETURN NULL;
+        END IF;
+        oldValues = slice(hstore(OLD), akeys(newValues));
+    END IF;
+
+    -- id пользователя прописывают sql методы документа (update, create, action) через set_config
+    userId = nullif(current_setting('app.user_id', true), '')::INT;
+
+    EXECUTE format('INSERT INTO %I (doc_id, user_id, operation, old_values, new_values) VALUES ($1, $2, $3, $4, $5)', TG_TABLE_NAME || '_history')
+        USING docId, userId, TG_OP, hstore_to_jsonb_loose(oldValues), hstore_to_jsonb_loose(newValues);
+
+    -- Result is ignored since this is an AFTER trigger
+    RETURN NULL;
+END;
+
+$$ LANGUAGE plpgsql;
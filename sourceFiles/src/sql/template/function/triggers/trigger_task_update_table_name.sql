@@ -11,7 +11,7 @@ BEGIN
         NEW.task_type_title = taskTypeRow.title;
         -- заполняем table_options
         NEW.table_options = '{}'::jsonb;
-        -- for codeGenerate #trigger_task_update_table_name_slot
+        -- codeGenerate slot:trigger_task_update_table_name
     end if;
 
     if (TG_OP = 'UPDATE') then
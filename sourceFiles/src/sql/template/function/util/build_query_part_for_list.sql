@@ -3,6 +3,104 @@
 -- order_by      type: string - поле для сортировки и направление сортировки.
 -- page_num      type: int - номер страницы. Дефолт: 1
 -- per_page      type: int - количество записей на странице. Дефолт: 10
+-- cursor        type: json - курсорная пагинация (keyset). Если параметр передан, то сортировка по одной колонке и id, без OFFSET (см list_keyset_where_str)
+
+-- колонка и направление сортировки для курсорной пагинации. order_by - только одна колонка таблицы, например 'created_at desc' или 'doc.created_at desc'. Дефолт: id desc.
+-- Для остальных вариантов order_by (несколько колонок, выражения) возвращает null - тогда курсорная пагинация не используется и list работает постранично
+-- Пустые значения при курсорной пагинации всегда в конце (NULLS LAST), поэтому для order_by с 'nulls first' тоже null
+DROP FUNCTION IF EXISTS list_keyset_sort(params JSONB);
+CREATE OR REPLACE FUNCTION list_keyset_sort(params JSONB)
+    RETURNS TEXT[]
+    LANGUAGE plpgsql
+    IMMUTABLE
+AS
+$function$
+
+DECLARE
+    m TEXT[];
+
+BEGIN
+
+    m = regexp_match(lower(trim(coalesce(params ->> 'order_by', 'id desc'))), '^(?:[a-z_][a-z0-9_]*\.)?([a-z_][a-z0-9_]*)(\s+(asc|desc))?(\s+nulls\s+last)?$');
+    IF m IS NULL
+    THEN
+        RETURN NULL;
+    END IF;
+
+    RETURN ARRAY [m[1], coalesce(m[3], 'asc')];
+
+END
+
+$function$;
+
+-- условие для курсорной пагинации: записи после курсора в порядке сортировки.
+-- cursor - {value: значение колонки сортировки, id} последней загруженной записи (meta_info.next_cursor в ответе list). Для первой страницы - null
+DROP FUNCTION IF EXISTS list_keyset_where_str(params JSONB, tableAlias VARCHAR);
+CREATE OR REPLACE FUNCTION list_keyset_where_str(params JSONB, tableAlias VARCHAR)
+    RETURNS TEXT
+    LANGUAGE plpgsql
+    IMMUTABLE
+AS
+$function$
+
+DECLARE
+    sort        TEXT[] := list_keyset_sort(params);
+    col         TEXT   := concat(tableAlias, '.', sort[1]);
+    op          TEXT   := CASE WHEN sort[2] = 'desc' THEN '<' ELSE '>' END;
+    cursorValue TEXT   := params -> 'cursor' ->> 'value';
+    cursorId    INT    := (params -> 'cursor' ->> 'id')::int;
+
+BEGIN
+
+    IF cursorId IS NULL OR sort IS NULL
+    THEN
+        RETURN '';
+    END IF;
+
+    IF sort[1] = 'id'
+    THEN
+        RETURN format(' AND %s %s %s', col, op, cursorId);
+    END IF;
+
+    -- null идут в конце списка (см build_query_part_for_list)
+    IF cursorValue IS NULL
+    THEN
+        RETURN format(' AND %1$s IS NULL AND %2$s.id %3$s %4$s', col, tableAlias, op, cursorId);
+    END IF;
+
+    RETURN format(' AND ((%1$s, %2$s.id) %3$s (%4$L, %5$s) OR %1$s IS NULL)', col, tableAlias, op, cursorValue, cursorId);
+
+END
+
+$function$;
+
+-- курсор следующей страницы: значение колонки сортировки и id последней записи. null - записей больше нет
+DROP FUNCTION IF EXISTS list_next_cursor(params JSONB, result JSON);
+CREATE OR REPLACE FUNCTION list_next_cursor(params JSONB, result JSON)
+    RETURNS JSONB
+    LANGUAGE plpgsql
+    IMMUTABLE
+AS
+$function$
+
+DECLARE
+    sort    TEXT[] := list_keyset_sort(params);
+    cnt     INT    := coalesce(json_array_length(result), 0);
+    lastRow JSON;
+
+BEGIN
+
+    IF sort IS NULL OR cnt = 0 OR cnt < COALESCE((params ->> 'per_page') :: INT, 1000)
+    THEN
+        RETURN NULL;
+    END IF;
+
+    lastRow = result -> (cnt - 1);
+    RETURN jsonb_build_object('value', lastRow -> sort[1], 'id', lastRow -> 'id');
+
+END
+
+$function$;
 
 DROP FUNCTION IF EXISTS build_query_part_for_list(params JSONB);
 CREATE OR REPLACE FUNCTION build_query_part_for_list(params JSONB)
@@ -18,9 +116,17 @@ DECLARE
     pageNum  INT;
     perPage  INT := COALESCE((params ->> 'per_page') :: INT, 1000);
     page     INT := COALESCE((params ->> 'page') :: INT, 1);
+    sort     TEXT[];
 
 BEGIN
 
+    -- курсорная пагинация: null всегда в конце, id - для однозначного порядка записей с одинаковым значением колонки
+    sort = CASE WHEN params ? 'cursor' THEN list_keyset_sort(params) END;
+    IF sort IS NOT NULL
+    THEN
+        RETURN format(' ORDER BY %1$s %2$s NULLS LAST, id %2$s LIMIT %3$s', sort[1], sort[2], perPage);
+    END IF;
+
     -- сборка сортировки
     IF (params ->> 'order_by') IS NOT NULL
     THEN
@@ -49,3 +49,32 @@ BEGIN
 END
 
 $function$;
+
+
+-- проверка, что фильтры, сортировка и поиск списка не используют поля, скрытые от пользователя по ролям (FldType.Roles)
+-- denied - поля из user_denied_flds, fldParams - поле: параметры, которые по нему фильтруют, например {"inn": ["inn", "search_text"]}
+-- сортировка проверяется по имени поля в order_by
+DROP FUNCTION IF EXISTS check_denied_params(params JSONB, denied TEXT [], fldParams JSONB);
+CREATE OR REPLACE FUNCTION check_denied_params(params JSONB, denied TEXT [], fldParams JSONB)
+  RETURNS JSON
+LANGUAGE plpgsql
+AS $function$
+
+DECLARE
+  fld TEXT;
+BEGIN
+
+  FOREACH fld IN ARRAY denied
+  LOOP
+    IF EXISTS(SELECT 1 FROM jsonb_array_elements_text(fldParams -> fld) p WHERE length(params ->> p) > 0)
+       OR (params ->> 'order_by') ~* concat('\m', fld, '\M')
+    THEN
+      RETURN json_build_object('ok', FALSE, 'message', concat('field is not available: ', fld));
+    END IF;
+  END LOOP;
+
+  RETURN NULL;
+
+END
+
+$function$;
@@ -66,7 +66,7 @@ $function$
 DECLARE
 BEGIN
     return jsonb_set(options, string_to_array(fldName, ''), coalesce(options -> fldName, '[]'::jsonb) ||
-                                                            (jsonObj || jsonb_build_object('user_id', userId, 'date', now() at time zone '[[Config.Postgres.TimeZone]]')));
+                                                            (jsonObj || jsonb_build_object('user_id', userId, 'date', now() at time zone '[[slot:pgTimeZone]]')));
 END
 $function$;
 
@@ -105,6 +105,20 @@ from (
 where row_number = num_days
 $function$ language sql;
 
+-- запрос для полнотекстового поиска (DocSql.IsFullTextSearch). Строку разбирает websearch_to_tsquery (слова через AND,
+-- "фраза", or, -слово), затем к каждому слову добавляется поиск по началу (:*), чтобы поиск работал при вводе части слова.
+-- Текст запроса экранирует сам postgres, поэтому спецсимволы во вводе не ломают запрос. Для пустой строки возвращает null
+DROP FUNCTION IF EXISTS search_tsquery(config regconfig, txt text);
+CREATE OR REPLACE FUNCTION search_tsquery(config regconfig, txt text)
+    RETURNS tsquery
+    LANGUAGE sql
+    IMMUTABLE
+AS
+$function$
+    SELECT CASE WHEN numnode(q) > 0 THEN regexp_replace(q::text, '''(\s|$)', ''':*\1', 'g')::tsquery END
+    FROM websearch_to_tsquery(config, coalesce(txt, '')) q;
+$function$;
+
 -- проверка, что пользователь имеет одну из ролей
 DROP FUNCTION IF EXISTS is_user_role(userId int, roles text[]);
 CREATE OR REPLACE FUNCTION is_user_role(userId int, roles text[])
@@ -118,6 +132,47 @@ BEGIN
 END;
 $function$;
 
+-- поля документа, недоступные пользователю по ролям (FldType.Roles). fldRoles - поле: роли, например {"inn": ["manager"]}
+-- admin и вызовы без пользователя (user_id пустой или -1) имеют доступ ко всем полям
+DROP FUNCTION IF EXISTS user_denied_flds(userId int, fldRoles jsonb);
+CREATE OR REPLACE FUNCTION user_denied_flds(userId int, fldRoles jsonb)
+    RETURNS text[]
+    LANGUAGE sql
+    STABLE
+AS
+$function$
+    SELECT coalesce(array_agg(f.key), '{}')
+    FROM jsonb_each(fldRoles) f
+    WHERE userId IS NOT NULL AND userId != -1
+      AND NOT is_user_role(userId, ARRAY ['admin'] || ARRAY(SELECT jsonb_array_elements_text(f.value)));
+$function$;
+
+-- удаление из записи полей, скрытых по ролям. denied - поля из user_denied_flds, fldKeys - поле: ключи записи, которые вычисляются
+-- из него, например {"manager_id": ["manager_title", "options.title.manager_title"]}. Ключ через точку - путь внутри jsonb
+DROP FUNCTION IF EXISTS jsonb_hide_flds(doc jsonb, denied text[], fldKeys jsonb);
+CREATE OR REPLACE FUNCTION jsonb_hide_flds(doc jsonb, denied text[], fldKeys jsonb)
+    RETURNS jsonb
+    LANGUAGE plpgsql
+    IMMUTABLE
+AS
+$function$
+DECLARE
+    k    text;
+    path text[];
+BEGIN
+    FOREACH k IN ARRAY denied || ARRAY(SELECT jsonb_array_elements_text(fldKeys -> f) FROM unnest(denied) f)
+        LOOP
+            path = string_to_array(k, '.');
+            -- путь удаляется только внутри объекта (например, в истории options хранится строкой)
+            IF jsonb_typeof(doc #> path[1:cardinality(path) - 1]) = 'object'
+            THEN
+                doc = doc #- path;
+            END IF;
+        END LOOP;
+    RETURN doc;
+END;
+$function$;
+
 -- проверка, что пользователь имеет одну из ролей
 DROP FUNCTION IF EXISTS is_admin(params jsonb);
 CREATE OR REPLACE FUNCTION is_admin(params jsonb)
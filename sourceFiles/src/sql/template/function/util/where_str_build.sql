@@ -4,6 +4,16 @@
 --     ['notQuoted', 'surveyId', 'q.survey_id']
 --   ])
 -- tableAlias - буква для названия таблицы для которой определяем свойство delete
+-- format - произвольное условие: m[3] - шаблон для format, в который подставляется значение параметра в кавычках. Пустое значение не учитывается
+--     ['format', 'search_text', 'doc.search_vector @@ search_tsquery(''russian'', %s)']
+-- операторы для фильтров списка (VueDocListFilter.Kind):
+--     ['gte', 'amount_gte', 'doc.amount'], ['lte', 'amount_lte', 'doc.amount'] - больше или равно / меньше или равно
+--     ['between', 'amount_between', 'doc.amount'] - параметр [с, по], любая из границ может быть null
+--     ['dateBetween', 'birth_date_between', 'doc.birth_date'] - период дат [с, по]: дата "по" входит целиком, включая время до конца дня
+--     ['in', 'status_in', 'doc.status'], ['notIn', 'status_not_in', 'doc.status'] - параметр массив значений
+--     ['isNull', 'phone_is_null', 'doc.phone'] - true - пустое, false - заполненное
+--     ['arrayContains', 'tags_contains', 'doc.tags'] - массив содержит все значения из параметра (значение или массив)
+--     ['jsonPath', 'level_option', 'doc.options -> ''level'''] - равенство значения jsonb (поля IsOptionFld)
 
 DROP FUNCTION IF EXISTS where_str_build(params JSONB, tableAlias VARCHAR, arr VARCHAR[]);
 CREATE OR REPLACE FUNCTION where_str_build(params JSONB, tableAlias VARCHAR, arr VARCHAR[])
@@ -88,6 +98,109 @@ BEGIN
                 END IF;
             END IF;
 
+            -- БОЛЬШЕ ИЛИ РАВНО / МЕНЬШЕ ИЛИ РАВНО
+            IF m[1] IN ('gte', 'lte')
+            THEN
+                IF length(params ->> m[2]) > 0
+                THEN
+                    whereStr = concat(whereStr, ' AND ', m[3], CASE WHEN m[1] = 'gte' THEN ' >= ' ELSE ' <= ' END,
+                                      quote_literal(params ->> m[2]));
+                END IF;
+            END IF;
+
+            -- ДИАПАЗОН [с, по]
+            IF m[1] = 'between'
+            THEN
+                IF jsonb_typeof(params -> m[2]) = 'array'
+                THEN
+                    IF length(params -> m[2] ->> 0) > 0
+                    THEN
+                        whereStr = concat(whereStr, ' AND ', m[3], ' >= ', quote_literal(params -> m[2] ->> 0));
+                    END IF;
+                    IF length(params -> m[2] ->> 1) > 0
+                    THEN
+                        whereStr = concat(whereStr, ' AND ', m[3], ' <= ', quote_literal(params -> m[2] ->> 1));
+                    END IF;
+                END IF;
+            END IF;
+
+            -- ПЕРИОД ДАТ [с, по]. Время в границах отбрасывается, дата "по" включается до конца дня
+            IF m[1] = 'dateBetween'
+            THEN
+                IF jsonb_typeof(params -> m[2]) = 'array'
+                THEN
+                    IF length(params -> m[2] ->> 0) > 0
+                    THEN
+                        whereStr = concat(whereStr, ' AND ', m[3], ' >= ', quote_literal(params -> m[2] ->> 0), '::date');
+                    END IF;
+                    IF length(params -> m[2] ->> 1) > 0
+                    THEN
+                        whereStr = concat(whereStr, ' AND ', m[3], ' < ', quote_literal(params -> m[2] ->> 1), '::date + interval ''1 day''');
+                    END IF;
+                END IF;
+            END IF;
+
+            -- ОДНО ИЗ ЗНАЧЕНИЙ / КРОМЕ ЗНАЧЕНИЙ. Сравнение в виде текста, чтобы подходило для колонок любого типа
+            IF m[1] IN ('in', 'notIn')
+            THEN
+                IF jsonb_typeof(params -> m[2]) = 'array' AND jsonb_array_length(params -> m[2]) > 0
+                THEN
+                    IF m[1] = 'in'
+                    THEN
+                        whereStr = concat(whereStr, ' AND ', m[3], '::text = ANY (',
+                                          quote_literal(text_array_from_json(params -> m[2])), '::text[])');
+                    ELSE
+                        -- пустые значения не входят в список, поэтому остаются в выборке
+                        whereStr = concat(whereStr, ' AND (', m[3], ' IS NULL OR NOT ', m[3], '::text = ANY (',
+                                          quote_literal(text_array_from_json(params -> m[2])), '::text[]))');
+                    END IF;
+                END IF;
+            END IF;
+
+            -- ПУСТОЕ / ЗАПОЛНЕННОЕ
+            IF m[1] = 'isNull'
+            THEN
+                IF (params ->> m[2]) IS NOT NULL
+                THEN
+                    whereStr = concat(whereStr, ' AND ', m[3],
+                                      CASE WHEN (params ->> m[2])::bool THEN ' IS NULL' ELSE ' IS NOT NULL' END);
+                END IF;
+            END IF;
+
+            -- МАССИВ СОДЕРЖИТ ЗНАЧЕНИЯ. Параметр - значение или массив значений
+            IF m[1] = 'arrayContains'
+            THEN
+                IF jsonb_typeof(params -> m[2]) = 'array'
+                THEN
+                    IF jsonb_array_length(params -> m[2]) > 0
+                    THEN
+                        whereStr = concat(whereStr, ' AND ', m[3], ' @> ',
+                                          quote_literal(text_array_from_json(params -> m[2])), '::text[]');
+                    END IF;
+                ELSIF length(params ->> m[2]) > 0
+                THEN
+                    whereStr = concat(whereStr, ' AND ', m[3], ' @> ', quote_literal(ARRAY [params ->> m[2]]), '::text[]');
+                END IF;
+            END IF;
+
+            -- РАВЕНСТВО ЗНАЧЕНИЯ JSONB. Тип значения сохраняется: строка, число, bool
+            IF m[1] = 'jsonPath'
+            THEN
+                IF jsonb_typeof(params -> m[2]) IS NOT NULL AND jsonb_typeof(params -> m[2]) != 'null'
+                THEN
+                    whereStr = concat(whereStr, ' AND ', m[3], ' = ', quote_literal(params -> m[2]), '::jsonb');
+                END IF;
+            END IF;
+
+            -- ПРОИЗВОЛЬНОЕ УСЛОВИЕ
+            IF m[1] = 'format'
+            THEN
+                IF length(params ->> m[2]) > 0
+                THEN
+                    whereStr = concat(whereStr, ' AND ', format(m[3], quote_literal(params ->> m[2])));
+                END IF;
+            END IF;
+
         END LOOP;
 
     RETURN whereStr;
@@ -28,7 +28,7 @@ BEGIN
 
     update task
     set (result, success_rate, state, date_completed) = (params ->> 'result', (params ->> 'success_rate')::int,
-                                                         'finished', now() at time zone '[[Config.Postgres.TimeZone]]')
+                                                         'finished', now() at time zone '[[slot:pgTimeZone]]')
     where id = (params ->> 'id')::int returning * into TaskRow;
 
     -- случай когда записи с таким id не найдено
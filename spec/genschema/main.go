@@ -0,0 +1,23 @@
+// генерация spec/schema.json: go generate ./spec
+package main
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/NL-A/nla_framework/spec"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatalf("usage: genschema <output file>")
+	}
+	data, err := spec.Schema()
+	if err != nil {
+		log.Fatalf("spec.Schema: %s", err)
+	}
+	if err = ioutil.WriteFile(os.Args[1], append(data, '\n'), 0644); err != nil {
+		log.Fatalf("write %s: %s", os.Args[1], err)
+	}
+}
@@ -0,0 +1,414 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/NL-A/nla_framework/types"
+	"gopkg.in/yaml.v3"
+)
+
+// Load чтение описания проекта из yaml или json файла и сборка ProjectType.
+// Результат можно передавать в nla_framework.Start так же, как проект, описанный в go коде
+func Load(path string) (types.ProjectType, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return types.ProjectType{}, err
+	}
+	p, err := Parse(data)
+	if err != nil {
+		return p, fmt.Errorf("%s: %s", path, err)
+	}
+	return p, nil
+}
+
+// Parse сборка ProjectType из содержимого yaml или json файла
+func Parse(data []byte) (types.ProjectType, error) {
+	s, err := Decode(data)
+	if err != nil {
+		return types.ProjectType{}, err
+	}
+	return Build(s)
+}
+
+// Decode чтение описания проекта без сборки ProjectType. json является подмножеством yaml, поэтому оба формата читаются одинаково.
+// Неизвестные поля считаются ошибкой, чтобы опечатки в описании не терялись молча
+func Decode(data []byte) (s ProjectSpec, err error) {
+	var raw interface{}
+	if err = yaml.Unmarshal(data, &raw); err != nil {
+		return
+	}
+	// yaml -> json, чтобы использовать json теги и регистронезависимое сопоставление полей для структур из пакета types
+	jsonData, err := json.Marshal(normalizeYaml(raw))
+	if err != nil {
+		return
+	}
+	dec := json.NewDecoder(bytes.NewReader(jsonData))
+	dec.DisallowUnknownFields()
+	err = dec.Decode(&s)
+	return
+}
+
+// Build сборка ProjectType из описания. Возвращает все найденные ошибки одним списком
+func Build(s ProjectSpec) (p types.ProjectType, err error) {
+	errs := []string{}
+	addErr := func(path, msg string, args ...interface{}) {
+		errs = append(errs, fmt.Sprintf("%s: %s", path, fmt.Sprintf(msg, args...)))
+	}
+
+	p = types.ProjectType{
+		Name:                     s.Name,
+		Config:                   s.Config,
+		Roles:                    s.Roles,
+		Vue:                      s.Vue,
+		Go:                       s.Go,
+		I18n:                     s.I18n,
+		OverridePathForTemplates: s.OverridePathForTemplates,
+		IsDebugMode:              s.IsDebugMode,
+		Slots:                    s.Slots,
+	}
+	p.Sql.InitialData = s.SqlInitialData
+
+	for _, ds := range s.Docs {
+		docPath := fmt.Sprintf("docs[%s]", ds.Name)
+		d := types.DocType{
+			Project:              &p,
+			Name:                 ds.Name,
+			NameRu:               ds.NameRu,
+			Type:                 ds.Type,
+			PathPrefix:           ds.PathPrefix,
+			IsTaskAllowed:        ds.IsTaskAllowed,
+			IsBaseTemplates:      types.DocIsBaseTemplates{Vue: true, Sql: true},
+			Integrations:         ds.Integrations,
+			TemplatePathOverride: ds.TemplatePathOverride,
+			I18n:                 ds.I18n,
+			Slots:                ds.Slots,
+		}
+		if ds.IsBaseTemplates != nil {
+			d.IsBaseTemplates = *ds.IsBaseTemplates
+		}
+		if len(ds.Templates) > 0 {
+			d.Templates = types.GetCustomTemplates(ds.Templates...)
+		}
+
+		// vue
+		v := ds.Vue
+		d.Vue = types.DocVue{
+			RouteName:           v.RouteName,
+			Routes:              v.Routes,
+			Path:                v.Path,
+			MenuIcon:            v.MenuIcon,
+			BreadcrumbIcon:      v.BreadcrumbIcon,
+			Roles:               v.Roles,
+			Mixins:              v.Mixins,
+			Components:          v.Components,
+			Vars:                v.Vars,
+			Methods:             v.Methods,
+			I18n:                v.I18n,
+			GloablI18n:          v.GloablI18n,
+			Tabs:                v.Tabs,
+			Hooks:               v.Hooks,
+			Readonly:            v.Readonly,
+			ListUrlQueryParams:  v.ListUrlQueryParams,
+			IsVueTitleClickable: v.IsVueTitleClickable,
+			IsHideDeleteOptions: v.IsHideDeleteOptions,
+			IsHideCreateNewBtn:  v.IsHideCreateNewBtn,
+			IsOpenNewInTab:      v.IsOpenNewInTab,
+			List:                v.List,
+			FilterList:          v.FilterList,
+			SortList:            v.SortList,
+			Breadcrumb:          v.Breadcrumb,
+		}
+		if d.Vue.Roles == nil {
+			d.Vue.Roles = []string{}
+		}
+		for funcName, name := range v.TmplFuncs {
+			f, ok := tmplFuncs[name]
+			if !ok {
+				addErr(docPath+".vue.tmplFuncs."+funcName, "unknown tmpl func '%s'. Register it with spec.RegisterTmplFunc", name)
+				continue
+			}
+			if d.Vue.TmplFuncs == nil {
+				d.Vue.TmplFuncs = map[string]func(types.DocType) string{}
+			}
+			d.Vue.TmplFuncs[funcName] = f
+		}
+
+		// поля
+		for i, fs := range ds.Flds {
+			fld, fldErrs := buildFld(fs, fmt.Sprintf("%s.flds[%v]", docPath, i))
+			errs = append(errs, fldErrs...)
+			d.Flds = append(d.Flds, fld)
+		}
+		if len(ds.RecursionTitle) > 0 {
+			d.SetIsRecursion(ds.RecursionTitle)
+		}
+
+		// sql
+		q := ds.Sql
+		d.Sql = types.DocSql{
+			IsUniqLink:           q.IsUniqLink,
+			IsBeforeTrigger:      q.IsBeforeTrigger,
+			IsAfterTrigger:       q.IsAfterTrigger,
+			IsNotifyEventTrigger: q.IsNotifyEventTrigger,
+			CustomTriggers:       q.CustomTriggers,
+			IsSearchText:         q.IsSearchText,
+			Indexes:              q.Indexes,
+			Hooks:                q.Hooks,
+			CheckConstrains:      q.CheckConstrains,
+			UniqConstrains:       q.UniqConstrains,
+			PrevNames:            q.PrevNames,
+			IsHistory:            q.IsHistory,
+			AccessRules:          q.AccessRules,
+			IsFullTextSearch:     q.IsFullTextSearch,
+			FullTextSearchConfig: q.FullTextSearchConfig,
+		}
+		if ds.IsBaseMethods == nil || *ds.IsBaseMethods {
+			d.Sql.FillBaseMethods(d.Name, ds.Roles...)
+		}
+		for _, m := range q.Methods {
+			if d.Sql.Methods == nil {
+				d.Sql.Methods = map[string]*types.DocSqlMethod{}
+			}
+			d.Sql.Methods[m.Name] = &types.DocSqlMethod{Name: m.Name, Roles: m.Roles, Params: m.Params, Tmpl: types.DocSqlMethodTmpl{Source: m.Source, Dist: m.Dist}}
+		}
+
+		d.Init()
+
+		// state machine заполняется после Init, потому что ссылается на поля документа
+		if ds.StateMachine != nil {
+			d.StateMachine = buildSm(d, *ds.StateMachine)
+			params := map[string]interface{}{}
+			for k, v := range ds.StateMachine.TmplParams {
+				params[k] = v
+			}
+			d.StateMachine.GenerateTmpls(&d, params)
+		}
+
+		p.Docs = append(p.Docs, d)
+	}
+
+	if len(p.Vue.Menu) > 0 {
+		p.FillSideMenu()
+	}
+	p.FillVueBaseRoutes()
+
+	if len(errs) > 0 {
+		err = errors.New(strings.Join(errs, "\n"))
+	}
+	return
+}
+
+// сборка поля через соответствующую функцию GetFld..., затем применяются модификаторы
+func buildFld(fs FldSpec, path string) (fld types.FldType, errs []string) {
+	params := append([]string{fs.Class}, fs.Params...)
+	switch fs.Type {
+	case "title":
+		fld = types.GetFldTitle(params...)
+	case types.FldTypeString, "":
+		fld = types.GetFldString(fs.Name, fs.NameRu, fs.Size, nil, params...)
+	case types.FldTypeText:
+		fld = types.GetFldString(fs.Name, fs.NameRu, 0, nil, params...)
+	case types.FldTypeInt:
+		fld = types.GetFldInt(fs.Name, fs.NameRu, nil, params...)
+	case types.FldTypeInt64:
+		fld = types.GetFldInt64(fs.Name, fs.NameRu, nil, params...)
+	case types.FldTypeDouble:
+		fld = types.GetFldDouble(fs.Name, fs.NameRu, nil, params...)
+	case types.FldTypeDate:
+		fld = types.GetFldDate(fs.Name, fs.NameRu, nil, params...)
+	case types.FldTypeDatetime:
+		fld = types.GetFldDateTime(fs.Name, fs.NameRu, nil, params...)
+	case types.FldTypeUuid:
+		fld = types.GetFldUuid(fs.Name, fs.NameRu, nil, params...)
+	case types.FldVueTypeCheckbox:
+		fld = types.GetFldCheckbox(fs.Name, fs.NameRu, nil, params...)
+	case types.FldVueTypeRadio:
+		fld = types.GetFldRadioString(fs.Name, fs.NameRu, nil, fs.Options, params...)
+	case types.FldVueTypeSelect:
+		fld = types.GetFldSelectString(fs.Name, fs.NameRu, fs.Size, nil, fs.Options, params...)
+	case types.FldVueTypeMultipleSelect:
+		fld = types.GetFldSelectMultiple(fs.Name, fs.NameRu, nil, fs.Options, params...)
+	case "ref":
+		if len(fs.Ref) == 0 {
+			errs = append(errs, fmt.Sprintf("%s: field with type 'ref' must have 'ref'", path))
+		}
+		fld = types.GetFldRef(fs.Name, fs.NameRu, fs.Ref, nil, params...)
+	case types.FldVueTypePhone:
+		fld = types.GetFldPhone(fs.Name, fs.NameRu, nil, params...)
+	case types.FldVueTypeEmail:
+		fld = types.GetFldEmail(fs.Name, fs.NameRu, nil, params...)
+	case "tag":
+		fld = types.GetFldTag(fs.Name, fs.NameRu, nil, params...)
+	case types.FldVueTypeDadataAddress:
+		fld = types.GetFldDadataAddress(fs.Name, fs.NameRu, nil, params...)
+	case types.FldVueTypeJsonList:
+		listParams := types.FldVueJsonList{}
+		if fs.JsonList != nil {
+			listParams.Icon = fs.JsonList.Icon
+			for i, v := range fs.JsonList.Flds {
+				f, fErrs := buildFld(v, fmt.Sprintf("%s.jsonList.flds[%v]", path, i))
+				errs = append(errs, fErrs...)
+				listParams.Flds = append(listParams.Flds, f)
+			}
+		}
+		fld = types.GetFldJsonList(fs.Name, fs.NameRu, nil, listParams, params...)
+	case types.FldVueTypeFiles:
+		fld = types.GetFldFiles(fs.Name, fs.NameRu, nil, types.FldVueFilesParams{Accept: fs.FileParams.Accept, MaxFileSize: fs.FileParams.MaxFileSize}, params...)
+	case types.FldVueTypeImg:
+		fld = types.GetFldImg(fs.Name, fs.NameRu, nil, fs.FileParams, params...)
+	case types.FldVueTypeImgList:
+		fld = types.GetFldImgList(fs.Name, fs.NameRu, nil, fs.FileParams, params...)
+	case "composition":
+		switch {
+		case len(fs.Composition) > 0:
+			f, ok := compositions[fs.Composition]
+			if !ok {
+				errs = append(errs, fmt.Sprintf("%s: unknown composition '%s'. Register it with spec.RegisterComposition", path, fs.Composition))
+			}
+			fld = types.GetFldJsonbCompositionWithoutFld(nil, fs.Class, "")
+			if len(fs.Name) > 0 {
+				fld.Name, fld.NameRu, fld.Type = fs.Name, fs.NameRu, types.FldTypeJsonb
+			}
+			fld.Vue.Composition = f
+		case len(fs.Component) > 0 && len(fs.Name) > 0:
+			fld = types.GetFldJsonbComposition(fs.Name, fs.NameRu, nil, fs.Class, fs.Component, fs.Params...)
+		case len(fs.Component) > 0:
+			fld = types.GetFldJsonbCompositionWithoutFld(nil, fs.Class, fs.Component, fs.Params...)
+		default:
+			errs = append(errs, fmt.Sprintf("%s: field with type 'composition' must have 'composition' or 'component'", path))
+		}
+	case "html":
+		fld = types.GetFldSimpleHtml(nil, fs.Class, fs.Html)
+	default:
+		errs = append(errs, fmt.Sprintf("%s: unknown field type '%s'", path, fs.Type))
+		fld = types.FldType{Name: fs.Name, NameRu: fs.NameRu}
+	}
+
+	if len(fs.RowCol) > 0 {
+		fld = fld.SetRowCol(fs.RowCol...)
+	}
+	if len(fs.NameRu) > 0 && fs.Type == "title" {
+		fld.NameRu = fs.NameRu
+	}
+	if fs.Type == "title" && len(fs.FillValueInBeforeTrigger) > 0 {
+		// аналог GetFldTitleComputed
+		fld.Sql.IsRequired, fld.Sql.IsUniq, fld.Sql.Size = false, false, 0
+	}
+	if fs.IsRequired {
+		fld = fld.SetIsRequired()
+	}
+	if fs.IsUniq {
+		fld = fld.SetIsUniq()
+	}
+	if fs.IsNotUniq {
+		fld = fld.SetIsNotUniq()
+	}
+	if fs.IsSearch {
+		fld = fld.SetIsSearch()
+	}
+	if fs.IsOptionFld {
+		fld = fld.SetIsOptionFld()
+	}
+	if fs.IsNotUpdatable {
+		fld = fld.SetIsNotUpdatable()
+	}
+	if fs.IsHide {
+		fld = fld.SetIsHide()
+	}
+	if len(fs.Default) > 0 {
+		fld = fld.SetDefault(fs.Default)
+	}
+	if len(fs.SearchWeight) > 0 {
+		fld = fld.SetSearchWeight(fs.SearchWeight)
+	}
+	if len(fs.PrevNames) > 0 {
+		fld = fld.SetPrevNames(fs.PrevNames...)
+	}
+	if len(fs.FillValueInBeforeTrigger) > 0 {
+		fld.Sql.FillValueInBeforeTrigger = fs.FillValueInBeforeTrigger
+	}
+	if len(fs.RefFldsForOptions) > 0 {
+		fld = fld.AddRefFldsForOptions(fs.RefFldsForOptions...)
+	}
+	if fs.Size > 0 && fs.Type != types.FldTypeString && fs.Type != types.FldVueTypeSelect {
+		fld = fld.SetSqlSize(fs.Size)
+	}
+	if len(fs.Readonly) > 0 {
+		fld = fld.SetReadonly(fs.Readonly)
+	}
+	if len(fs.Vif) > 0 {
+		fld = fld.SetVif(fs.Vif)
+	}
+	if len(fs.ReadRoles) > 0 {
+		fld = fld.SetReadRoles(fs.ReadRoles...)
+	}
+	if len(fs.WriteRoles) > 0 {
+		fld = fld.SetWriteRoles(fs.WriteRoles...)
+	}
+	for k, v := range fs.Ext {
+		fld = fld.AddVueExt(k, v)
+	}
+	if fs.Bitrix != nil {
+		fld = fld.SetBitrixInfo(*fs.Bitrix)
+	}
+	if fs.Odata != nil {
+		fld = fld.SetOdataInfo(*fs.Odata)
+	}
+	return
+}
+
+// state machine. Поля в UpdateFlds указываются по имени и берутся из документа
+func buildSm(d types.DocType, s SmSpec) *types.DocSm {
+	getFlds := func(names []string) []types.FldType {
+		var res []types.FldType
+		for _, name := range names {
+			res = append(res, *d.Fld(name))
+		}
+		return res
+	}
+	sm := &types.DocSm{Tmpls: s.Tmpls}
+	for _, st := range s.States {
+		state := &types.DocSmState{Title: st.Title, TitleRu: st.TitleRu, IconSrc: st.IconSrc, IsFinal: st.IsFinal, UpdateFlds: getFlds(st.UpdateFlds)}
+		for _, a := range st.Actions {
+			state.Actions = append(state.Actions, types.DocSmAction{
+				From:       a.From,
+				To:         a.To,
+				Label:      a.Label,
+				Icon:       a.Icon,
+				UpdateFlds: getFlds(a.UpdateFlds),
+				Conditions: a.Conditions,
+				Hooks:      a.Hooks,
+			})
+		}
+		sm.States = append(sm.States, state)
+	}
+	return sm
+}
+
+// yaml может вернуть map[interface{}]interface{}, который не сериализуется в json
+func normalizeYaml(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, v1 := range val {
+			val[k] = normalizeYaml(v1)
+		}
+		return val
+	case map[interface{}]interface{}:
+		res := map[string]interface{}{}
+		for k, v1 := range val {
+			res[fmt.Sprintf("%v", k)] = normalizeYaml(v1)
+		}
+		return res
+	case []interface{}:
+		for i, v1 := range val {
+			val[i] = normalizeYaml(v1)
+		}
+		return val
+	}
+	return v
+}
@@ -0,0 +1,37 @@
+package spec
+
+import (
+	"sort"
+
+	"github.com/NL-A/nla_framework/types"
+)
+
+// функции, которые нельзя описать в yaml/json. Регистрируются в go коде проекта до загрузки описания,
+// а в файле описания указывается только имя функции
+var (
+	compositions = map[string]func(types.ProjectType, types.DocType, types.FldType) string{}
+	tmplFuncs    = map[string]func(types.DocType) string{}
+)
+
+// RegisterComposition регистрация функции для FldVue.Composition. В описании поля: composition: <name>
+func RegisterComposition(name string, f func(types.ProjectType, types.DocType, types.FldType) string) {
+	compositions[name] = f
+}
+
+// RegisterTmplFunc регистрация функции для DocVue.TmplFuncs. В описании документа: vue.tmplFuncs: {PrintListRowLabel: <name>}
+func RegisterTmplFunc(name string, f func(types.DocType) string) {
+	tmplFuncs[name] = f
+}
+
+// RegisteredNames списки зарегистрированных функций. Нужны для вывода в сообщениях об ошибках
+func RegisteredNames() (compositionNames, tmplFuncNames []string) {
+	for k := range compositions {
+		compositionNames = append(compositionNames, k)
+	}
+	for k := range tmplFuncs {
+		tmplFuncNames = append(tmplFuncNames, k)
+	}
+	sort.Strings(compositionNames)
+	sort.Strings(tmplFuncNames)
+	return
+}
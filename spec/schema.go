@@ -0,0 +1,85 @@
+package spec
+
+//go:generate go run ./genschema schema.json
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/NL-A/nla_framework/utils"
+)
+
+// SchemaId адрес опубликованной схемы (ветка master). В yaml файле схему можно подключить строкой
+// # yaml-language-server: $schema=<путь к schema.json>. nla init кладет копию схемы рядом с project.yaml
+const SchemaId = "https://raw.githubusercontent.com/NL-A/nla_framework/master/spec/schema.json"
+
+// Schema JSON Schema для файла с описанием проекта. Строится по структурам ProjectSpec, чтобы схема не расходилась с загрузчиком
+func Schema() ([]byte, error) {
+	defs := map[string]interface{}{}
+	root := schemaForType(reflect.TypeOf(ProjectSpec{}), defs)
+	root["$schema"] = "http://json-schema.org/draft-07/schema#"
+	root["$id"] = SchemaId
+	root["title"] = "nla_framework project"
+	root["definitions"] = defs
+	return json.MarshalIndent(root, "", "  ")
+}
+
+func schemaForType(t reflect.Type, defs map[string]interface{}) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return schemaForType(t.Elem(), defs)
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": schemaForType(t.Elem(), defs)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaForType(t.Elem(), defs)}
+	case reflect.Struct:
+		// именованные структуры выносим в definitions, в том числе из-за рекурсии (FldSpec.JsonList.Flds)
+		if len(t.Name()) > 0 {
+			if _, ok := defs[t.Name()]; !ok {
+				defs[t.Name()] = map[string]interface{}{} // заглушка на время обхода
+				defs[t.Name()] = schemaForStruct(t, defs)
+			}
+			return map[string]interface{}{"$ref": "#/definitions/" + t.Name()}
+		}
+		return schemaForStruct(t, defs)
+	}
+	// interface{} - любое значение
+	return map[string]interface{}{}
+}
+
+func schemaForStruct(t reflect.Type, defs map[string]interface{}) map[string]interface{} {
+	props := map[string]interface{}{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if len(f.PkgPath) > 0 || f.Type.Kind() == reflect.Func || f.Type.Kind() == reflect.Chan {
+			continue
+		}
+		// в описании нет смысла ссылаться на функции (template.FuncMap и т.п.)
+		if f.Type.Kind() == reflect.Map && f.Type.Elem().Kind() == reflect.Func {
+			continue
+		}
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if len(name) == 0 {
+			// поля без тегов (структуры из пакета types) читаются регистронезависимо, в схеме указываем lowerCamelCase
+			name = utils.LowerCaseFirst(f.Name)
+		}
+		prop := schemaForType(f.Type, defs)
+		if enum := f.Tag.Get("enum"); len(enum) > 0 {
+			prop["enum"] = strings.Split(enum, ",")
+		}
+		props[name] = prop
+	}
+	return map[string]interface{}{"type": "object", "properties": props, "additionalProperties": false}
+}
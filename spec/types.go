@@ -0,0 +1,165 @@
+package spec
+
+import "github.com/NL-A/nla_framework/types"
+
+// описание проекта в yaml/json файле. Названия полей в файле - lowerCamelCase (см schema.json).
+// Вложенные структуры из пакета types (config, vue, go и пр) читаются как есть - имена их полей тоже пишутся в lowerCamelCase
+type (
+	ProjectSpec struct {
+		Name                     string              `json:"name"`
+		Config                   types.ProjectConfig `json:"config"`
+		Roles                    []types.ProjectRole `json:"roles"`
+		Vue                      types.ProjectVue    `json:"vue"` // в том числе боковое меню vue.menu
+		Go                       types.ProjectGo     `json:"go"`
+		SqlInitialData           []string            `json:"sqlInitialData"`
+		I18n                     types.I18nType      `json:"i18n"`
+		OverridePathForTemplates map[string]string   `json:"overridePathForTemplates"`
+		IsDebugMode              bool                `json:"isDebugMode"`
+		Docs                     []DocSpec           `json:"docs"`
+		Slots                    map[string][]string `json:"slots"` // содержимое именованных слотов в копируемых файлах (см types.SlotType)
+	}
+
+	DocSpec struct {
+		Name                 string                            `json:"name"`
+		NameRu               string                            `json:"nameRu"`
+		Type                 string                            `json:"type"`
+		PathPrefix           string                            `json:"pathPrefix"`
+		IsBaseTemplates      *types.DocIsBaseTemplates         `json:"isBaseTemplates"` // по умолчанию генерируются и vue, и sql шаблоны
+		IsBaseMethods        *bool                             `json:"isBaseMethods"`   // по умолчанию заполняются стандартные методы (list, get_by_id, update...)
+		Roles                []string                          `json:"roles"`           // роли для стандартных методов
+		IsTaskAllowed        bool                              `json:"isTaskAllowed"`
+		RecursionTitle       string                            `json:"recursionTitle"` // если заполнено, то документ рекурсивный (см DocType.SetIsRecursion)
+		Flds                 []FldSpec                         `json:"flds"`
+		Vue                  DocVueSpec                        `json:"vue"`
+		Sql                  DocSqlSpec                        `json:"sql"`
+		StateMachine         *SmSpec                           `json:"stateMachine"`
+		Integrations         types.DocIntegrations             `json:"integrations"`
+		Templates            []string                          `json:"templates"` // кастомные шаблоны документа (см GetCustomTemplates)
+		TemplatePathOverride map[string]types.TmplPathOverride `json:"templatePathOverride"`
+		I18n                 map[string]map[string]string      `json:"i18n"`
+		Slots                map[string][]string               `json:"slots"`
+	}
+
+	FldSpec struct {
+		Name   string `json:"name"`
+		NameRu string `json:"nameRu"`
+		// тип поля. Соответствует функциям GetFld...: title, string, text, int, int64, double, date, datetime, uuid, checkbox, radio,
+		// select, multipleSelect, ref, phone, email, tag, dadataAddress, jsonList, files, img, imgList, composition, html
+		Type                     string                    `json:"type" enum:"title,string,text,int,int64,double,date,datetime,uuid,checkbox,radio,select,multipleSelect,ref,phone,email,tag,dadataAddress,jsonList,files,img,imgList,composition,html"`
+		Size                     int                       `json:"size"`
+		RowCol                   []int                     `json:"rowCol"` // строка, колонка и (необязательно) ширина колонки, как в SetRowCol
+		Class                    string                    `json:"class"`
+		Params                   []string                  `json:"params"` // дополнительные параметры функции GetFld... Например isShowLink, isAddNew, isClearable, readonly:true
+		Ref                      string                    `json:"ref"`
+		Options                  []types.FldVueOptionsItem `json:"options"`
+		IsRequired               bool                      `json:"isRequired"`
+		IsUniq                   bool                      `json:"isUniq"`
+		IsNotUniq                bool                      `json:"isNotUniq"`
+		IsSearch                 bool                      `json:"isSearch"`
+		IsOptionFld              bool                      `json:"isOptionFld"`
+		IsHide                   bool                      `json:"isHide"`
+		IsNotUpdatable           bool                      `json:"isNotUpdatable"`
+		Default                  string                    `json:"default"`
+		PrevNames                []string                  `json:"prevNames"`    // прежние названия колонки (см types.FldSql.PrevNames)
+		SearchWeight             string                    `json:"searchWeight"` // вес в полнотекстовом поиске: A, B, C, D (см types.FldSql.SearchWeight)
+		FillValueInBeforeTrigger string                    `json:"fillValueInBeforeTrigger"`
+		RefFldsForOptions        []string                  `json:"refFldsForOptions"`
+		Readonly                 string                    `json:"readonly"`
+		Vif                      string                    `json:"vif"`
+		ReadRoles                []string                  `json:"readRoles"`  // роли, которым поле видно (см types.FldRoles)
+		WriteRoles               []string                  `json:"writeRoles"` // роли, которым поле можно изменять (см types.FldRoles)
+		Ext                      map[string]string         `json:"ext"`
+		Composition              string                    `json:"composition"` // для composition - имя функции, зарегистрированной через RegisterComposition
+		Component                string                    `json:"component"`   // для composition - имя vue компоненты (см GetFldJsonbComposition), если функция не указана
+		Html                     string                    `json:"html"`        // для html - разметка
+		JsonList                 *JsonListSpec             `json:"jsonList"`
+		FileParams               types.FldVueImgParams     `json:"fileParams"` // для files, img, imgList
+		Bitrix                   *types.BitrixFld          `json:"bitrix"`
+		Odata                    *types.OdataFld           `json:"odata"`
+	}
+
+	JsonListSpec struct {
+		Icon string    `json:"icon"`
+		Flds []FldSpec `json:"flds"`
+	}
+
+	DocVueSpec struct {
+		RouteName           string                       `json:"routeName"`
+		Routes              [][]string                   `json:"routes"`
+		Path                string                       `json:"path"`
+		MenuIcon            string                       `json:"menuIcon"`
+		BreadcrumbIcon      string                       `json:"breadcrumbIcon"`
+		Roles               []string                     `json:"roles"`
+		Mixins              map[string][]types.VueMixin  `json:"mixins"`
+		Components          map[string]map[string]string `json:"components"`
+		Vars                map[string]map[string]string `json:"vars"`
+		Methods             map[string]map[string]string `json:"methods"`
+		TmplFuncs           map[string]string            `json:"tmplFuncs"` // название функции шаблона - имя функции, зарегистрированной через RegisterTmplFunc
+		I18n                map[string]string            `json:"i18n"`
+		GloablI18n          map[string]map[string]string `json:"globalI18n"`
+		Tabs                []types.VueTab               `json:"tabs"`
+		Hooks               types.DocVueHooks            `json:"hooks"`
+		Readonly            string                       `json:"readonly"`
+		ListUrlQueryParams  []string                     `json:"listUrlQueryParams"`
+		IsVueTitleClickable bool                         `json:"isVueTitleClickable"`
+		IsHideDeleteOptions bool                         `json:"isHideDeleteOptions"`
+		IsHideCreateNewBtn  bool                         `json:"isHideCreateNewBtn"`
+		IsOpenNewInTab      bool                         `json:"isOpenNewInTab"`
+		List                types.VueDocList             `json:"list"`
+		FilterList          []types.VueDocListFilter     `json:"filterList"`
+		SortList            []types.VueDocListSort       `json:"sortList"`
+		Breadcrumb          string                       `json:"breadcrumb"`
+	}
+
+	DocSqlSpec struct {
+		Methods              []SqlMethodSpec                   `json:"methods"` // методы в дополнение к стандартным
+		IsUniqLink           bool                              `json:"isUniqLink"`
+		IsBeforeTrigger      bool                              `json:"isBeforeTrigger"`
+		IsAfterTrigger       bool                              `json:"isAfterTrigger"`
+		IsNotifyEventTrigger bool                              `json:"isNotifyEventTrigger"`
+		CustomTriggers       []string                          `json:"customTriggers"`
+		IsSearchText         bool                              `json:"isSearchText"`
+		Indexes              []string                          `json:"indexes"`
+		Hooks                types.DocSqlHooks                 `json:"hooks"`
+		CheckConstrains      []types.DocSqlCheckConstraint     `json:"checkConstrains"`
+		UniqConstrains       []types.DocSqlUniqConstraint      `json:"uniqConstrains"`
+		PrevNames            []string                          `json:"prevNames"`            // прежние названия таблицы (см types.DocSql.PrevNames)
+		IsHistory            bool                              `json:"isHistory"`            // история изменений (см types.DocSql.IsHistory)
+		AccessRules          map[string]types.DocSqlAccessRule `json:"accessRules"`          // доступ к записям по ролям (см types.DocSql.AccessRules)
+		IsFullTextSearch     bool                              `json:"isFullTextSearch"`     // полнотекстовый поиск (см types.DocSql.IsFullTextSearch)
+		FullTextSearchConfig string                            `json:"fullTextSearchConfig"` // по умолчанию russian
+	}
+
+	SqlMethodSpec struct {
+		Name   string            `json:"name"`
+		Roles  []string          `json:"roles"`
+		Params map[string]string `json:"params"`
+		Source string            `json:"source"` // путь к шаблону sql функции
+		Dist   string            `json:"dist"`
+	}
+
+	SmSpec struct {
+		States     []SmStateSpec     `json:"states"`
+		Tmpls      types.DocSmTmpls  `json:"tmpls"`
+		TmplParams map[string]string `json:"tmplParams"` // параметры для DocSm.GenerateTmpls: cardTmplPath, actionBtnPath
+	}
+
+	SmStateSpec struct {
+		Title      string         `json:"title"`
+		TitleRu    string         `json:"titleRu"`
+		IconSrc    string         `json:"iconSrc"`
+		IsFinal    bool           `json:"isFinal"`    // конечное состояние, переходов из него нет. Состояние без переходов считается конечным и без флага
+		UpdateFlds []string       `json:"updateFlds"` // имена полей документа, которые можно редактировать в этом стейте
+		Actions    []SmActionSpec `json:"actions"`
+	}
+
+	SmActionSpec struct {
+		From       string                       `json:"from"`
+		To         string                       `json:"to"`
+		Label      string                       `json:"label"`
+		Icon       string                       `json:"icon"`
+		UpdateFlds []string                     `json:"updateFlds"` // имена полей документа, которые заполняются при смене стейта
+		Conditions []types.DocSmActionCondition `json:"conditions"`
+		Hooks      types.DocSmActionlHooks      `json:"hooks"`
+	}
+)
@@ -0,0 +1,47 @@
+package templates
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/NL-A/nla_framework/types"
+	"github.com/NL-A/nla_framework/utils"
+	"github.com/serenize/snaker"
+)
+
+// история изменений документа: таблица <doc>_history, которую заполняет триггер doc_history, и метод <doc>_history_list.
+// Таб "история" добавляется в d.Init (см DocSql.IsHistory)
+func docIsHistoryProccess(p types.ProjectType, d *types.DocType, docIndex int) {
+	readTmpl := func(path, name string) *template.Template {
+		sourcePath := getCurrentDir() + path + name
+		// проверяем возможность того, что путь к шаблону был переопределен внутри документа
+		if d.TemplatePathOverride != nil {
+			if tmpl, ok := d.TemplatePathOverride[name]; ok {
+				if len(tmpl.Source) > 0 {
+					sourcePath = tmpl.Source
+				}
+			}
+		}
+		t, err := utils.ParseTemplateFiles(template.New(name).Funcs(funcMap), sourcePath)
+		utils.CheckErr(err, name)
+		return t
+	}
+	// модель таблицы истории кладем рядом с моделью документа, чтобы она создавалась после основной таблицы
+	distPath, _ := utils.ParseDocTemplateFilename(d.Name, "sql_main.toml", p.DistPath, docIndex, nil)
+	d.Templates["sql_history_main.toml"] = &types.DocTemplate{Tmpl: readTmpl("/sql/", "history.toml"), DistPath: distPath + "History", DistFilename: "main.toml"}
+
+	methodName := d.PgName() + "_history_list"
+	distPath = fmt.Sprintf("%s/sql/template/function/_%s", p.DistPath, snaker.SnakeToCamel(d.Name))
+	d.Templates["sql_function_history_list.sql"] = &types.DocTemplate{Tmpl: readTmpl("/sql/function/", "history_list.sql"), DistPath: distPath, DistFilename: methodName + ".sql"}
+	// добавляем в список sql методов. История доступна тем же ролям, что и просмотр документа
+	if d.Sql.Methods == nil {
+		d.Sql.Methods = map[string]*types.DocSqlMethod{}
+	}
+	if _, ok := d.Sql.Methods[methodName]; !ok {
+		roles := []string{}
+		if m, ok := d.Sql.Methods[d.Name+"_get_by_id"]; ok {
+			roles = m.Roles
+		}
+		d.Sql.Methods[methodName] = &types.DocSqlMethod{Name: methodName, Roles: roles}
+	}
+}
@@ -18,7 +18,7 @@ func docIsRecursionProccess(p types.ProjectType, d *types.DocType) {
 			}
 		}
 	}
-	t, err := template.New("recursiveChildList.vue").Funcs(funcMap).Delims("[[", "]]").ParseFiles(sourcePath)
+	t, err := utils.ParseTemplateFiles(template.New("recursiveChildList.vue").Funcs(funcMap).Delims("[[", "]]"), sourcePath)
 	utils.CheckErr(err, "recursiveChildList.vue")
 	docRouteName := d.Name
 	if len(d.Vue.Path) > 0 {
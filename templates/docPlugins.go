@@ -0,0 +1,49 @@
+package templates
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/NL-A/nla_framework/types"
+	"github.com/NL-A/nla_framework/utils"
+)
+
+// функции для шаблонов из включенных плагинов
+func pluginsFuncMapProccess(p types.ProjectType) {
+	for _, pl := range p.EnabledPlugins() {
+		for k, v := range pl.FuncMap(p) {
+			funcMap[k] = v
+		}
+	}
+}
+
+// шаблоны документа из включенных плагинов (интеграции с Битрикс, 1С и пр)
+func docPluginsProccess(p types.ProjectType, d *types.DocType) {
+	for _, pl := range p.EnabledPlugins() {
+		for tName, dt := range pl.DocTemplates(p, *d) {
+			// функции плагина для шаблона расширяем стандартными функциями
+			fMap := template.FuncMap{}
+			for k, v := range funcMap {
+				fMap[k] = v
+			}
+			for k, v := range dt.FuncMap {
+				fMap[k] = v
+			}
+			_, fName := utils.PathExtractFilename(dt.Source)
+			t, err := utils.ParseTemplateFiles(template.New(fName).Funcs(fMap).Delims("[[", "]]"), dt.Source)
+			utils.CheckErr(err, fmt.Sprintf("plugin '%s' doc: %s tmpl: %s", pl.Name(), d.Name, tName))
+			dt.Tmpl = t
+			d.Templates[tName] = dt
+		}
+	}
+}
+
+// файлы проекта из включенных плагинов
+func writePluginsProjectFiles(p types.ProjectType) {
+	for _, pl := range p.EnabledPlugins() {
+		for _, t := range pl.ProjectTemplates(p) {
+			distPath, filename := utils.PathExtractFilename(t.Dist)
+			ReadTmplAndPrint(p, t.Source, distPath, filename, t.FuncMap)
+		}
+	}
+}
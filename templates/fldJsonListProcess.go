@@ -28,7 +28,7 @@ func fldJsonListProccess(p types.ProjectType, d *types.DocType, fld *types.FldTy
 				}
 			}
 		}
-		t, err := template.New("fldJsonList.vue").Funcs(funcMap).Delims("[[", "]]").ParseFiles(sourcePath)
+		t, err := utils.ParseTemplateFiles(template.New("fldJsonList.vue").Funcs(funcMap).Delims("[[", "]]"), sourcePath)
 		utils.CheckErr(err, "fldJsonList.vue")
 		dPath := d.Name
 		if len(d.Vue.Path) > 0 {
@@ -43,7 +43,7 @@ func fldJsonListProccess(p types.ProjectType, d *types.DocType, fld *types.FldTy
 		if d.TemplatePathOverride != nil {
 			if tmpl, ok := d.TemplatePathOverride["fldJsonList.vue"]; ok {
 				if len(tmpl.Dist) > 0 {
-					distPath = tmpl.Dist
+					distPath = p.ReplaceDefaultDistPath(tmpl.Dist)
 				}
 			}
 		}
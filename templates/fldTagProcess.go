@@ -29,7 +29,7 @@ func fldTagProccess(p types.ProjectType, d *types.DocType, fld *types.FldType) {
 				}
 			}
 		}
-		t, err := template.New("tag_list.sql").Funcs(funcMap).ParseFiles(sourcePath)
+		t, err := utils.ParseTemplateFiles(template.New("tag_list.sql").Funcs(funcMap), sourcePath)
 		utils.CheckErr(err, "tag_list.sql")
 		distPath := fmt.Sprintf("%s/sql/template/function/_%s", p.DistPath, snaker.SnakeToCamel(d.Name))
 		d.Templates["sql_function_"+fld.Name+"_tag_list.sql"] = &types.DocTemplate{Tmpl: t, DistPath: distPath, DistFilename: methodName + ".sql"}
@@ -39,7 +39,7 @@ func fldTagProccess(p types.ProjectType, d *types.DocType, fld *types.FldType) {
 		}
 		d.Sql.Methods[methodName] = &types.DocSqlMethod{Name: methodName}
 		// читаем шаблон и генерим файл с mixin
-		t, err = template.New("mixinTag.js").Funcs(funcMap).Delims("[[", "]]").ParseFiles(fmt.Sprintf("%s/webClient/quasar_%v/doc/mixinTag.js", getCurrentDir(), p.GetQuasarVersion()))
+		t, err = utils.ParseTemplateFiles(template.New("mixinTag.js").Funcs(funcMap).Delims("[[", "]]"), fmt.Sprintf("%s/webClient/quasar_%v/doc/mixinTag.js", getCurrentDir(), p.GetQuasarVersion()))
 		utils.CheckErr(err, "mixinTag.js")
 		distPath = fmt.Sprintf("%s/webClient/src/app/components/%s/mixins", p.DistPath, d.Name)
 		// в случае табов изменяем path
@@ -50,7 +50,7 @@ func fldTagProccess(p types.ProjectType, d *types.DocType, fld *types.FldType) {
 		if d.TemplatePathOverride != nil {
 			if tmpl, ok := d.TemplatePathOverride["tag_list.js"]; ok {
 				if len(tmpl.Dist) > 0 {
-					distPath = tmpl.Dist
+					distPath = p.ReplaceDefaultDistPath(tmpl.Dist)
 				}
 			}
 		}
@@ -5,11 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
+	"go/format"
 	"log"
-	"os"
-	"path"
-	"runtime"
 	"strings"
 	"text/template"
 
@@ -46,12 +43,15 @@ var funcMap = template.FuncMap{
 
 func ParseTemplates(p types.ProjectType) map[string]*template.Template {
 
+	// функции плагинов доступны во всех шаблонах
+	pluginsFuncMapProccess(p)
+
 	// парсинг общих шаблонов
 	res := map[string]*template.Template{}
 
 	// функция которая: файлы с шаблонами -> map[string]*template.Template
 	readFiles := func(prefix, delimLeft, delimRight string, path ...string) {
-		tmpls, err := template.New("").Funcs(funcMap).Delims(delimLeft, delimRight).ParseFiles(path...)
+		tmpls, err := utils.ParseTemplateFiles(template.New("").Funcs(funcMap).Delims(delimLeft, delimRight), path...)
 		utils.CheckErr(err, "ParseFiles")
 		for _, t := range tmpls.Templates() {
 			res[prefix+t.Name()] = t
@@ -71,19 +71,21 @@ func ParseTemplates(p types.ProjectType) map[string]*template.Template {
 	}
 	// webClient
 	path = fmt.Sprintf("%s/webClient/quasar_%v/doc/", currentDir, p.GetQuasarVersion())
-	readFiles("webClient_", "[[", "]]", path+"index.vue", path+"item.vue", path+"itemWithTabs.vue", path+"tabInfo.vue")
-	if p.GetQuasarVersion() == 1 {
-		readFiles("webClient_", "[[", "]]", path+"tabTasks.vue")
+	readFiles("webClient_", "[[", "]]", path+"index.vue", path+"item.vue", path+"itemWithTabs.vue")
+	for _, name := range CommonTabTmplNames(p) {
+		readFiles("webClient_", "[[", "]]", path+name)
 	}
 
 	// sql
 	path = currentDir + "/sql/"
 	readFiles("sql_", "{{", "}}", path+"main.toml")
 	path = currentDir + "/sql/function/"
-	readFiles("sql_function_", "{{", "}}", path+"get_by_id.sql", path+"list.sql", path+"update.sql", path+"trigger_before.sql", path+"trigger_after.sql")
+	readFiles("sql_function_", "{{", "}}", path+"get_by_id.sql", path+"list.sql", path+"update.sql", path+"trigger_before.sql", path+"trigger_after.sql", path+"access.sql")
 	readFiles("sql_function_", "[[", "]]", path+"create.sql")
 	// отдельно читаем шаблон action для stateMachine. Там нужно передавать свой map с параметрами
 	res["sql_function_action.sql"] = stateMachineReadTmplAction(funcMap, path+"action.sql")
+	// go пакет repo
+	res["go_repo.go"] = repoReadTmpl(funcMap, currentDir+"/repo/doc.go")
 
 	// парсинг шаблонов для конкретного документа
 	for i, d := range p.Docs {
@@ -100,7 +102,7 @@ func ParseTemplates(p types.ProjectType) map[string]*template.Template {
 			// извлекаем имя файла шаблона, чтобы использовать его в качестве имени шабона. Иначе могут быть ошибки
 			path := strings.Split(dt.Source, "/")
 			fName := path[len(path)-1]
-			t, err := template.New(fName).Funcs(fMap).Delims("[[", "]]").ParseFiles(dt.Source)
+			t, err := utils.ParseTemplateFiles(template.New(fName).Funcs(fMap).Delims("[[", "]]"), dt.Source)
 			utils.CheckErr(err, fmt.Sprintf("ParseTemplates doc: %s tmpl: %s parse template error: %s", d.Name, tName, err))
 			// сохраняем template в поле структуры
 			dt.Tmpl = t
@@ -112,7 +114,7 @@ func ParseTemplates(p types.ProjectType) map[string]*template.Template {
 		}
 
 		if d.IsBaseTemplates.Sql {
-			baseTmplNames = append(baseTmplNames, "sql_main.toml", "sql_function_get_by_id.sql", "sql_function_list.sql", "sql_function_update.sql", "sql_function_trigger_before.sql", "sql_function_trigger_after.sql")
+			baseTmplNames = append(baseTmplNames, "sql_main.toml", "sql_function_get_by_id.sql", "sql_function_list.sql", "sql_function_update.sql", "sql_function_trigger_before.sql", "sql_function_trigger_after.sql", "sql_function_access.sql")
 		}
 		if d.StateMachine != nil {
 			baseTmplNames = append(baseTmplNames, "sql_function_action.sql", "sql_function_create.sql")
@@ -121,8 +123,12 @@ func ParseTemplates(p types.ProjectType) map[string]*template.Template {
 		if d.IsRecursion {
 			docIsRecursionProccess(p, &d)
 		}
-		// если есть интеграции, то создаем дополнительные шаблоны
-		docIsIntegrationProccess(p, &d)
+		// если у документа включена история изменений, то дополнительные шаблоны
+		if d.Sql.IsHistory && d.IsBaseTemplates.Sql {
+			docIsHistoryProccess(p, &d, i)
+		}
+		// шаблоны плагинов (интеграции с Битрикс, 1С и пр)
+		docPluginsProccess(p, &d)
 
 		// в случае если указаны табы, то подбираем соответствующие шаблоны
 		for _, tab := range d.Vue.Tabs {
@@ -135,17 +141,20 @@ func ParseTemplates(p types.ProjectType) map[string]*template.Template {
 			if t1, ok := d.Templates["webClient_"+tab.TmplName]; ok {
 				t = t1.Tmpl
 			}
+			distPath := TabDistPath(d, tab)
+			// и в последнюю очередь - шаблон, переопределенный на уровне проекта (см OverridePathForTemplates)
+			if source, ok := p.OverridePathForTemplates[distPath+"/index.vue"]; ok {
+				_, fName := utils.PathExtractFilename(source)
+				t1, err := utils.ParseTemplateFiles(template.New(fName).Funcs(funcMap).Delims("[[", "]]"), source)
+				utils.CheckErr(err, fmt.Sprintf("ParseTemplates doc: %s tab: %s", d.Name, tab.Title))
+				t = t1
+			}
 			if t == nil {
 				log.Fatalf("ParseTemplates: Template not found for tab %s webClient_%s", d.Name, tab.TmplName)
 			}
 
 			tName := "webClient_tabs_" + tab.Title
-			compPath := d.Name
-			if len(d.Vue.Path) > 0 {
-				compPath = d.Vue.Path // в случае если указан специальный путь к компоненте
-			}
-			distPath := fmt.Sprintf("%s/webClient/src/app/components/%s/tabs/%s", p.DistPath, compPath, tab.Title)
-			d.Templates[tName] = &types.DocTemplate{Tmpl: t, DistPath: distPath, DistFilename: "index.vue"}
+			d.Templates[tName] = &types.DocTemplate{Tmpl: t, DistPath: p.DistPath + distPath, DistFilename: "index.vue"}
 		}
 
 		for _, fld := range d.Flds {
@@ -155,6 +164,13 @@ func ParseTemplates(p types.ProjectType) map[string]*template.Template {
 			fldJsonListProccess(p, &d, &fld)
 		}
 
+		// типизированные функции для вызова sql методов документа из go кода (пакет repo)
+		if d.IsBaseTemplates.Sql {
+			if _, ok := d.Templates["go_repo.go"]; !ok {
+				d.Templates["go_repo.go"] = &types.DocTemplate{Tmpl: res["go_repo.go"], DistPath: p.DistPath + "/repo", DistFilename: strcase.ToLowerCamel(d.Name) + ".go"}
+			}
+		}
+
 		for _, tName := range baseTmplNames {
 			// если шаблона с таким именем нет, то добавляем стандартный
 			if _, ok := d.Templates[tName]; !ok {
@@ -164,6 +180,9 @@ func ParseTemplates(p types.ProjectType) map[string]*template.Template {
 				if tName == "sql_function_trigger_after.sql" && !d.Sql.IsAfterTrigger {
 					continue
 				}
+				if tName == "sql_function_access.sql" && len(d.Sql.AccessRules) == 0 {
+					continue
+				}
 				params := map[string]string{}
 				if len(d.Vue.Path) > 0 {
 					params["doc.Vue.Path"] = d.Vue.Path
@@ -175,7 +194,7 @@ func ParseTemplates(p types.ProjectType) map[string]*template.Template {
 				// возможность переопределить шаблон
 				// если указаны табы, то подменяем шаблон item.vue на itemWithTabs.vue
 				if len(d.Vue.Tabs) > 0 {
-					if strings.HasPrefix(distPath, "../src/webClient/src/app/components") && distFilename == "item.vue" {
+					if strings.HasPrefix(distPath, p.DistPath+"/webClient/src/app/components") && distFilename == "item.vue" {
 						tmpl = res["webClient_itemWithTabs.vue"]
 					}
 				}
@@ -220,30 +239,48 @@ func ParseTemplates(p types.ProjectType) map[string]*template.Template {
 	return res
 }
 
+// общие шаблоны табов документа (см DocVue.Tabs) из webClient/quasar_<версия>/doc
+func CommonTabTmplNames(p types.ProjectType) []string {
+	res := []string{"tabInfo.vue", "tabHistory.vue"}
+	if p.GetQuasarVersion() == 1 {
+		res = append(res, "tabTasks.vue")
+	}
+	return res
+}
+
+// директория компоненты таба относительно DistPath. С "/index.vue" - ключ для OverridePathForTemplates
+func TabDistPath(d types.DocType, tab types.VueTab) string {
+	compPath := d.Name
+	if len(d.Vue.Path) > 0 {
+		compPath = d.Vue.Path // в случае если указан специальный путь к компоненте
+	}
+	return fmt.Sprintf("/webClient/src/app/components/%s/tabs/%s", compPath, tab.Title)
+}
+
 func ExecuteToFile(t *template.Template, d interface{}, path, filename string) error {
 	if t == nil {
 		log.Fatalf("template is nil for path '%s/%s'\n", path, filename)
 	}
-	err := os.MkdirAll(path, os.ModePerm)
-	if err != nil {
-		return err
-	}
 	var tpl bytes.Buffer
-	err = t.Execute(&tpl, d)
+	err := t.Execute(&tpl, d)
 	if err != nil {
 		return err
 	}
-	// для оптимизации записи файлов webClient (чтобы ускорить рестарт quasar), проверяем что файл изменен и только в этом случае его перезаписываем
-	if strings.Contains(path, "webClient") {
-		if existFile, err := ioutil.ReadFile(fmt.Sprintf("%s/%s", path, filename)); err == nil {
-			isEqual := utils.ByteSliceEqual(existFile, []byte(tpl.String()))
-			if isEqual {
-				return nil
-			}
-			//fmt.Printf("file changed: %s/%s not equal\n", path, filename)
+	content := tpl.Bytes()
+	// go файлы форматируются как gofmt. При ошибке файл записывается как есть, чтобы было видно, что сгенерировалось
+	var formatErr error
+	if strings.HasSuffix(filename, ".go") {
+		if src, err := format.Source(content); err == nil {
+			content = src
+		} else {
+			formatErr = fmt.Errorf("format '%s/%s': %v", path, filename, err)
 		}
 	}
-	return ioutil.WriteFile(path+"/"+filename, []byte(tpl.String()), 0644)
+	// неизмененные файлы не перезаписываются (для оптимизации, чтобы ускорить рестарт quasar), см utils.WriteFile
+	if err := utils.WriteFile(path+"/"+filename, content); err != nil {
+		return err
+	}
+	return formatErr
 }
 
 // печать vue темплейтов для
@@ -260,6 +297,22 @@ func PrintVueFldTemplate(fld types.FldType) string {
 	if len(readonly) == 0 {
 		readonly = "false"
 	}
+	vif := fld.Vue.Vif
+	// доступ к полю по ролям (см FldRoles). Роли в обратных кавычках, потому что выражение внутри атрибутов в одинарных и двойных кавычках
+	if roles := fld.Roles.Write; len(roles) > 0 {
+		cond := fmt.Sprintf("!$currentUser.isFldAccess(`%s`)", strings.Join(roles, "`, `"))
+		if readonly != "false" {
+			cond = fmt.Sprintf("(%s) || %s", readonly, cond)
+		}
+		readonly = cond
+	}
+	if roles := fld.ReadRoles(); len(roles) > 0 {
+		cond := fmt.Sprintf("$currentUser.isFldAccess(`%s`)", strings.Join(roles, "`, `"))
+		if len(vif) > 0 {
+			cond = fmt.Sprintf("(%s) && %s", vif, cond)
+		}
+		vif = cond
+	}
 	fldType := fld.Vue.Type
 	if len(fldType) == 0 {
 		fldType = fld.Type
@@ -285,8 +338,8 @@ func PrintVueFldTemplate(fld types.FldType) string {
 	} else {
 		params = params + " class='q-mb-sm' "
 	}
-	if len(fld.Vue.Vif) > 0 {
-		params = params + fmt.Sprintf(" v-if=\"%s\" ", fld.Vue.Vif)
+	if len(vif) > 0 {
+		params = params + fmt.Sprintf(" v-if=\"%s\" ", vif)
 	}
 	// не во всех случаях fld.Doc доступен, но там и label не нужен. Например doc.AddFld(t.GetFldVueCompositionRefList(&doc, t.VueCompRefListWidgetParams{...
 	var labelI18n string
@@ -330,9 +383,9 @@ func PrintVueFldTemplate(fld types.FldType) string {
 			// если есть доп параметры, то вручную дописываем их к json строке
 			if len(fld.Vue.Ext) > 0 {
 				rawJson = strings.TrimSuffix(rawJson, "}")
-				for k, v := range fld.Vue.Ext {
+				for _, k := range utils.SortedKeys(fld.Vue.Ext) {
 					if k != "rawJsonExt" {
-						rawJson = fmt.Sprintf(`%s, %s: "%s"`, rawJson, k, v)
+						rawJson = fmt.Sprintf(`%s, %s: "%s"`, rawJson, k, fld.Vue.Ext[k])
 					}
 				}
 				rawJson = rawJson + "}"
@@ -404,8 +457,8 @@ func PrintVueFldTemplate(fld types.FldType) string {
 		return fmt.Sprintf(`<comp-fld-json-list-%s :label="$t('%s')" :item='item' :fld='item.%s' @update='item.%s = $event' :readonly='%s' %s/>`, name, labelI18n, name, name, readonly, params)
 	case types.FldVueTypeFiles:
 		extStr := ""
-		for k, v := range fld.Vue.Ext {
-			extStr = extStr + fmt.Sprintf(", %s: \"%s\"", k, v)
+		for _, k := range utils.SortedKeys(fld.Vue.Ext) {
+			extStr = extStr + fmt.Sprintf(", %s: \"%s\"", k, fld.Vue.Ext[k])
 		}
 		if fld.Doc == nil {
 			utils.CheckErr(errors.New("in fld pointer to Doc is nil"), "type files print in Vue error")
@@ -413,14 +466,14 @@ func PrintVueFldTemplate(fld types.FldType) string {
 		return fmt.Sprintf("<comp-fld-files v-if=\"this.id != 'new'\" fldName='%s' :label=\"$t('%s')\" :fld='item.%s' :ext = '{tableName: \"%s\", tableId: this.id%s}' @update=\"v=> item.%s = v\" :readonly='%s' %s/>", name, labelI18n, name, fld.Doc.Name, extStr, name, readonly, params)
 	case types.FldVueTypeImg:
 		extStr := ""
-		for k, v := range fld.Vue.Ext {
-			extStr = extStr + fmt.Sprintf(", %s: \"%s\"", k, v)
+		for _, k := range utils.SortedKeys(fld.Vue.Ext) {
+			extStr = extStr + fmt.Sprintf(", %s: \"%s\"", k, fld.Vue.Ext[k])
 		}
 		return fmt.Sprintf("<comp-fld-img v-if=\"this.id != 'new'\" :label=\"$t('%s')\" :fld='item.%s' :ext = '{tableName: \"%s\", tableId: this.id, fldName: \"%s\"%s}' @update=\"v=> item.%s = v\" :readonly='%s' %s/>", labelI18n, name, fld.Doc.Name, name, extStr, name, readonly, params)
 	case types.FldVueTypeImgList:
 		extStr := ""
-		for k, v := range fld.Vue.Ext {
-			extStr = extStr + fmt.Sprintf(", %s: \"%s\"", k, v)
+		for _, k := range utils.SortedKeys(fld.Vue.Ext) {
+			extStr = extStr + fmt.Sprintf(", %s: \"%s\"", k, fld.Vue.Ext[k])
 		}
 		return fmt.Sprintf("<comp-fld-img-list v-if=\"this.id != 'new'\" :label=\"$t('%s')\" :fld='item.%s' :ext = '{tableName: \"%s\", tableId: this.id, fldName: \"%s\"%s}' @update=\"v=> item.%s = v\" :readonly='%s' %s/>", labelI18n, name, fld.Doc.Name, name, extStr, name, readonly, params)
 	default:
@@ -461,10 +514,7 @@ func arrayStringJoin(arr []string) string {
 	return strings.Join(tmpArr, ", ")
 }
 
+// директория с шаблонами фреймворка. Файлы читаются из embed.FS (см utils.FrameworkPathPrefix)
 func getCurrentDir() string {
-	_, filename, _, ok := runtime.Caller(0)
-	if !ok {
-		log.Fatalf("ParseTemplates runtime.Caller: No caller information")
-	}
-	return path.Dir(filename)
+	return utils.FrameworkPath("templates")
 }
@@ -11,12 +11,13 @@ import (
 )
 
 func WriteProjectFiles(p types.ProjectType, tmplMap map[string]*template.Template) {
-	for name, t := range tmplMap {
+	for _, name := range utils.SortedKeys(tmplMap) {
+		t := tmplMap[name]
 		if strings.HasPrefix(name, "project_") {
 			filename := strings.TrimPrefix(name, "project_")
-			path := ".."
+			path := p.Generator.OutputRoot
 			if filename == "config.toml" || filename == "main.go" || filename == "go.mod" || filename == "go.sum" {
-				path = "../src"
+				path = p.DistPath
 			}
 			err := ExecuteToFile(t, p, path, filename)
 			utils.CheckErr(err, fmt.Sprintf("'project' ExecuteToFile '%s'", name))
@@ -24,12 +25,12 @@ func WriteProjectFiles(p types.ProjectType, tmplMap map[string]*template.Templat
 	}
 
 	// генерим шаблоны, которые указаны дополнительно на уровне проекта. Без относительно конкретных документов
-	for _, m := range p.Sql.Methods {
-		for _, v := range m {
+	for _, docName := range utils.SortedKeys(p.Sql.Methods) {
+		for _, v := range p.Sql.Methods[docName] {
 			if len(v.Tmpl.Source) > 0 && len(v.Tmpl.Dist) > 0 {
 				distPath, filename := utils.PathExtractFilename(v.Tmpl.Dist)
-				distPath = "../src" + distPath
-				t, err := template.New(filename).Delims("[[", "]]").ParseFiles(v.Tmpl.Source)
+				distPath = p.DistPath + distPath
+				t, err := utils.ParseTemplateFiles(template.New(filename).Delims("[[", "]]"), v.Tmpl.Source)
 				utils.CheckErr(err, "p.Sql.Methods")
 
 				err = ExecuteToFile(t, p, distPath, filename)
@@ -42,12 +43,9 @@ func WriteProjectFiles(p types.ProjectType, tmplMap map[string]*template.Templat
 	webClient := fmt.Sprintf("/webClient/quasar_%v", p.GetQuasarVersion())
 	ReadTmplAndPrint(p, projectTmplPath+"/types/main.go", "/types", "main.go", nil)
 	ReadTmplAndPrint(p, projectTmplPath+"/types/config.go", "/types", "config.go", nil)
-	ReadTmplAndPrint(p, projectTmplPath+"/webServer/main.go", "/webServer", "main.go", nil)
-	ReadTmplAndPrint(p, projectTmplPath+"/webServer/apiCallPgFunc.go", "/webServer", "apiCallPgFunc.go", nil)
 	ReadTmplAndPrint(p, projectTmplPath+"/webServer/auth/email.go", "/webServer/auth", "email.go", nil)
 	ReadTmplAndPrint(p, projectTmplPath+"/sql/initialData.sql", "/sql/template/function/", "initialData.sql", nil)
 	ReadTmplAndPrint(p, projectTmplPath+"/sql/user_trigger_before.sql", "/sql/template/function/_User/", "user_trigger_before.sql", nil)
-	ReadTmplAndPrint(p, projectTmplPath+"/sql/user_trigger_after.sql", "/sql/template/function/_User/", "user_trigger_after.sql", template.FuncMap{"PrintUserAfterTriggerUpdateLinkedRecords": types.PrintUserAfterTriggerUpdateLinkedRecords})
 	ReadTmplAndPrint(p, projectTmplPath+"/sql/01_User/main.toml", "/sql/model/01_User", "main.toml", nil)
 	ReadTmplAndPrint(p, projectTmplPath+"/sql/01_User/user_list.sql", "/sql/template/function/_User", "user_list.sql", nil)
 	ReadTmplAndPrint(p, projectTmplPath+"/sql/01_User/user_update.sql", "/sql/template/function/_User", "user_update.sql", nil)
@@ -76,14 +74,8 @@ func WriteProjectFiles(p types.ProjectType, tmplMap map[string]*template.Templat
 	ReadTmplAndPrint(p, projectTmplPath+webClient+"/app/components/auth/waitingAuthPage.vue", "/webClient/src/app/components/auth", "waitingAuthPage.vue", nil)
 	ReadTmplAndPrint(p, projectTmplPath+webClient+"/app/components/auth/email/components/compRegisterForm.vue", "/webClient/src/app/components/auth/email/components", "compRegisterForm.vue", nil)
 
-	// заполняем словарь локализаций для всех документов
-	FillDocI18n(p)
-	// печать i18n/index.js
-	PrintI18nJs(p)
-	// создаем папки i18n под каждый указанный язык и в них свой index.js
-	for _, lang := range p.I18n.LangList {
-		PrintDocI18nJs(p, lang)
-	}
+	// файлы, которые собираются по списку документов
+	WriteDocDependentFiles(p)
 
 	if p.Config.Auth.ByPhone {
 		ReadTmplAndPrint(p, projectTmplPath+"/sql/01_User/user_get_by_phone_with_password.sql", "/sql/template/function/_User", "user_get_by_phone_with_password.sql", nil)
@@ -96,13 +88,6 @@ func WriteProjectFiles(p types.ProjectType, tmplMap map[string]*template.Templat
 		ReadTmplAndPrint(p, projectTmplPath+webClient+"/app/components/auth/phone/components/compRegisterForm.vue", "/webClient/src/app/components/auth/phone/components", "compRegisterForm.vue", nil)
 	}
 
-	if p.IsTelegramIntegration() {
-		ReadTmplAndPrint(p, getCurrentDir()+"/integrations/telegram/telegramAuth.go", "/webServer", "telegramAuth.go", nil)
-		ReadTmplAndPrint(p, getCurrentDir()+"/integrations/telegram/user_telegram_auth.sql", "/sql/template/function/_User", "user_telegram_auth.sql", nil)
-		ReadTmplAndPrint(p, getCurrentDir()+"/integrations/telegram/user_get_by_telegram_id.sql", "/sql/template/function/_User", "user_get_by_telegram_id.sql", nil)
-		ReadTmplAndPrint(p, projectTmplPath+"/tgBot/main.go", "/tgBot", "main.go", nil)
-	}
-
 	if p.IsBackupOnYandexDisk() {
 		ReadTmplAndPrint(p, projectTmplPath+"/yandexDiskBackup/main.go", "/yandexDiskBackup", "main.go", nil)
 		ReadTmplAndPrint(p, projectTmplPath+"/yandexDiskBackup/yandexApi.go", "/yandexDiskBackup", "yandexApi.go", nil)
@@ -110,22 +95,26 @@ func WriteProjectFiles(p types.ProjectType, tmplMap map[string]*template.Templat
 		ReadTmplAndPrint(p, projectTmplPath+"/yandexDiskBackup/systemdService.service", "/yandexDiskBackup", p.Config.Postgres.DbName+"_yandexBackup.service", nil)
 		ReadTmplAndPrint(p, projectTmplPath+"/yandexDiskBackup/startYandexBackupService.sh", "/yandexDiskBackup", "startYandexBackupService.sh", nil)
 	}
+}
 
-	// в случае коннекта к Битрикс генерим файлы
-	if p.IsBitrixIntegration() {
-		ReadTmplAndPrint(p, getCurrentDir()+"/integrations/bitrix/bitrixMain.go", "/bitrix", "main.go", nil)
-		//sourcePath := "../../../NL-A/nla_framework/templates/integrations/bitrix/bitrixMain.go"
-		//t, err := template.New("bitrixMain.go").Funcs(funcMap).Delims("[[", "]]").ParseFiles(sourcePath)
-		//utils.CheckErr(err, "bitrixMain.go")
-		//distPath := fmt.Sprintf("%s/bitrix", p.DistPath)
-		//err = ExecuteToFile(t, p, distPath, "main.go")
-		//utils.CheckErr(err, fmt.Sprintf("'project' ExecuteToFile '%s'", "bitrix/main.go"))
-	}
+// WriteDocDependentFiles файлы проекта, содержимое которых зависит от документов: обработчики методов, триггер пользователя, файлы плагинов и локализация.
+// При инкрементальной генерации (см nla_framework.Watch) перегенерируются вместе с измененными документами
+func WriteDocDependentFiles(p types.ProjectType) {
+	projectTmplPath := getCurrentDir() + "/project"
+	ReadTmplAndPrint(p, projectTmplPath+"/webServer/main.go", "/webServer", "main.go", nil)
+	ReadTmplAndPrint(p, projectTmplPath+"/webServer/apiCallPgFunc.go", "/webServer", "apiCallPgFunc.go", nil)
+	ReadTmplAndPrint(p, projectTmplPath+"/sql/user_trigger_after.sql", "/sql/template/function/_User/", "user_trigger_after.sql", template.FuncMap{"PrintUserAfterTriggerUpdateLinkedRecords": types.PrintUserAfterTriggerUpdateLinkedRecords})
+
+	// файлы плагинов генератора (интеграции с Битрикс, 1С, Telegram и пр). Могут зависеть от списка документов
+	writePluginsProjectFiles(p)
 
-	// в случае коннекта к 1 Odata генерим файлы
-	if p.IsOdataIntegration() {
-		ReadTmplAndPrint(p, getCurrentDir()+"/integrations/odata/main.go", "/odata", "main.go", nil)
-		ReadTmplAndPrint(p, getCurrentDir()+"/integrations/odata/odataQueryType.go", "/odata", "odataQueryType.go", nil)
+	// заполняем словарь локализаций для всех документов
+	FillDocI18n(p)
+	// печать i18n/index.js
+	PrintI18nJs(p)
+	// создаем папки i18n под каждый указанный язык и в них свой index.js
+	for _, lang := range p.I18n.LangList {
+		PrintDocI18nJs(p, lang)
 	}
 }
 
@@ -138,6 +127,15 @@ func OtherTemplatesGenerate(p types.ProjectType) {
 	tmplGenerateStep2.PluginUtilsJs(p)
 	//
 	tmplGenerateStep2.BootI18nJs(p)
+	// ER-диаграммы по сгенерированным sql/model и диаграммы машин состояний
+	tmplGenerateStep2.ErDiagram(p)
+	tmplGenerateStep2.SmDiagram(p)
+	// миграции схемы базы по изменениям sql/model
+	tmplGenerateStep2.Migrations(p)
+	// описание api в формате OpenAPI (docs/openapi.json)
+	tmplGenerateStep2.OpenApi(p)
+	// типы документов и типизированный клиент api для webClient
+	tmplGenerateStep2.TypeScriptApi(p)
 }
 
 func ReadTmplAndPrint(p types.ProjectType, sourcePath, distPath, filename string, addFuncMap template.FuncMap) {
@@ -153,7 +151,7 @@ func ReadTmplAndPrint(p types.ProjectType, sourcePath, distPath, filename string
 		sourcePath = newSourcePath
 	}
 	_, sourceFilename := utils.PathExtractFilename(sourcePath)
-	t, err := template.New(sourceFilename).Funcs(fMap).Delims("[[", "]]").ParseFiles(sourcePath)
+	t, err := utils.ParseTemplateFiles(template.New(sourceFilename).Funcs(fMap).Delims("[[", "]]"), sourcePath)
 	utils.CheckErr(err, "readFileWithDist")
 	err = ExecuteToFile(t, p, p.DistPath+distPath, filename)
 	utils.CheckErr(err, fmt.Sprintf("ReadTmplAndPrint ExecuteToFile '%s/%s'", distPath, filename))
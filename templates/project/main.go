@@ -12,15 +12,6 @@ import (
 	"{{.Config.LocalProjectPath}}/utils"
 	"{{.Config.LocalProjectPath}}/webServer"
 	"{{.Config.LocalProjectPath}}/sse"
-{{if .IsBitrixIntegration -}}
-	"{{.Config.LocalProjectPath}}/bitrix"
-{{- end}}
-{{if .IsTelegramIntegration -}}
-	"{{.Config.LocalProjectPath}}/tgBot"
-{{- end}}
-{{if .IsOdataIntegration -}}
-	"{{.Config.LocalProjectPath}}/odata"
-{{- end}}
 	"math/rand"
 	"os"
 	"time"
@@ -47,10 +38,8 @@ func main() {
 	pgPort := flag.String("pg_port", "", "an string")
 	pgPassword := flag.String("pg_pass", "", "an string")
 	dbName := flag.String("dbname", "", "an string")
-{{if .IsTelegramIntegration -}}
-	tgBotName := flag.String("telegram_bot_name", "", "an string")
-	tgBotToken:= flag.String("telegram_bot_token", "", "an string")
-{{- end}}
+	// версии миграций с потерей данных, которые разрешено применить. Например, -approve_migrations 0003,0004
+	approveMigrations := flag.String("approve_migrations", "", "an string")
 {{- range.Go.Flags}}
 	{{.Desc}}
 {{- end}}
@@ -68,19 +57,13 @@ func main() {
 		if len(*dbName) > 0 {
 			_ = os.Setenv("PG_DBNAME", *dbName)
 		}
-{{if .IsTelegramIntegration -}}
-		if len(*tgBotName) > 0 {
-			_ = os.Setenv("TELEGRAM_BOT_NAME", *tgBotName)
-		} else {
-			utils.Panic("Write 'telegram_bot_name' and 'telegram_bot_token' in go parameters for developmeent mode")
-		}
-		if len(*tgBotToken) > 0 {
-			_ = os.Setenv("TELEGRAM_BOT_TOKEN", *tgBotToken)
-		}
-{{- end}}
 		_ = os.Setenv("IS_DEVELOPMENT", "true")
 	}
 
+	if len(*approveMigrations) > 0 {
+		_ = os.Setenv("APPROVE_MIGRATIONS", *approveMigrations)
+	}
+
 {{range.Go.Flags -}}
 	{{.ProcessBlock}}
 {{- end}}
@@ -116,17 +99,8 @@ func main() {
 	// передаем часть конфига в utils
 	utils.SetWebServerConfig(config.WebServer)
 	utils.SetEmailConfig(config.Email)
-{{if .IsBitrixIntegration -}}
-	bitrix.SetBitrixConfig(config.Bitrix)
-{{- end}}
-{{if .IsOdataIntegration -}}
-	odata.SetOdataConfig(config.Odata)
-{{- end}}
 
 	//go pg.GenerateFakeUsers(100)
-{{if .IsTelegramIntegration -}}
-	go tgBot.Start(*config)
-{{- end}}
 
 	// инициализируем брокера для обработки подключений по SSE
 	sse.Init()
@@ -28,27 +28,11 @@ type EmailConfig struct {
 	SenderLogo            string
 	IsSendWithEmptySender bool // признак что не прописывать отправителя
 }
-[[if.IsTelegramIntegration -]]
-type TelegramConfig struct {
-	BotName string
-	Token   string
-}
-[[- end]]
+[[- range .PluginConfigSections]]
 
-[[if.IsBitrixIntegration -]]
-type BitrixConfig struct {
-	ApiUrl       string
-	UserId       string
-	WebhookToken string
-}
+type [[.GoType]] struct {
+[[- range .Flds]]
+	[[.GoName]] string
 [[- end]]
-
-[[if.IsOdataIntegration -]]
-type OdataConfig struct {
-	Url              string
-	Login            string
-	Password         string
-	ExchangePlanName string
-	ExchangePlanGuid string
 }
 [[- end]]
@@ -7,13 +7,8 @@ import (
 	"[[.Config.LocalProjectPath]]/webServer/auth"
 	"github.com/gin-gonic/gin"
 
-[[if .IsBitrixIntegration -]]
-	"[[.Config.LocalProjectPath]]/bitrix"
-[[- end]]
-[[if .IsOdataIntegration -]]
-	"[[.Config.LocalProjectPath]]/odata"
-[[- end]]
 	"net/http"
+	"os"
 [[- range .Go.Routes.Imports]]
 	[[if StringContainsQuote . ]][[.]][[ else ]]"[[.]]"[[end]]
 [[- end]]
@@ -70,55 +65,21 @@ func StartWebServer(config types.Config) {
 		// загрузка фото
 		apiRoute.POST("/upload_image", uploadImage)
 		apiRoute.POST("/upload_profile_image", uploadProfileImage)
-[[if .IsTelegramIntegration -]]
-		apiRoute.POST("/telegram_auth", telegramAuth(config.Telegram))
-[[- end]]
-
-[[if .IsBitrixIntegration -]]
-		// импорт данных из Битрикс
-		btxRoute := apiRoute.Group("/bitrix")
-[[- range .Docs -]]
-[[- if .IsBitrixIntegration]]
-		btxRoute.POST("/import_[[.Name]]", bitrix.Get[[ToCamel .Name]]History)
-[[- end ]]
-[[- end ]]
-[[- end ]]
-
-[[if .IsOdataIntegration -]]
-		// импорт данных из 1С Odata
-		odataRoute := apiRoute.Group("/odata")
-[[- range .Docs -]]
-[[- if .IsOdataIntegration]]
-		odataRoute.POST("/import_[[.Name]]", odata.Start[[ToCamel .Name]]Sync)
-[[- end ]]
-[[- end ]]
-[[- end ]]
 [[- range .Go.Routes.Api]]
 		[[.]]
 [[- end]]
 	}
 
+	// в режиме разработки - описание api (docs/openapi.json) и Swagger UI для него
+	if os.Getenv("IS_DEVELOPMENT") == "true" {
+		r.StaticFile("/swagger/openapi.json", "./docs/openapi.json")
+		r.GET("/swagger", swaggerUi)
+	}
+
 [[- range .Go.Routes.NotAuth]]
 	[[.]]
 [[- end]]
 
-[[if .IsBitrixIntegration -]]
-	// отладочные методы для импорта данных из Битрикс
-[[- range .Docs -]]
-[[- if .IsBitrixIntegrationDebugMode]]
-	r.GET("/bitrix/import_[[.Name]]", bitrix.Get[[ToCamel .Name]]HistoryDebug)
-[[- end ]]
-[[- end ]]
-[[- end ]]
-
-[[if .IsOdataIntegration -]]
-	// отладочные методы для импорта данных из Битрикс
-[[- range .Docs -]]
-[[- if .IsOdataIntegrationDebugMode]]
-	r.GET("/odata/import_[[.Name]]",  odata.Sync[[ToCamel .Name]]With1CDebug)
-[[- end ]]
-[[- end ]]
-[[- end ]]
 
 	// на ненайденный url отправляем статический файл для запуска vuejs приложения
 	r.NoRoute(func(c *gin.Context) {
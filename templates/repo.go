@@ -0,0 +1,65 @@
+package templates
+
+import (
+	"text/template"
+
+	"github.com/NL-A/nla_framework/types"
+	"github.com/NL-A/nla_framework/utils"
+)
+
+// шаблон файла пакета repo для документа: структура записи и функции для вызова sql методов
+func repoReadTmpl(funcMap template.FuncMap, path string) *template.Template {
+	fMap := template.FuncMap{
+		// поля, которые хранятся в таблице
+		"IsRepoFld": func(fld types.FldType) bool {
+			return len(fld.Name) > 0 && fld.Type != types.FldTypeVueComposition
+		},
+		// фильтры метода list - те же поля, что в where_str_build (см PrintSqlFuncListWhereCond)
+		"IsRepoListFilter": func(fld types.FldType) bool {
+			return fld.Name != "title" && (len(fld.Sql.Ref) > 0 || fld.Sql.IsSearch)
+		},
+		// для полей-ссылок list и get_by_id возвращают название записи (см FldType.RefTitleName)
+		"RefTitleFlds": func(d types.DocType) []string {
+			res := []string{}
+			for _, fld := range d.Flds {
+				if len(fld.Sql.Ref) > 0 {
+					res = append(res, fld.RefTitleName())
+				}
+			}
+			return res
+		},
+		"IsDocHasFld": func(d types.DocType, name string) bool {
+			for _, fld := range d.Flds {
+				if fld.Name == name {
+					return true
+				}
+			}
+			return false
+		},
+		// поля, которые заполняются при переходах машины состояний
+		"ActionFlds": func(d types.DocType) []types.FldType {
+			res := []types.FldType{}
+			names := []string{}
+			for _, st := range d.StateMachine.States {
+				for _, actn := range st.Actions {
+					for _, fld := range actn.UpdateFlds {
+						if fld.Name != "state" && !utils.CheckContainsSliceStr(fld.Name, names...) {
+							names = append(names, fld.Name)
+							res = append(res, fld)
+						}
+					}
+				}
+			}
+			return res
+		},
+	}
+	for k, v := range funcMap {
+		fMap[k] = v
+	}
+	tmpls, err := utils.ParseTemplateFiles(template.New("").Funcs(fMap).Delims("[[", "]]"), path)
+	utils.CheckErr(err, "repoReadTmpl")
+	for _, tmpl := range tmpls.Templates() {
+		return tmpl
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package repo
+
+import (
+	"context"
+)
+
+type (
+	// [[.NameRu]]
+	[[$.NameCamelCase]] struct {
+		Id int `json:"id"`
+	[[- range .Flds]]
+	[[- if IsRepoFld .]]
+		[[ToCamel .Name]] [[.GoType]] `json:"[[.Name]],omitempty"` // [[.NameRu]]
+	[[- end]]
+	[[- end]]
+	[[- range RefTitleFlds .]]
+		[[ToCamel .]] string `json:"[[.]],omitempty"`
+	[[- end]]
+	[[- if not (IsDocHasFld . "options")]]
+		Options map[string]interface{} `json:"options,omitempty"`
+	[[- end]]
+		CreatedAt string `json:"created_at,omitempty"`
+		UpdatedAt string `json:"updated_at,omitempty"`
+		Deleted   bool   `json:"deleted,omitempty"`
+	}
+
+	// [[$.NameCamelCase]]ListParams параметры [[$.NameCamelCase]]List. Фильтры - поля-ссылки и поля с признаком IsSearch
+	[[$.NameCamelCase]]ListParams struct {
+		ListParams
+	[[- range .Flds]]
+	[[- if IsRepoListFilter .]]
+		[[ToCamel .Name]] [[.GoType]] `json:"[[.Name]],omitempty"`
+	[[- end]]
+	[[- end]]
+	}
+[[- if .IsStateMachine]]
+
+	// [[$.NameCamelCase]]ActionParams параметры перехода машины состояний. Поля заполняются при смене состояния
+	[[$.NameCamelCase]]ActionParams struct {
+		Id         int    `json:"id"`
+		ActionName string `json:"action_name"`
+	[[- range ActionFlds .]]
+		[[ToCamel .Name]] [[.GoType]] `json:"[[.Name]],omitempty"`
+	[[- end]]
+	}
+[[- end]]
+)
+[[- if .IsStateMachine]]
+
+// переходы машины состояний [[.NameRu]]
+const (
+[[- range .StateMachine.States]]
+[[- $st := .]]
+[[- range .Actions]]
+	[[$.NameCamelCase]]Action[[ToCamel $st.Title]]To[[ToCamel .To]] = "[[$st.Title]]_to_[[.To]]"
+[[- end]]
+[[- end]]
+)
+[[- end]]
+
+// [[$.NameCamelCase]]GetById запись по id
+func [[$.NameCamelCase]]GetById(ctx context.Context, id int) (*[[$.NameCamelCase]], error) {
+	res := &[[$.NameCamelCase]]{}
+	if err := call(ctx, "[[.PgName]]_get_by_id", map[string]interface{}{"id": id}, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// [[$.NameCamelCase]]List список записей
+func [[$.NameCamelCase]]List(ctx context.Context, params [[$.NameCamelCase]]ListParams) ([][[$.NameCamelCase]], error) {
+	res := [][[$.NameCamelCase]]{}
+	if err := call(ctx, "[[.PgName]]_list", params, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// [[$.NameCamelCase]]Update создание (Id = 0) или изменение записи.
+// Поля с нулевыми значениями не передаются. Чтобы записать пустое значение, используйте [[$.NameCamelCase]]UpdateFlds
+func [[$.NameCamelCase]]Update(ctx context.Context, item [[$.NameCamelCase]]) (*[[$.NameCamelCase]], error) {
+	if item.Id == 0 {
+		item.Id = -1
+	}
+	res := &[[$.NameCamelCase]]{}
+	if err := call(ctx, "[[.PgName]]_update", item, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// [[$.NameCamelCase]]UpdateFlds изменение отдельных полей записи. Например, [[$.NameCamelCase]]UpdateFlds(ctx, id, map[string]interface{}{"deleted": false})
+func [[$.NameCamelCase]]UpdateFlds(ctx context.Context, id int, flds map[string]interface{}) (*[[$.NameCamelCase]], error) {
+	params := map[string]interface{}{"id": id}
+	for k, v := range flds {
+		params[k] = v
+	}
+	res := &[[$.NameCamelCase]]{}
+	if err := call(ctx, "[[.PgName]]_update", params, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+[[- if .IsStateMachine]]
+
+// [[$.NameCamelCase]]Action переход в другое состояние. action - константа [[$.NameCamelCase]]Action...
+func [[$.NameCamelCase]]Action(ctx context.Context, id int, action string) (*[[$.NameCamelCase]], error) {
+	return [[$.NameCamelCase]]ActionWithParams(ctx, [[$.NameCamelCase]]ActionParams{Id: id, ActionName: action})
+}
+
+// [[$.NameCamelCase]]ActionWithParams переход в другое состояние с заполнением полей
+func [[$.NameCamelCase]]ActionWithParams(ctx context.Context, params [[$.NameCamelCase]]ActionParams) (*[[$.NameCamelCase]], error) {
+	res := &[[$.NameCamelCase]]{}
+	if err := call(ctx, "[[.PgName]]_action", params, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+[[- end]]
@@ -0,0 +1,20 @@
+-- доступ пользователя к записи {{.NameRu}} по ролям (DocSql.AccessRules)
+-- вызывается из list, get_by_id, update, action и списков тэгов. Вызовы без пользователя (user_id пустой или -1) не ограничиваются
+-- параметры:
+-- userId   type: int - id пользователя
+-- doc      type: {{.PgName}} - запись
+
+DROP FUNCTION IF EXISTS {{.PgName}}_access(userId INT, doc {{.PgName}});
+CREATE OR REPLACE FUNCTION {{.PgName}}_access(userId INT, doc {{.PgName}})
+    RETURNS BOOL
+    LANGUAGE sql
+    STABLE
+AS
+$function$
+
+    SELECT userId IS NULL OR userId = -1 OR exists(SELECT 1 FROM "user" u WHERE u.id = userId AND (
+        'admin' = ANY (u.role)
+{{.PrintSqlFuncAccessCond}}
+    ));
+
+$function$;
@@ -23,6 +23,16 @@ DECLARE
     m           VARCHAR[];
     [[tmplSqlActionPrintRefUpdateVarDeclare .]]
 BEGIN
+[[- if .Sql.IsHistory]]
+
+    -- пользователь, от имени которого идет изменение. Его записывает в [[.PgName]]_history триггер doc_history
+    PERFORM set_config('app.user_id', coalesce(params ->> 'user_id', ''), true);
+[[- end]]
+[[- if .IsFldRoles "write"]]
+
+    -- поля, которые пользователю нельзя изменять по ролям (FldType.Roles), не обновляются
+    params = params - user_denied_flds((params ->> 'user_id')::int, [[.PrintSqlFldRolesJson "write"]]);
+[[- end]]
 
     -- проверка наличия id
     checkMsg = check_required_params(params, ARRAY ['id', 'action_name', 'user_id']);
@@ -36,6 +46,10 @@ BEGIN
     THEN
         RETURN json_build_object('ok', FALSE, 'message', 'wrong id');
     END IF;
+[[- if .Sql.AccessRules]]
+
+    [[.PrintSqlFuncAccessCheck "r.id" "wrong id"]]
+[[- end]]
     -- создаем json объект из записи, чтобы можно было обращаться к значениям колонок через название переменных
     rJson = row_to_json(r)::jsonb;
 
@@ -13,6 +13,16 @@ DECLARE
     checkMsg    TEXT;
  [[.Sql.Hooks.Print "update" "declareVars"]]
 BEGIN
+[[- if .Sql.IsHistory]]
+
+    -- пользователь, от имени которого идет изменение. Его записывает в [[.PgName]]_history триггер doc_history
+    PERFORM set_config('app.user_id', coalesce(params ->> 'user_id', ''), true);
+[[- end]]
+[[- if .IsFldRoles "write"]]
+
+    -- поля, которые пользователю нельзя изменять по ролям (FldType.Roles), не обновляются
+    params = params - user_denied_flds((params ->> 'user_id')::int, [[.PrintSqlFldRolesJson "write"]]);
+[[- end]]
 
     [[.PrintSqlFuncUpdateCheckParams]]
 
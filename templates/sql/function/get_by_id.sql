@@ -23,6 +23,10 @@ BEGIN
     THEN
         RETURN checkMsg;
     END IF;
+{{- if .Sql.AccessRules}}
+
+    {{.PrintSqlFuncAccessCheck "(params ->> 'id')::int" "not found"}}
+{{- end}}
 
     {{.PrintSqlFuncGetById}}
 
@@ -31,6 +35,11 @@ BEGIN
     THEN
         RETURN json_build_object('ok', FALSE, 'message', 'not found');
     END IF;
+{{- if .IsFldRoles "read"}}
+
+    -- поля, скрытые от пользователя по ролям (FldType.Roles)
+    result = jsonb_hide_flds(result, user_denied_flds((params ->> 'user_id')::int, {{.PrintSqlFldRolesJson "read"}}), {{.PrintSqlFldHiddenKeysJson}});
+{{- end}}
 
     RETURN json_build_object('ok', TRUE, 'result', result);
 
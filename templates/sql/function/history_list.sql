@@ -0,0 +1,53 @@
+-- история изменений {{.NameRu}} (см DocSql.IsHistory)
+-- параметры:
+-- id              type: int - id записи {{.PgName}}
+-- page            type: int - номер страницы. Дефолт: 1
+-- per_page        type: int - количество записей на странице. Дефолт: 100
+
+DROP FUNCTION IF EXISTS {{.PgName}}_history_list(params JSONB);
+CREATE OR REPLACE FUNCTION {{.PgName}}_history_list(params JSONB)
+    RETURNS JSON
+    LANGUAGE plpgsql
+AS
+$function$
+
+DECLARE
+    result   JSON;
+    checkMsg TEXT;
+    perPage  INT;
+BEGIN
+
+    checkMsg = check_required_params_with_func_name('{{.PgName}}_history_list', params, ARRAY ['id', 'user_id']);
+    IF checkMsg IS NOT NULL
+    THEN
+        RETURN checkMsg;
+    END IF;
+{{- if .Sql.AccessRules}}
+
+    {{.PrintSqlFuncAccessCheck "(params ->> 'id')::int" "not found"}}
+{{- end}}
+
+    perPage = coalesce((params ->> 'per_page')::int, 100);
+
+    SELECT array_to_json(array_agg(t)) INTO result FROM (
+{{- if .IsFldRoles "read"}}
+        -- поля, скрытые от пользователя по ролям (FldType.Roles), не показываются и в истории
+        SELECT h.id, h.operation, jsonb_hide_flds(h.old_values, d.flds, {{.PrintSqlFldHiddenKeysJson}}) AS old_values,
+               jsonb_hide_flds(h.new_values, d.flds, {{.PrintSqlFldHiddenKeysJson}}) AS new_values, h.created_at, h.user_id, u.fullname AS user_fullname
+        FROM {{.PgName}}_history h
+        LEFT JOIN "user" u ON u.id = h.user_id,
+        user_denied_flds((params ->> 'user_id')::int, {{.PrintSqlFldRolesJson "read"}}) d(flds)
+{{- else}}
+        SELECT h.id, h.operation, h.old_values, h.new_values, h.created_at, h.user_id, u.fullname AS user_fullname
+        FROM {{.PgName}}_history h
+        LEFT JOIN "user" u ON u.id = h.user_id
+{{- end}}
+        WHERE h.doc_id = (params ->> 'id')::int
+        ORDER BY h.id DESC
+        LIMIT perPage OFFSET (coalesce((params ->> 'page')::int, 1) - 1) * perPage
+    ) t;
+
+    RETURN json_build_object('ok', TRUE, 'result', coalesce(result, '[]'));
+
+END
+$function$;
@@ -5,6 +5,11 @@
 -- page            type: int - номер страницы. Дефолт: 1
 -- per_page        type: int - количество записей на странице. Дефолт: 1000
 -- search_text     type: string - текстовый поиск
+-- with_total      type: bool - вернуть в meta_info.total количество записей по фильтру
+-- cursor          type: json - курсорная пагинация вместо page: meta_info.next_cursor из предыдущего ответа. Для первой страницы - null
+{{- if .Sql.IsFullTextSearch}}
+-- is_default_order type: bool - order_by - сортировка по умолчанию, не выбранная пользователем: при search_text сортировка по релевантности
+{{- end}}
 
 DROP FUNCTION IF EXISTS {{.PgName}}_list(params JSONB);
 CREATE OR REPLACE FUNCTION {{.PgName}}_list(params JSONB)
@@ -18,6 +23,11 @@ DECLARE
     condQueryStr TEXT;
     whereStr     TEXT;
     checkMsg     TEXT;
+    total        INT;
+    metaInfo     JSONB := '{}';
+{{- if .Sql.IsFullTextSearch}}
+    searchQuery  tsquery;
+{{- end}}
     {{.Sql.Hooks.Print "list" "declareVars"}}
 BEGIN
 
@@ -26,6 +36,15 @@ BEGIN
     THEN
         RETURN checkMsg;
     END IF;
+{{- if .IsFldRoles "read"}}
+
+    -- фильтры, сортировка и поиск по полям, скрытым от пользователя по ролям (FldType.Roles), запрещены
+    checkMsg = check_denied_params(params, user_denied_flds((params ->> 'user_id')::int, {{.PrintSqlFldRolesJson "read"}}), {{.PrintSqlFldDeniedParamsJson}});
+    IF checkMsg IS NOT NULL
+    THEN
+        RETURN checkMsg;
+    END IF;
+{{- end}}
 
     {{.Sql.Hooks.Print "list" "listBeforeBuildWhere"}}
 
@@ -34,14 +53,75 @@ BEGIN
         {{.PrintSqlFuncListWhereCond}}
     ]);
 
+{{- if .Sql.AccessRules}}
+
+    {{.PrintSqlFuncListAccessCond}}
+{{- end}}
+
+{{- if .Sql.IsFullTextSearch}}
+
+    -- полнотекстовый поиск: если сортировка не указана или клиент передал сортировку по умолчанию (is_default_order),
+    -- то сортируем по релевантности. Курсор по релевантности не строится, поэтому дальше постранично
+    searchQuery = search_tsquery('{{.Sql.GetFullTextSearchConfig}}', params ->> 'search_text');
+    IF searchQuery IS NOT NULL AND (params ->> 'order_by' IS NULL OR coalesce((params ->> 'is_default_order')::bool, FALSE))
+    THEN
+        params = (params - 'cursor') || jsonb_build_object('order_by', format('ts_rank(doc.search_vector, %L::tsquery) DESC, doc.id DESC', searchQuery));
+    END IF;
+{{- end}}
+
     {{.Sql.Hooks.Print "list" "listAfterBuildWhere"}}
 
+    -- количество записей по фильтру, без учета пагинации
+    IF (params ->> 'with_total')::bool
+    THEN
+        EXECUTE concat('SELECT count(*) FROM {{.PgName}} AS doc ', whereStr) INTO total;
+        metaInfo = metaInfo || jsonb_build_object('total', total);
+    END IF;
+
+    -- курсорная пагинация только при сортировке по одной колонке (см list_keyset_sort). Иначе - постранично:
+    -- без next_cursor в meta_info клиент переходит на page
+    IF params ? 'cursor' AND list_keyset_sort(params) IS NULL
+    THEN
+        params = params - 'cursor';
+    END IF;
+
+    -- курсорная пагинация (keyset): записи после курсора (list_keyset_where_str - функция из папки base)
+    IF params ? 'cursor'
+    THEN
+        whereStr = whereStr || list_keyset_where_str(params, 'doc');
+    END IF;
+
     -- финальная сборка строки с условиями выборки (build_query_part_for_list - функция из папки base)
     condQueryStr = '' || whereStr || build_query_part_for_list(params);
 
     {{.PrintSqlFuncList}}
 
-    RETURN json_build_object('ok', TRUE, 'result', coalesce(result, '[]'));
+    IF params ? 'cursor'
+    THEN
+        metaInfo = metaInfo || jsonb_build_object('next_cursor', list_next_cursor(params, result));
+    END IF;
+{{- if .Sql.IsFullTextSearch}}
+
+    -- релевантность и найденные фрагменты текста в meta_info каждой записи
+    IF searchQuery IS NOT NULL
+    THEN
+        SELECT json_agg(e.value || jsonb_build_object('meta_info', jsonb_build_object(
+                   'rank', ts_rank(doc.search_vector, searchQuery),
+                   'snippet', ts_headline('{{.Sql.GetFullTextSearchConfig}}', concat_ws(' ', {{.PrintSqlFuncSearchHeadlineText}}), searchQuery))) ORDER BY e.ordinality)
+        INTO result
+        FROM jsonb_array_elements(result::jsonb) WITH ORDINALITY e
+                 JOIN {{.PgName}} doc ON doc.id = (e.value ->> 'id')::int;
+    END IF;
+{{- end}}
+{{- if .IsFldRoles "read"}}
+
+    -- поля, скрытые от пользователя по ролям (FldType.Roles)
+    SELECT json_agg(jsonb_hide_flds(e.value, h.flds, {{.PrintSqlFldHiddenKeysJson}}) ORDER BY e.ordinality) INTO result
+    FROM jsonb_array_elements(result::jsonb) WITH ORDINALITY e,
+         user_denied_flds((params ->> 'user_id')::int, {{.PrintSqlFldRolesJson "read"}}) h(flds);
+{{- end}}
+
+    RETURN json_build_object('ok', TRUE, 'result', coalesce(result, '[]'), 'meta_info', metaInfo);
 
 END
 $function$;
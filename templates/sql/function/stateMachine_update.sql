@@ -20,6 +20,16 @@ DECLARE
     arrFlds     VARCHAR[] := '{{options, options, jsonb}}'::VARCHAR[];
     m           VARCHAR[];
 BEGIN
+[[- if .Sql.IsHistory]]
+
+    -- пользователь, от имени которого идет изменение. Его записывает в [[.PgName]]_history триггер doc_history
+    PERFORM set_config('app.user_id', coalesce(params ->> 'user_id', ''), true);
+[[- end]]
+[[- if .IsFldRoles "write"]]
+
+    -- поля, которые пользователю нельзя изменять по ролям (FldType.Roles), не обновляются
+    params = params - user_denied_flds((params ->> 'user_id')::int, [[.PrintSqlFldRolesJson "write"]]);
+[[- end]]
 
     -- проверка наличия id
     checkMsg = check_required_params(params, ARRAY ['id', 'user_id']);
@@ -33,6 +43,10 @@ BEGIN
     THEN
         RETURN json_build_object('ok', FALSE, 'message', 'wrong id');
     END IF;
+[[- if .Sql.AccessRules]]
+
+    [[.PrintSqlFuncAccessCheck "r.id" "wrong id"]]
+[[- end]]
 
     case r.state
 [[tmplSqlUpdatePrintCaseBlock .]]
@@ -54,10 +68,18 @@ BEGIN
 
     EXECUTE (concat('UPDATE [[.Name]] SET ', '' || update_str_from_json(params, arrFlds), ' WHERE id=', params ->> 'id', ' RETURNING *;'))
         INTO rNew;
+[[- if .Sql.AccessRules]]
+
+    [[.PrintSqlFuncAccessCheckRow "rNew"]]
+[[- end]]
 
     [[.Sql.Hooks.Print "update" "afterInsertUpdate"]]
 
     RETURN [[.Name]]_get_by_id(params);
+[[- if .Sql.AccessRules]]
+
+[[.PrintSqlFuncAccessDeniedHandler]]
+[[- end]]
 
 END
 
@@ -10,9 +10,17 @@ AS $function$
 DECLARE
   result JSON;
 BEGIN
+{{- with .PrintSqlFldRolesJsonByName "read" GetFld}}
+
+  -- тэги поля, скрытого от пользователя по ролям (FldType.Roles), не возвращаются
+  IF cardinality(user_denied_flds((params ->> 'user_id')::int, {{.}})) > 0
+  THEN
+    RETURN json_build_object('ok', FALSE, 'message', 'field is not available: {{GetFld}}');
+  END IF;
+{{- end}}
 
   EXECUTE (
-    'SELECT array_to_json(array_agg(t.unnest)) FROM (select DISTINCT unnest({{GetFld}}) from {{.PgName}}) AS t')
+    'SELECT array_to_json(array_agg(t.unnest)) FROM (select DISTINCT unnest({{GetFld}}) from {{.PgName}}{{if .Sql.AccessRules}} AS doc WHERE {{.PgName}}_access(' || quote_nullable(params ->> 'user_id') || '::int, doc){{end}}) AS t')
   INTO result;
 
   RETURN json_build_object('ok', TRUE, 'result', coalesce(result, '[]'));
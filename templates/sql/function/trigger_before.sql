@@ -9,7 +9,7 @@ DECLARE
        searchTxtVar TEXT := '';
 BEGIN
 
-        {{if .Sql.IsSearchText}}
+        {{if or .Sql.IsSearchText .Sql.IsFullTextSearch}}
         {{- /* заполнение ref полей */ -}}
         {{.GetBeforeTriggerFillRefVars}}
         {{- end}}
@@ -37,6 +37,11 @@ BEGIN
         NEW.search_text = '';
         {{- end}}
         {{- end }}
+        {{- if .Sql.IsFullTextSearch}}
+
+        -- заполняем search_vector для полнотекстового поиска
+        NEW.search_vector = {{.GetSearchVectorString}};
+        {{- end}}
 
         {{.Sql.Hooks.Print "triggerBefore" "AfterTriggerBefore"}}
 
@@ -16,6 +16,16 @@ DECLARE
     queryStr    TEXT;
     {{.Sql.Hooks.Print "update" "declareVars"}}
 BEGIN
+{{- if .Sql.IsHistory}}
+
+    -- пользователь, от имени которого идет изменение. Его записывает в {{.PgName}}_history триггер doc_history
+    PERFORM set_config('app.user_id', coalesce(params ->> 'user_id', ''), true);
+{{- end}}
+{{- if .IsFldRoles "write"}}
+
+    -- поля, которые пользователю нельзя изменять по ролям (FldType.Roles), не обновляются
+    params = params - user_denied_flds((params ->> 'user_id')::int, {{.PrintSqlFldRolesJson "write"}});
+{{- end}}
 
     {{.PrintSqlFuncUpdateCheckParams}}
 
@@ -46,6 +56,9 @@ BEGIN
         {{.Sql.Hooks.Print "update" "afterInsert"}}
 
     else
+        {{- if .Sql.AccessRules}}
+        {{.PrintSqlFuncUpdateAccessCheck}}{{"\n"}}
+        {{- end}}
         updateValue = '' || update_str_from_json(params, ARRAY [
 {{.PrintSqlFuncUpdateFlds}}
             ['options', 'options', 'jsonb'],
@@ -62,12 +75,20 @@ BEGIN
         THEN
             RETURN json_build_object('ok', FALSE, 'message', 'wrong id');
         END IF;
+        {{- if .Sql.AccessRules}}
+
+        {{.PrintSqlFuncUpdateAccessCheckRow}}
+        {{- end}}
 
     end if;
 
     {{.Sql.Hooks.Print "update" "afterInsertUpdate"}}
 
     RETURN {{.PgName}}_get_by_id(jsonb_build_object('id', {{.Name}}Row.id, 'user_id', (params->>'user_id')::int));
+{{- if .Sql.AccessRules}}
+
+{{.PrintSqlFuncAccessDeniedHandler}}
+{{- end}}
 
 END
 
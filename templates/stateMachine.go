@@ -13,7 +13,7 @@ func stateMachineReadTmplAction(funcMap template.FuncMap, path ...string) *templ
 	funcMap["tmplSqlActionPrintRefUpdateVarDeclare"] = t.DocSm{}.TmplSqlActionPrintRefUpdateVarDeclare
 	funcMap["tmplSqlActionPrintAfterHook"] = t.DocSm{}.TmplSqlActionPrintAfterHook
 
-	tmpls, err := template.New("").Funcs(funcMap).Delims("[[", "]]").ParseFiles(path...)
+	tmpls, err := utils.ParseTemplateFiles(template.New("").Funcs(funcMap).Delims("[[", "]]"), path...)
 	utils.CheckErr(err, "stateMachineReadTmplAction")
 	for _, tmpl := range tmpls.Templates() {
 		return tmpl
@@ -24,7 +24,7 @@ func stateMachineReadTmplAction(funcMap template.FuncMap, path ...string) *templ
 func stateMachineReadTmplUpdate(funcMap template.FuncMap, path ...string) *template.Template {
 	funcMap["tmplSqlUpdatePrintCaseBlock"] = t.DocSm{}.TmplSqlUpdatePrintCaseBlock
 
-	tmpls, err := template.New("").Funcs(funcMap).Delims("[[", "]]").ParseFiles(path...)
+	tmpls, err := utils.ParseTemplateFiles(template.New("").Funcs(funcMap).Delims("[[", "]]"), path...)
 	utils.CheckErr(err, "stateMachineReadTmplUpdate")
 	for _, tmpl := range tmpls.Templates() {
 		return tmpl
@@ -33,7 +33,7 @@ func stateMachineReadTmplUpdate(funcMap template.FuncMap, path ...string) *templ
 }
 
 func stateMachineReadTmplWebclientItem(funcMap template.FuncMap, path ...string) *template.Template {
-	tmpls, err := template.New("").Funcs(funcMap).Delims("[[", "]]").ParseFiles(path...)
+	tmpls, err := utils.ParseTemplateFiles(template.New("").Funcs(funcMap).Delims("[[", "]]"), path...)
 	utils.CheckErr(err, "stateMachineReadTmplWebclientItem")
 	for _, tmpl := range tmpls.Templates() {
 		return tmpl
@@ -0,0 +1,441 @@
+package tmplGenerateStep2
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/NL-A/nla_framework/types"
+	"github.com/NL-A/nla_framework/utils"
+)
+
+type (
+	// таблица для ER-диаграммы. Собирается по sql/model/*/main.toml, поэтому в диаграмме и служебные таблицы фреймворка
+	erTable struct {
+		Name     string
+		Comment  string
+		Cols     []erColumn
+		IsSystem bool // служебная таблица фреймворка (user, file и т.д.), а не документ проекта
+	}
+
+	erColumn struct {
+		Name       string
+		Type       string // тип как в main.toml: char(150), int, timestamp...
+		Comment    string
+		IsPKey     bool
+		IsRequired bool
+		IsUniq     bool
+		Ref        string
+	}
+
+	// связь многие-к-многим через таблицу связи (DocSql.IsUniqLink или виджет GetFldLinkListWidget)
+	erLink struct {
+		From, To, Via string
+	}
+)
+
+var (
+	erItemRe   = regexp.MustCompile(`\{[^{}]*\}`)
+	erParamRe  = regexp.MustCompile(`(\w+)\s*=\s*(?:"((?:[^"\\]|\\.)*)"|(\d+))`)
+	erUniqRe   = regexp.MustCompile(`(?i)^UNIQUE\s*\(([^)]*)\)$`)
+	erFolderRe = regexp.MustCompile(`[^\p{L}\p{N}_-]+`)
+)
+
+// первые 9 папок в sql/model - служебные таблицы фреймворка, документы начинаются с 10 (см utils.ParseDocTemplateFilename)
+const erDocModelIndex = 10
+
+// ER-диаграммы модели данных в docs/er: project.mmd / project.dot по всему проекту и отдельно по каждой папке меню.
+// Вызывается после генерации sql/model, поэтому колонки и ограничения те же, что попадут в базу
+func ErDiagram(p types.ProjectType) {
+	tables, err := readErTables(p)
+	utils.CheckErr(err, "ErDiagram")
+	links := erManyToManyLinks(p)
+	distPath := p.DistPath + "/docs/er"
+
+	all := map[string]bool{}
+	for _, t := range tables {
+		all[t.Name] = true
+	}
+	writeErDiagram(distPath, "project", tables, links, all)
+
+	usedNames := map[string]int{}
+	var printFolders func(menu []types.VueMenu)
+	printFolders = func(menu []types.VueMenu) {
+		for _, m := range menu {
+			if !m.IsFolder {
+				continue
+			}
+			focus := map[string]bool{}
+			for _, name := range erMenuDocNames(p, m.LinkList) {
+				focus[name] = true
+			}
+			if len(focus) > 0 {
+				filename := strings.Trim(erFolderRe.ReplaceAllString(m.Text, "_"), "_")
+				if len(filename) == 0 {
+					filename = "folder"
+				}
+				// папки с одинаковым названием на разных уровнях меню
+				usedNames[filename]++
+				if usedNames[filename] > 1 {
+					filename = fmt.Sprintf("%s_%v", filename, usedNames[filename])
+				}
+				writeErDiagram(distPath, "folder_"+filename, tables, links, focus)
+			}
+			printFolders(m.LinkList)
+		}
+	}
+	printFolders(p.Vue.Menu)
+}
+
+// таблицы документов из пунктов меню, включая вложенные папки
+func erMenuDocNames(p types.ProjectType, menu []types.VueMenu) []string {
+	res := []string{}
+	for _, m := range menu {
+		if m.IsFolder {
+			res = append(res, erMenuDocNames(p, m.LinkList)...)
+			continue
+		}
+		if d := p.GetDocByName(m.DocName); d != nil {
+			res = append(res, d.PgName())
+		}
+	}
+	return res
+}
+
+// focus - таблицы, которые выводятся с колонками. Таблицы, на которые они ссылаются, выводятся только названием
+func writeErDiagram(distPath, name string, tables []erTable, links []erLink, focus map[string]bool) {
+	shown := []erTable{}
+	isShown := map[string]bool{}
+	for _, t := range tables {
+		if focus[t.Name] {
+			shown = append(shown, t)
+			isShown[t.Name] = true
+		}
+	}
+	for _, t := range tables {
+		if isShown[t.Name] {
+			continue
+		}
+		for _, s := range shown {
+			if s.refersTo(t.Name) || erIsLinked(links, s.Name, t.Name) {
+				shown = append(shown, erTable{Name: t.Name, Comment: t.Comment, IsSystem: t.IsSystem})
+				isShown[t.Name] = true
+				break
+			}
+		}
+	}
+	shownLinks := []erLink{}
+	for _, l := range links {
+		if isShown[l.From] && isShown[l.To] && (focus[l.From] || focus[l.To]) {
+			shownLinks = append(shownLinks, l)
+		}
+	}
+	err := utils.WriteFile(fmt.Sprintf("%s/%s.mmd", distPath, name), []byte(printErMermaid(shown, shownLinks, isShown)))
+	utils.CheckErr(err, "ErDiagram WriteFile")
+	err = utils.WriteFile(fmt.Sprintf("%s/%s.dot", distPath, name), []byte(printErDot(name, shown, shownLinks, isShown, focus)))
+	utils.CheckErr(err, "ErDiagram WriteFile")
+}
+
+func printErMermaid(tables []erTable, links []erLink, isShown map[string]bool) string {
+	res := []string{"erDiagram"}
+	for _, t := range tables {
+		if len(t.Comment) > 0 {
+			res = append(res, fmt.Sprintf("    %%%% %s: %s", t.Name, t.Comment))
+		}
+		// таблица, на которую только ссылаются, появится в диаграмме по связи
+		if len(t.Cols) == 0 {
+			continue
+		}
+		res = append(res, fmt.Sprintf("    %s {", t.Name))
+		for _, c := range t.Cols {
+			keys := []string{}
+			if c.IsPKey {
+				keys = append(keys, "PK")
+			}
+			if len(c.Ref) > 0 {
+				keys = append(keys, "FK")
+			}
+			if c.IsUniq {
+				keys = append(keys, "UK")
+			}
+			line := fmt.Sprintf("        %s %s", strings.ReplaceAll(c.Type, " ", "_"), c.Name)
+			if len(keys) > 0 {
+				line += " " + strings.Join(keys, ", ")
+			}
+			if comment := c.fullComment(); len(comment) > 0 {
+				line += fmt.Sprintf(" \"%s\"", strings.ReplaceAll(comment, "\"", "'"))
+			}
+			res = append(res, line)
+		}
+		res = append(res, "    }")
+	}
+	for _, t := range tables {
+		for _, c := range t.Cols {
+			if len(c.Ref) == 0 || !isShown[c.Ref] {
+				continue
+			}
+			// многие записи таблицы ссылаются на одну. Если колонка не обязательная, то ссылки может и не быть
+			card := "o|"
+			if c.IsRequired {
+				card = "||"
+			}
+			res = append(res, fmt.Sprintf("    %s }o--%s %s : \"%s\"", t.Name, card, c.Ref, c.Name))
+		}
+	}
+	for _, l := range links {
+		res = append(res, fmt.Sprintf("    %s }o..o{ %s : \"%s\"", l.From, l.To, l.Via))
+	}
+	return strings.Join(res, "\n") + "\n"
+}
+
+func printErDot(name string, tables []erTable, links []erLink, isShown, focus map[string]bool) string {
+	res := []string{
+		fmt.Sprintf("digraph %s {", strconv.Quote(name)),
+		"    graph [rankdir=LR];",
+		"    node [shape=plaintext, fontname=\"Helvetica\", fontsize=10];",
+		"    edge [fontname=\"Helvetica\", fontsize=9, arrowhead=none, arrowtail=crow, dir=both];",
+	}
+	for _, t := range tables {
+		// документы проекта и служебные таблицы различаются цветом заголовка
+		color := "#d5e8f7"
+		if t.IsSystem {
+			color = "#e8e8e8"
+		}
+		style := ""
+		if !focus[t.Name] {
+			style = " style=\"dashed\""
+		}
+		title := "<b>" + html.EscapeString(t.Name) + "</b>"
+		if len(t.Comment) > 0 {
+			title += "<br/>" + html.EscapeString(t.Comment)
+		}
+		rows := []string{fmt.Sprintf("<tr><td bgcolor=\"%s\"%s>%s</td></tr>", color, style, title)}
+		for _, c := range t.Cols {
+			keys := []string{}
+			if c.IsPKey {
+				keys = append(keys, "PK")
+			}
+			if len(c.Ref) > 0 {
+				keys = append(keys, "FK")
+			}
+			if c.IsUniq {
+				keys = append(keys, "UK")
+			}
+			if c.IsRequired && !c.IsPKey {
+				keys = append(keys, "NN")
+			}
+			text := fmt.Sprintf("%s: %s", c.Name, c.Type)
+			if len(keys) > 0 {
+				text += " " + strings.Join(keys, ",")
+			}
+			rows = append(rows, fmt.Sprintf("<tr><td port=\"%s\" align=\"left\">%s</td></tr>", c.Name, html.EscapeString(text)))
+		}
+		res = append(res, fmt.Sprintf("    %s [label=<<table border=\"0\" cellborder=\"1\" cellspacing=\"0\">%s</table>>];", strconv.Quote(t.Name), strings.Join(rows, "")))
+	}
+	for _, t := range tables {
+		for _, c := range t.Cols {
+			if len(c.Ref) == 0 || !isShown[c.Ref] {
+				continue
+			}
+			// у таблиц, выведенных без колонок, нет портов
+			target := strconv.Quote(c.Ref)
+			if focus[c.Ref] {
+				target += ":id"
+			}
+			head := "teeodot"
+			if c.IsRequired {
+				head = "teetee"
+			}
+			res = append(res, fmt.Sprintf("    %s:%s -> %s [label=%s, arrowhead=%s];", strconv.Quote(t.Name), c.Name, target, strconv.Quote(c.Name), head))
+		}
+	}
+	for _, l := range links {
+		res = append(res, fmt.Sprintf("    %s -> %s [label=%s, style=dashed, arrowhead=crow];", strconv.Quote(l.From), strconv.Quote(l.To), strconv.Quote(l.Via)))
+	}
+	res = append(res, "}")
+	return strings.Join(res, "\n") + "\n"
+}
+
+// чтение таблиц из sql/model/*/main.toml. Документы берутся только те, что есть в проекте:
+// файлы удаленных документов могут еще лежать на диске до завершения генерации
+func readErTables(p types.ProjectType) ([]erTable, error) {
+	modelPath := p.DistPath + "/sql/model"
+	files, err := utils.GetOutput().ListFiles(modelPath)
+	if err != nil {
+		return nil, err
+	}
+	docNames := map[string]bool{}
+	for _, d := range p.Docs {
+		docNames[d.PgName()] = true
+	}
+	res := []erTable{}
+	for _, path := range files {
+		if !strings.HasSuffix(path, "/main.toml") {
+			continue
+		}
+		dir := strings.TrimSuffix(strings.TrimPrefix(path, strings.TrimSuffix(modelPath, "/")+"/"), "/main.toml")
+		index, _ := strconv.Atoi(strings.SplitN(dir, "_", 2)[0])
+		data, err := utils.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		t := parseErTable(string(data))
+		t.IsSystem = index < erDocModelIndex
+		if len(t.Name) == 0 || (!t.IsSystem && !docNames[t.Name]) {
+			continue
+		}
+		res = append(res, t)
+	}
+	return res, nil
+}
+
+// разбор main.toml модели таблицы: tableName, tableComment, fields и fkConstraints
+func parseErTable(src string) erTable {
+	t := erTable{}
+	for _, line := range strings.Split(src, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "tableName") {
+			t.Name = erUnquote(strings.TrimSpace(strings.SplitN(line, "=", 2)[1]))
+		}
+		if strings.HasPrefix(line, "tableComment") {
+			t.Comment = erUnquote(strings.TrimSpace(strings.SplitN(line, "=", 2)[1]))
+		}
+	}
+	isAdded := map[string]bool{}
+	for _, item := range erItemRe.FindAllString(erTomlSection(src, "fields"), -1) {
+		params := erItemParams(item)
+		c := erColumn{Name: params["name"], Type: params["type"], Comment: params["comment"]}
+		// колонка может быть объявлена дважды (например, options у документа) - в базе будет одна
+		if len(c.Name) == 0 || isAdded[c.Name] {
+			continue
+		}
+		isAdded[c.Name] = true
+		if size, ok := params["size"]; ok {
+			c.Type = fmt.Sprintf("%s(%s)", c.Type, size)
+		}
+		c.IsPKey = c.Name == "id"
+		c.IsRequired = c.IsPKey || strings.Contains(params["ext"], "not null")
+		t.Cols = append(t.Cols, c)
+	}
+	for _, item := range erItemRe.FindAllString(erTomlSection(src, "fkConstraints"), -1) {
+		params := erItemParams(item)
+		if fld, ok := params["fld"]; ok {
+			t.setCol(fld, func(c *erColumn) { c.Ref = strings.Trim(params["ref"], `"`) })
+		}
+		if m := erUniqRe.FindStringSubmatch(strings.TrimSpace(params["ext"])); m != nil && !strings.Contains(m[1], ",") {
+			t.setCol(strings.TrimSpace(m[1]), func(c *erColumn) { c.IsUniq = true })
+		}
+	}
+	return t
+}
+
+// связи многие-к-многим: таблицы связи (IsUniqLink) и виджеты GetFldLinkListWidget
+func erManyToManyLinks(p types.ProjectType) []erLink {
+	res := []erLink{}
+	isAdded := map[string]bool{}
+	add := func(a, b, via string) {
+		if len(a) == 0 || len(b) == 0 {
+			return
+		}
+		if a > b {
+			a, b = b, a
+		}
+		key := a + "|" + b + "|" + via
+		if !isAdded[key] {
+			isAdded[key] = true
+			res = append(res, erLink{From: a, To: b, Via: via})
+		}
+	}
+	refs := func(d types.DocType) []string {
+		arr := []string{}
+		for _, f := range d.Flds {
+			if len(f.Sql.Ref) > 0 {
+				arr = append(arr, f.Sql.Ref)
+			}
+		}
+		return arr
+	}
+	for _, d := range p.Docs {
+		if arr := refs(d); d.Sql.IsUniqLink && len(arr) > 1 {
+			add(arr[0], arr[1], d.PgName())
+		}
+		for _, f := range d.Flds {
+			if len(f.Vue.LinkTable) == 0 {
+				continue
+			}
+			if link := p.GetDocByName(f.Vue.LinkTable); link != nil {
+				for _, ref := range refs(*link) {
+					if ref != d.Name {
+						add(d.PgName(), ref, link.PgName())
+					}
+				}
+			}
+		}
+	}
+	return res
+}
+
+func erIsLinked(links []erLink, a, b string) bool {
+	for _, l := range links {
+		if (l.From == a && l.To == b) || (l.From == b && l.To == a) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t erTable) refersTo(name string) bool {
+	for _, c := range t.Cols {
+		if c.Ref == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *erTable) setCol(name string, fn func(c *erColumn)) {
+	for i := range t.Cols {
+		if t.Cols[i].Name == name {
+			fn(&t.Cols[i])
+		}
+	}
+}
+
+func (c erColumn) fullComment() string {
+	arr := []string{}
+	if c.IsRequired && !c.IsPKey {
+		arr = append(arr, "not null")
+	}
+	if len(c.Comment) > 0 {
+		arr = append(arr, c.Comment)
+	}
+	return strings.Join(arr, ", ")
+}
+
+// содержимое секции вида name = [ ... ]
+func erTomlSection(src, name string) string {
+	m := regexp.MustCompile(`(?ms)^\s*` + name + `\s*=\s*\[(.*?)^\s*\]`).FindStringSubmatch(src)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+func erItemParams(item string) map[string]string {
+	res := map[string]string{}
+	for _, m := range erParamRe.FindAllStringSubmatch(item, -1) {
+		if len(m[3]) > 0 {
+			res[m[1]] = m[3]
+		} else {
+			res[m[1]] = strings.ReplaceAll(m[2], `\"`, `"`)
+		}
+	}
+	return res
+}
+
+// "user" или "\"user\"" -> user
+func erUnquote(s string) string {
+	return strings.Trim(strings.ReplaceAll(s, `\"`, `"`), `"`)
+}
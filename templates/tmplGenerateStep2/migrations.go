@@ -0,0 +1,475 @@
+package tmplGenerateStep2
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/NL-A/nla_framework/types"
+	"github.com/NL-A/nla_framework/utils"
+)
+
+// Миграции схемы базы. alterScripts в main.toml только добавляют колонки, поэтому остальные изменения модели
+// (тип, размер, not null, default, удаленные и переименованные колонки и таблицы, check/unique ограничения) оформляются миграциями:
+//   - sql/migrations/schema_snapshot.json - модель на момент прошлой генерации
+//   - sql/migrations/NNNN_<таблицы>.up.sql и .down.sql - изменения относительно снимка
+//
+// Миграции применяет при старте приложение (pg/migrations.go) и отмечает в таблице schema_migrations.
+// Шаги, при которых теряются данные, помечаются строкой "-- destructive: ..." и выполняются только после явного подтверждения.
+// Файлы миграций и снимок пишутся мимо манифеста: это история изменений, а не перегенерируемые файлы
+
+const (
+	migrationsDir          = "/sql/migrations"
+	migrationsSnapshotFile = "schema_snapshot.json"
+	// префикс строки с описанием шага, при котором теряются данные. Его же ищет pg/migrations.go
+	migrationDestructivePrefix = "-- destructive: "
+)
+
+type (
+	migrationSnapshot struct {
+		Tables map[string]migrationTable `json:"tables"`
+	}
+
+	migrationTable struct {
+		Columns     []migrationColumn `json:"columns"`
+		Constraints map[string]string `json:"constraints,omitempty"` // check и unique ограничения: название - определение
+		PrevNames   []string          `json:"-"`                     // DocSql.PrevNames. В снимок не пишутся, берутся из текущего описания документа
+	}
+
+	migrationColumn struct {
+		Name      string   `json:"name"`
+		Type      string   `json:"type"`
+		NotNull   bool     `json:"notNull,omitempty"`
+		Default   string   `json:"default,omitempty"`
+		PrevNames []string `json:"-"` // FldSql.PrevNames
+	}
+
+	migrationStep struct {
+		Up          []string
+		Down        []string
+		Destructive string // описание потери данных или шага, который упадет на таблице с записями. Пустая строка - шаг безопасный
+	}
+)
+
+var (
+	migrationFileRe    = regexp.MustCompile(`^(\d+)_.*\.up\.sql$`)
+	migrationDefaultRe = regexp.MustCompile(`(?i)\bdefault\s+(.+)$`)
+	migrationVarcharRe = regexp.MustCompile(`^character varying\((\d+)\)$`)
+)
+
+// снимок текущей модели и, если есть отличия от прошлого снимка, новая миграция
+func Migrations(p types.ProjectType) {
+	distPath := p.DistPath + migrationsDir
+	current, err := readMigrationSnapshot(p)
+	utils.CheckErr(err, "Migrations")
+	data, err := json.MarshalIndent(current, "", "  ")
+	utils.CheckErr(err, "Migrations Marshal")
+	data = append(data, '\n')
+
+	prevData, err := utils.ReadFile(distPath + "/" + migrationsSnapshotFile)
+	if err != nil && !os.IsNotExist(err) {
+		utils.CheckErr(err, "Migrations ReadFile")
+	}
+	// первая генерация - таблицы создаются по модели, миграции не нужны. Сохраняем снимок как точку отсчета
+	if len(prevData) > 0 {
+		prev := migrationSnapshot{}
+		err = json.Unmarshal(prevData, &prev)
+		utils.CheckErr(err, fmt.Sprintf("Migrations %s", migrationsSnapshotFile))
+		if steps, tables := diffMigrationSnapshots(prev, current); len(steps) > 0 {
+			writeMigration(distPath, steps, tables)
+		}
+	}
+	if !utils.ByteSliceEqual(prevData, data) {
+		err = utils.GetOutput().WriteFile(distPath+"/"+migrationsSnapshotFile, data)
+		utils.CheckErr(err, "Migrations WriteFile")
+	}
+}
+
+func writeMigration(distPath string, steps []migrationStep, tables []string) {
+	files, err := utils.ReadDirNames(distPath)
+	utils.CheckErr(err, "Migrations ReadDirNames")
+	version := 0
+	for _, f := range files {
+		if m := migrationFileRe.FindStringSubmatch(f); m != nil {
+			if v, _ := strconv.Atoi(m[1]); v > version {
+				version = v
+			}
+		}
+	}
+	version++
+	name := strings.Join(tables, "_")
+	if len(name) > 50 {
+		name = name[:50]
+	}
+	name = fmt.Sprintf("%04d_%s", version, strings.Trim(name, "_"))
+
+	up := []string{fmt.Sprintf("-- миграция %s. Сгенерирована по изменениям sql/model", name)}
+	down := []string{fmt.Sprintf("-- откат миграции %s. Автоматически не применяется", name)}
+	for _, s := range steps {
+		if len(s.Destructive) > 0 {
+			up = append(up, migrationDestructivePrefix+s.Destructive)
+		}
+	}
+	for _, s := range steps {
+		up = append(up, "", strings.Join(s.Up, "\n"))
+	}
+	for i := len(steps) - 1; i >= 0; i-- {
+		if len(steps[i].Down) > 0 {
+			down = append(down, "", strings.Join(steps[i].Down, "\n"))
+		}
+	}
+	err = utils.GetOutput().WriteFile(fmt.Sprintf("%s/%s.up.sql", distPath, name), []byte(strings.Join(up, "\n")+"\n"))
+	utils.CheckErr(err, "Migrations WriteFile")
+	err = utils.GetOutput().WriteFile(fmt.Sprintf("%s/%s.down.sql", distPath, name), []byte(strings.Join(down, "\n")+"\n"))
+	utils.CheckErr(err, "Migrations WriteFile")
+	// при генерации в память (dry run, diff) файл не создается - миграция видна в отчете по изменениям
+	if _, ok := utils.GetOutput().(utils.DirOutput); ok {
+		fmt.Printf("new migration: %s.up.sql\n", name)
+	}
+}
+
+// модель по sql/model/*/main.toml - те же таблицы, что попадают в ER-диаграмму
+func readMigrationSnapshot(p types.ProjectType) (migrationSnapshot, error) {
+	res := migrationSnapshot{Tables: map[string]migrationTable{}}
+	modelPath := p.DistPath + "/sql/model"
+	files, err := utils.GetOutput().ListFiles(modelPath)
+	if err != nil {
+		return res, err
+	}
+	docs := map[string]types.DocType{}
+	for _, d := range p.Docs {
+		docs[d.PgName()] = d
+	}
+	for _, path := range files {
+		if !strings.HasSuffix(path, "/main.toml") {
+			continue
+		}
+		dir := strings.TrimSuffix(strings.TrimPrefix(path, strings.TrimSuffix(modelPath, "/")+"/"), "/main.toml")
+		index, _ := strconv.Atoi(strings.SplitN(dir, "_", 2)[0])
+		data, err := utils.ReadFile(path)
+		if err != nil {
+			return res, err
+		}
+		name, t := parseMigrationTable(string(data))
+		d, isDoc := docs[name]
+		if len(name) == 0 || (index >= erDocModelIndex && !isDoc) {
+			continue
+		}
+		// прежние названия таблицы и колонок есть только в описании документа
+		if isDoc {
+			t.PrevNames = d.Sql.PrevNames
+			fldPrevNames := map[string][]string{}
+			for _, fld := range d.Flds {
+				fldPrevNames[fld.Name] = fld.Sql.PrevNames
+			}
+			for i, c := range t.Columns {
+				t.Columns[i].PrevNames = fldPrevNames[c.Name]
+			}
+		}
+		res.Tables[name] = t
+	}
+	return res, nil
+}
+
+func parseMigrationTable(src string) (string, migrationTable) {
+	name := ""
+	for _, line := range strings.Split(src, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "tableName") {
+			name = erUnquote(strings.TrimSpace(strings.SplitN(line, "=", 2)[1]))
+		}
+	}
+	t := migrationTable{Constraints: map[string]string{}}
+	isAdded := map[string]bool{}
+	for _, item := range erItemRe.FindAllString(erTomlSection(src, "fields"), -1) {
+		params := erItemParams(item)
+		// id создается вместе с таблицей. Колонка может быть объявлена дважды (например, options у документа)
+		if len(params["name"]) == 0 || params["name"] == "id" || isAdded[params["name"]] {
+			continue
+		}
+		isAdded[params["name"]] = true
+		ext := strings.TrimSpace(params["ext"])
+		c := migrationColumn{Name: params["name"], Type: migrationPgType(params["type"], params["size"], ext), NotNull: strings.Contains(strings.ToLower(ext), "not null")}
+		if m := migrationDefaultRe.FindStringSubmatch(ext); m != nil {
+			c.Default = strings.TrimSpace(m[1])
+		}
+		t.Columns = append(t.Columns, c)
+	}
+	for _, item := range erItemRe.FindAllString(erTomlSection(src, "fkConstraints"), -1) {
+		params := erItemParams(item)
+		// внешние ключи (fld/ref) создает pg_generate, здесь только именованные check и unique
+		if len(params["name"]) > 0 && len(params["ext"]) > 0 {
+			t.Constraints[params["name"]] = params["ext"]
+		}
+	}
+	return name, t
+}
+
+// тип колонки в том виде, в котором его создает pg_generate по main.toml
+func migrationPgType(typ, size, ext string) string {
+	switch typ {
+	case "char":
+		if len(size) > 0 {
+			return fmt.Sprintf("character varying(%s)", size)
+		}
+		return "text"
+	case "int":
+		return "integer"
+	case "double":
+		return "double precision"
+	case "bool":
+		return "boolean"
+	case "timestamp":
+		if strings.Contains(ext, "with time zone") {
+			return "timestamp with time zone"
+		}
+	}
+	return typ
+}
+
+// шаги миграции от prev к current. Новые таблицы создает pg_generate, поэтому в миграцию не попадают.
+// Исключение - таблица, у которой в PrevNames указано название из прошлого снимка: она переименовывается
+func diffMigrationSnapshots(prev, current migrationSnapshot) ([]migrationStep, []string) {
+	steps := []migrationStep{}
+	tables := []string{}
+	// новое название таблицы - прежнее
+	renamedFrom := map[string]string{}
+	isRenamed := map[string]bool{}
+	for _, name := range migrationSortedTables(prev, current) {
+		if _, ok := prev.Tables[name]; ok {
+			continue
+		}
+		for _, old := range current.Tables[name].PrevNames {
+			if _, inCurrent := current.Tables[old]; !inCurrent && !isRenamed[old] {
+				if _, inPrev := prev.Tables[old]; inPrev {
+					renamedFrom[name] = old
+					isRenamed[old] = true
+					break
+				}
+			}
+		}
+	}
+	for _, name := range migrationSortedTables(prev, current) {
+		pt, inPrev := prev.Tables[name]
+		ct, inCurrent := current.Tables[name]
+		if !inCurrent && isRenamed[name] {
+			continue
+		}
+		if old, ok := renamedFrom[name]; ok {
+			steps = append(steps, migrationRenameTable(old, name))
+			pt, inPrev = prev.Tables[old], true
+		}
+		if !inCurrent {
+			steps = append(steps, migrationStep{
+				// функция <doc>_access (DocSql.AccessRules) зависит от типа строки таблицы и мешает ее удалению
+				Up:          []string{fmt.Sprintf("drop function if exists %s_access(int, %s);", name, migrationQuote(name)), fmt.Sprintf("drop table if exists %s;", migrationQuote(name))},
+				Down:        []string{fmt.Sprintf("-- таблица %s удалена: восстанавливается из резервной копии", name)},
+				Destructive: fmt.Sprintf("drop table %s", name),
+			})
+			tables = append(tables, name)
+			continue
+		}
+		if !inPrev {
+			continue
+		}
+		tableSteps := diffMigrationTable(migrationQuote(name), pt, ct)
+		if len(tableSteps) > 0 || len(renamedFrom[name]) > 0 {
+			steps = append(steps, tableSteps...)
+			tables = append(tables, name)
+		}
+	}
+	return steps, tables
+}
+
+func diffMigrationTable(table string, prev, current migrationTable) []migrationStep {
+	res := []migrationStep{}
+	prevCols := map[string]migrationColumn{}
+	for _, c := range prev.Columns {
+		prevCols[c.Name] = c
+	}
+	currentCols := map[string]bool{}
+	for _, c := range current.Columns {
+		currentCols[c.Name] = true
+	}
+	// прежние названия переименованных колонок. Такие колонки не удаляются
+	isRenamed := map[string]bool{}
+	for _, c := range current.Columns {
+		pc, ok := prevCols[c.Name]
+		if !ok {
+			for _, old := range c.PrevNames {
+				if oldCol, inPrev := prevCols[old]; inPrev && !currentCols[old] && !isRenamed[old] {
+					pc, ok = oldCol, true
+					isRenamed[old] = true
+					res = append(res, migrationStep{
+						Up:   []string{fmt.Sprintf("alter table %s rename column %s to %s;", table, old, c.Name)},
+						Down: []string{fmt.Sprintf("alter table %s rename column %s to %s;", table, c.Name, old)},
+					})
+					break
+				}
+			}
+		}
+		if !ok {
+			res = append(res, migrationAddColumn(table, c))
+			continue
+		}
+		if pc.Type != c.Type {
+			step := migrationStep{
+				Up:   []string{fmt.Sprintf("alter table %s alter column %s type %s using %[2]s::%[3]s;", table, c.Name, c.Type)},
+				Down: []string{fmt.Sprintf("alter table %s alter column %s type %s using %[2]s::%[3]s;", table, c.Name, pc.Type)},
+			}
+			if !migrationIsWidening(pc.Type, c.Type) {
+				step.Destructive = fmt.Sprintf("%s.%s type %s -> %s", table, c.Name, pc.Type, c.Type)
+			}
+			res = append(res, step)
+		}
+		if pc.Default != c.Default {
+			res = append(res, migrationStep{Up: []string{migrationSetDefault(table, c.Name, c.Default)}, Down: []string{migrationSetDefault(table, c.Name, pc.Default)}})
+		}
+		if pc.NotNull != c.NotNull {
+			res = append(res, migrationNotNull(table, c, !c.NotNull))
+		}
+	}
+	for _, c := range prev.Columns {
+		if currentCols[c.Name] || isRenamed[c.Name] {
+			continue
+		}
+		down := migrationAddColumn(table, c).Up
+		res = append(res, migrationStep{
+			Up:          []string{fmt.Sprintf("alter table %s drop column if exists %s;", table, c.Name)},
+			Down:        append([]string{fmt.Sprintf("-- данные колонки %s не восстанавливаются", c.Name)}, down...),
+			Destructive: fmt.Sprintf("drop column %s.%s", table, c.Name),
+		})
+	}
+	for _, name := range utils.SortedKeys(current.Constraints) {
+		def := current.Constraints[name]
+		if prevDef, ok := prev.Constraints[name]; ok && prevDef == def {
+			continue
+		}
+		down := []string{fmt.Sprintf("alter table %s drop constraint if exists %s;", table, name)}
+		if prevDef, ok := prev.Constraints[name]; ok {
+			down = append(down, fmt.Sprintf("alter table %s add constraint %s %s;", table, name, prevDef))
+		}
+		res = append(res, migrationStep{
+			Up:   []string{fmt.Sprintf("alter table %s drop constraint if exists %s;", table, name), fmt.Sprintf("alter table %s add constraint %s %s;", table, name, def)},
+			Down: down,
+		})
+	}
+	for _, name := range utils.SortedKeys(prev.Constraints) {
+		if _, ok := current.Constraints[name]; !ok {
+			res = append(res, migrationStep{
+				Up:   []string{fmt.Sprintf("alter table %s drop constraint if exists %s;", table, name)},
+				Down: []string{fmt.Sprintf("alter table %s add constraint %s %s;", table, name, prev.Constraints[name])},
+			})
+		}
+	}
+	return res
+}
+
+// переименование таблицы. Триггеры с прежним названием удаляются: pg_generate создаст их заново с новым названием,
+// иначе builtin_fld_update и прочие триггерные функции будут срабатывать дважды
+func migrationRenameTable(old, name string) migrationStep {
+	up := []string{fmt.Sprintf("alter table %s rename to %s;", migrationQuote(old), migrationQuote(name))}
+	for _, suffix := range []string{"created", "trigger_before", "trigger_after", "event", "history"} {
+		up = append(up, fmt.Sprintf("drop trigger if exists %s_%s on %s;", old, suffix, migrationQuote(name)))
+	}
+	up = append(up, fmt.Sprintf("drop function if exists %s_access(int, %s);", old, migrationQuote(name)))
+	// таблица истории изменений (DocSql.IsHistory) не входит в снимок схемы, поэтому переименовывается вместе с документом
+	up = append(up,
+		fmt.Sprintf("alter table if exists %s rename to %s;", migrationQuote(old+"_history"), migrationQuote(name+"_history")),
+		fmt.Sprintf("alter index if exists %s_history_doc_id_idx rename to %s_history_doc_id_idx;", old, name),
+	)
+	return migrationStep{
+		Up: up,
+		Down: []string{
+			fmt.Sprintf("alter table %s rename to %s;", migrationQuote(name), migrationQuote(old)),
+			fmt.Sprintf("alter table if exists %s rename to %s;", migrationQuote(name+"_history"), migrationQuote(old+"_history")),
+			fmt.Sprintf("alter index if exists %s_history_doc_id_idx rename to %s_history_doc_id_idx;", name, old),
+		},
+	}
+}
+
+func migrationAddColumn(table string, c migrationColumn) migrationStep {
+	def := ""
+	if len(c.Default) > 0 {
+		def = " default " + c.Default
+	}
+	up := []string{fmt.Sprintf("alter table %s add column if not exists %s %s%s;", table, c.Name, c.Type, def)}
+	step := migrationStep{Down: []string{fmt.Sprintf("alter table %s drop column if exists %s;", table, c.Name)}}
+	if c.NotNull {
+		up = append(up, fmt.Sprintf("alter table %s alter column %s set not null;", table, c.Name))
+		// у существующих строк значение null. Без default установка not null упадет, если в таблице есть записи,
+		// поэтому шаг выполняется только после явного подтверждения
+		if len(c.Default) == 0 {
+			step.Destructive = fmt.Sprintf("add not null column %s.%s without default: fails if table has rows", table, c.Name)
+		}
+	}
+	step.Up = up
+	return step
+}
+
+func migrationSetDefault(table, col, def string) string {
+	if len(def) == 0 {
+		return fmt.Sprintf("alter table %s alter column %s drop default;", table, col)
+	}
+	return fmt.Sprintf("alter table %s alter column %s set default %s;", table, col, def)
+}
+
+func migrationNotNull(table string, c migrationColumn, isDrop bool) migrationStep {
+	setNotNull := []string{fmt.Sprintf("alter table %s alter column %s set not null;", table, c.Name)}
+	if len(c.Default) > 0 {
+		// пустые значения заполняем default, иначе set not null упадет
+		setNotNull = append([]string{fmt.Sprintf("update %s set %s = %s where %[2]s is null;", table, c.Name, c.Default)}, setNotNull...)
+	}
+	dropNotNull := []string{fmt.Sprintf("alter table %s alter column %s drop not null;", table, c.Name)}
+	if isDrop {
+		return migrationStep{Up: dropNotNull, Down: setNotNull}
+	}
+	step := migrationStep{Up: setNotNull, Down: dropNotNull}
+	if len(c.Default) == 0 {
+		step.Destructive = fmt.Sprintf("set not null %s.%s without default: fails if column has nulls", table, c.Name)
+	}
+	return step
+}
+
+// изменение типа без потери данных: увеличение размера строки, строка -> text, int -> double precision
+func migrationIsWidening(from, to string) bool {
+	if mFrom := migrationVarcharRe.FindStringSubmatch(from); mFrom != nil {
+		if to == "text" {
+			return true
+		}
+		if mTo := migrationVarcharRe.FindStringSubmatch(to); mTo != nil {
+			sizeFrom, _ := strconv.Atoi(mFrom[1])
+			sizeTo, _ := strconv.Atoi(mTo[1])
+			return sizeTo >= sizeFrom
+		}
+	}
+	return from == "integer" && utils.CheckContainsSliceStr(to, "bigint", "double precision")
+}
+
+func migrationSortedTables(a, b migrationSnapshot) []string {
+	names := map[string]bool{}
+	for k := range a.Tables {
+		names[k] = true
+	}
+	for k := range b.Tables {
+		names[k] = true
+	}
+	res := []string{}
+	for k := range names {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// user - зарезервированное слово в postgres
+func migrationQuote(table string) string {
+	if table == "user" {
+		return `"user"`
+	}
+	return table
+}
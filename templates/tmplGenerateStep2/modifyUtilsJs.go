@@ -2,9 +2,6 @@ package tmplGenerateStep2
 
 import (
 	"fmt"
-	"log"
-	"path"
-	"runtime"
 	"strings"
 	"text/template"
 
@@ -27,7 +24,7 @@ func PluginUtilsJs(p types.ProjectType) {
 		},
 	}
 	path := fmt.Sprintf("%s/project/webClient/quasar_%v/app/plugins/utils.js", getPathDirTemplate(), p.GetQuasarVersion())
-	t, err := template.New("utils.js").Funcs(funcMap).Delims("[[", "]]").ParseFiles(path)
+	t, err := utils.ParseTemplateFiles(template.New("utils.js").Funcs(funcMap).Delims("[[", "]]"), path)
 	utils.CheckErr(err, "OverriteCopiedFiles ParseFiles")
 
 	err = executeToFile(t, p, distPath, "utils.js")
@@ -39,7 +36,7 @@ func BootI18nJs(p types.ProjectType) {
 	distPath := fmt.Sprintf("%s/webClient/src/boot", p.DistPath)
 
 	path := fmt.Sprintf("%s/project/webClient/quasar_%v/boot/i18n.js", getPathDirTemplate(), p.GetQuasarVersion())
-	t, err := template.New("i18n.js").Delims("[[", "]]").ParseFiles(path)
+	t, err := utils.ParseTemplateFiles(template.New("i18n.js").Delims("[[", "]]"), path)
 	utils.CheckErr(err, "OverrideCopiedFiles ParseFiles")
 
 	err = executeToFile(t, p, distPath, "i18n.js")
@@ -92,12 +89,13 @@ const %s = (v) => {
 			}
 		}
 		// в документе может быть прописан дополнительный глобальный справочник
-		for fNname, m := range d.Vue.GloablI18n {
+		for _, fNname := range utils.SortedKeys(d.Vue.GloablI18n) {
+			m := d.Vue.GloablI18n[fNname]
 			// название функции
 			funcNames = fmt.Sprintf("%s%s,\n\t", funcNames, fNname)
 			arr := []string{}
-			for val, label := range m {
-				arr = append(arr, fmt.Sprintf("%s: '%s'", val, label))
+			for _, val := range utils.SortedKeys(m) {
+				arr = append(arr, fmt.Sprintf("%s: '%s'", val, m[val]))
 			}
 			funcBodyes = fmt.Sprintf(`%s
 const %s = (v) => {
@@ -112,11 +110,7 @@ const %s = (v) => {
 	return
 }
 
+// директория с шаблонами фреймворка. Файлы читаются из embed.FS (см utils.FrameworkPathPrefix)
 func getPathDirTemplate() string {
-	_, filename, _, ok := runtime.Caller(0)
-	if !ok {
-		log.Fatalf("ParseTemplates runtime.Caller: No caller information")
-	}
-	return strings.TrimSuffix(path.Dir(filename), "/tmplGenerateStep2")
-
+	return utils.FrameworkPath("templates")
 }
@@ -0,0 +1,394 @@
+package tmplGenerateStep2
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/NL-A/nla_framework/types"
+	"github.com/NL-A/nla_framework/utils"
+)
+
+// описание api проекта в формате OpenAPI 3. Пишется в docs/openapi.json, в dev режиме отдается web сервером вместе со Swagger UI по адресу /swagger
+//
+// Все sql методы вызываются одним запросом POST /api/call_pg_func с телом {method, params}. Чтобы в документации у каждого метода
+// была своя операция, путь дополняется якорем: /api/call_pg_func#client_list. Якорь браузер на сервер не отправляет, поэтому "Try it out" в Swagger UI работает
+
+type oaObj map[string]interface{}
+
+// методы пользователей, которые прописаны в apiCallPgFunc.go вручную
+var oaUserMethods = []string{"user_update", "user_list", "user_get_by_id", "user_get_by_id_for_ui", "current_user_update", "current_user_get_auth_providers"}
+
+func OpenApi(p types.ProjectType) {
+	paths := oaObj{}
+	schemas := oaObj{}
+
+	// методы пользователей. Роли как в apiCallPgFunc.go
+	userRoles := map[string][]string{
+		"user_update":    append([]string{"admin"}, p.Config.User.Roles.UserUpdate...),
+		"user_list":      p.Config.User.Roles.UserList,
+		"user_get_by_id": p.Config.User.Roles.UserGetById,
+	}
+	for _, name := range oaUserMethods {
+		paths["/api/call_pg_func#"+name] = oaObj{"post": oaMethodOperation(name, "user", userRoles[name], oaObj{"type": "object"}, oaObj{})}
+	}
+
+	for _, m := range p.ApiCallPgFuncMethods() {
+		if _, ok := paths["/api/call_pg_func#"+m.Name]; ok {
+			continue
+		}
+		params, result := oaObj{"type": "object"}, oaObj{}
+		tag := "other"
+		if d, kind := docForApiMethod(p, m.Name); d != nil {
+			tag = d.Name
+			params, result = oaDocMethodSchemas(*d, kind)
+			schemas[d.Name] = oaDocSchema(*d)
+		}
+		paths["/api/call_pg_func#"+m.Name] = oaObj{"post": oaMethodOperation(m.Name, tag, m.Roles, params, result)}
+	}
+
+	for _, v := range oaAuthEndpoints(p) {
+		paths["/auth/"+v.name] = oaObj{"post": oaObj{
+			"operationId": "auth_" + v.name,
+			"summary":     v.summary,
+			"tags":        []string{"auth"},
+			"security":    []oaObj{},
+			"requestBody": oaRequestBody(oaObj{"type": "object", "properties": oaObj{"params": v.params}}),
+			"responses":   oaResponses(oaObj{}),
+		}}
+	}
+
+	schemas["Envelope"] = oaObj{
+		"type": "object",
+		"properties": oaObj{
+			"ok":        oaObj{"type": "boolean"},
+			"result":    oaObj{"description": "результат метода"},
+			"message":   oaObj{"type": "string", "description": "текст ошибки при ok = false"},
+			"meta_info": oaObj{"type": "object", "description": "дополнительная информация к результату"},
+		},
+		"required": []string{"ok"},
+	}
+
+	doc := oaObj{
+		"openapi": "3.0.3",
+		"info": oaObj{
+			"title":       p.Name,
+			"version":     "1.0.0",
+			"description": "Sql методы вызываются запросом POST /api/call_pg_func с телом {\"method\": \"<название>\", \"params\": {...}}. Часть пути после # - только для документации.",
+		},
+		"paths": paths,
+		"components": oaObj{
+			"schemas": schemas,
+			"securitySchemes": oaObj{
+				"authToken": oaObj{"type": "apiKey", "in": "header", "name": "Auth-token"},
+			},
+		},
+		"security": []oaObj{{"authToken": []string{}}},
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	utils.CheckErr(err, "OpenApi Marshal")
+	err = utils.WriteFile(p.DistPath+"/docs/openapi.json", append(data, '\n'))
+	utils.CheckErr(err, "OpenApi WriteFile")
+}
+
+// операция для метода из call_pg_func
+func oaMethodOperation(name, tag string, roles []string, params, result oaObj) oaObj {
+	if roles == nil {
+		roles = []string{}
+	}
+	description := "доступен всем авторизованным пользователям"
+	if len(roles) > 0 {
+		description = "роли: " + strings.Join(roles, ", ")
+	}
+	return oaObj{
+		"operationId": name,
+		"summary":     name,
+		"description": description,
+		"tags":        []string{tag},
+		"x-roles":     roles,
+		"requestBody": oaRequestBody(oaObj{
+			"type": "object",
+			"properties": oaObj{
+				"method": oaObj{"type": "string", "enum": []string{name}},
+				"params": params,
+			},
+			"required": []string{"method"},
+		}),
+		"responses": oaResponses(result),
+	}
+}
+
+func oaRequestBody(schema oaObj) oaObj {
+	return oaObj{"required": true, "content": oaObj{"application/json": oaObj{"schema": schema}}}
+}
+
+// ответ всегда в обертке {ok, result, message, meta_info}
+func oaResponses(result oaObj) oaObj {
+	schema := oaObj{"$ref": "#/components/schemas/Envelope"}
+	if len(result) > 0 {
+		schema = oaObj{"allOf": []oaObj{schema, {"type": "object", "properties": oaObj{"result": result}}}}
+	}
+	return oaObj{"200": oaObj{"description": "ok = false и message - в случае ошибки", "content": oaObj{"application/json": oaObj{"schema": schema}}}}
+}
+
+// документ и тип метода (list, update...) по названию метода
+func docForApiMethod(p types.ProjectType, name string) (*types.DocType, string) {
+	for i, d := range p.Docs {
+		for _, prefix := range []string{d.Name + "_", d.PgName() + "_"} {
+			if !strings.HasPrefix(name, prefix) {
+				continue
+			}
+			if _, ok := d.Sql.Methods[name]; ok {
+				return &p.Docs[i], strings.TrimPrefix(name, prefix)
+			}
+			for _, m := range p.Sql.Methods[d.Name] {
+				if m.Name == name {
+					return &p.Docs[i], strings.TrimPrefix(name, prefix)
+				}
+			}
+		}
+	}
+	return nil, ""
+}
+
+// параметры и результат стандартных методов документа
+func oaDocMethodSchemas(d types.DocType, kind string) (oaObj, oaObj) {
+	ref := oaObj{"$ref": "#/components/schemas/" + d.Name}
+	id := oaObj{"type": "integer"}
+	switch kind {
+	case "list":
+		props := oaObj{
+			"deleted":     oaObj{"type": "boolean", "default": false, "description": "удаленные / существующие"},
+			"order_by":    oaObj{"type": "string", "description": "поле и направление сортировки, например 'id desc'"},
+			"page":        oaObj{"type": "integer", "default": 1},
+			"per_page":    oaObj{"type": "integer", "default": 1000},
+			"search_text": oaObj{"type": "string", "description": "текстовый поиск"},
+			"with_total":  oaObj{"type": "boolean", "default": false, "description": "общее количество записей по фильтру в meta_info.total"},
+			"cursor": oaObj{"type": "object", "nullable": true, "description": "курсорная пагинация вместо page: null - первая страница, далее meta_info.next_cursor", "properties": oaObj{
+				"value": oaObj{"description": "значение поля сортировки последней записи"},
+				"id":    id,
+			}},
+		}
+		if d.Sql.IsFullTextSearch {
+			props["is_default_order"] = oaObj{"type": "boolean", "default": false, "description": "order_by не выбран пользователем: при search_text сортировка по релевантности"}
+		}
+		// фильтры - те же поля, что в where_str_build функции list
+		for _, f := range d.Flds {
+			if f.Name != "title" && (len(f.Sql.Ref) > 0 || f.Sql.IsSearch) {
+				props[f.Name] = oaFldSchema(f)
+			}
+		}
+		for _, fp := range d.ListFilterParams() {
+			props[fp.Param] = oaFilterParamSchema(fp)
+		}
+		return oaObj{"type": "object", "properties": props}, oaObj{"type": "array", "items": ref}
+	case "get_by_id":
+		return oaObj{"type": "object", "properties": oaObj{"id": id}, "required": []string{"id"}}, ref
+	case "update", "create":
+		props := oaObj{}
+		required := []string{}
+		for _, f := range d.Flds {
+			if s := oaFldSchema(f); s != nil {
+				props[f.Name] = s
+				if f.Sql.IsRequired {
+					required = append(required, f.Name)
+				}
+			}
+		}
+		params := oaObj{"type": "object", "properties": props}
+		if kind == "update" {
+			props["id"] = oaObj{"type": "integer", "description": "-1 - создание новой записи"}
+			params["required"] = []string{"id"}
+			// обязательные поля проверяются только при создании записи
+			if len(required) > 0 {
+				params["description"] = "при создании обязательны: " + strings.Join(required, ", ")
+			}
+		} else if len(required) > 0 {
+			params["required"] = required
+		}
+		return params, ref
+	case "action":
+		actions := []string{}
+		props := oaObj{"id": id}
+		if d.StateMachine != nil {
+			for _, st := range d.StateMachine.States {
+				for _, actn := range st.Actions {
+					actions = append(actions, fmt.Sprintf("%s_to_%s", st.Title, actn.To))
+					for _, f := range actn.UpdateFlds {
+						if s := oaFldSchema(f); s != nil && f.Name != "state" {
+							props[f.Name] = s
+						}
+					}
+				}
+			}
+		}
+		props["action_name"] = oaObj{"type": "string", "enum": actions}
+		return oaObj{"type": "object", "properties": props, "required": []string{"id", "action_name"}}, ref
+	case "history_list":
+		params := oaObj{"type": "object", "properties": oaObj{
+			"id":       oaObj{"type": "integer", "description": "id записи " + d.PgName()},
+			"page":     oaObj{"type": "integer", "default": 1},
+			"per_page": oaObj{"type": "integer", "default": 100},
+		}, "required": []string{"id"}}
+		item := oaObj{"type": "object", "properties": oaObj{
+			"id":            id,
+			"operation":     oaObj{"type": "string", "enum": []string{"INSERT", "UPDATE", "DELETE"}},
+			"old_values":    oaObj{"type": "object", "nullable": true, "description": "прежние значения измененных полей"},
+			"new_values":    oaObj{"type": "object", "nullable": true, "description": "новые значения измененных полей"},
+			"created_at":    oaObj{"type": "string", "format": "date-time"},
+			"user_id":       oaObj{"type": "integer", "nullable": true},
+			"user_fullname": oaObj{"type": "string", "nullable": true},
+		}}
+		return params, oaObj{"type": "array", "items": item}
+	}
+	return oaObj{"type": "object"}, oaObj{}
+}
+
+// схема записи документа
+func oaDocSchema(d types.DocType) oaObj {
+	props := oaObj{
+		"id":         oaObj{"type": "integer"},
+		"created_at": oaObj{"type": "string", "format": "date-time"},
+		"updated_at": oaObj{"type": "string", "format": "date-time"},
+		"deleted":    oaObj{"type": "boolean"},
+	}
+	for _, f := range d.Flds {
+		if s := oaFldSchema(f); s != nil {
+			props[f.Name] = s
+			if len(f.Sql.Ref) > 0 {
+				props[f.RefTitleName()] = oaObj{"type": "string", "description": "название записи из " + f.Sql.Ref}
+			}
+		}
+	}
+	if d.Sql.IsFullTextSearch {
+		props["meta_info"] = oaObj{"type": "object", "description": "в list при полнотекстовом поиске по search_text", "properties": oaObj{
+			"rank":    oaObj{"type": "number"},
+			"snippet": oaObj{"type": "string", "description": "фрагмент текста с выделенными найденными словами"},
+		}}
+	}
+	return oaObj{"type": "object", "title": d.NameRu, "properties": props}
+}
+
+// схема параметра фильтра списка (см DocType.ListFilterParams)
+func oaFilterParamSchema(fp types.DocListFilterParam) oaObj {
+	s := oaFldSchema(fp.Fld)
+	if s == nil {
+		s = oaObj{}
+	}
+	switch fp.Op {
+	case "between":
+		return oaObj{"type": "array", "items": s, "minItems": 2, "maxItems": 2, "description": fp.Fld.NameRu + ": [с, по], любая из границ может быть null"}
+	case "dateBetween":
+		return oaObj{"type": "array", "items": s, "minItems": 2, "maxItems": 2, "description": fp.Fld.NameRu + ": [с, по], любая из границ может быть null. Дата \"по\" включается целиком"}
+	case "gte":
+		s["description"] = fp.Fld.NameRu + ": больше или равно"
+	case "lte":
+		s["description"] = fp.Fld.NameRu + ": меньше или равно"
+	case "in":
+		return oaObj{"type": "array", "items": s, "description": fp.Fld.NameRu + ": одно из значений"}
+	case "notIn":
+		return oaObj{"type": "array", "items": s, "description": fp.Fld.NameRu + ": кроме значений"}
+	case "isNull":
+		return oaObj{"type": "boolean", "description": fp.Fld.NameRu + ": true - пустое, false - заполненное"}
+	case "arrayContains":
+		return oaObj{"type": "array", "items": oaObj{"type": "string"}, "description": fp.Fld.NameRu + ": содержит все значения"}
+	}
+	return s
+}
+
+// схема поля документа. nil - поле не хранится в таблице
+func oaFldSchema(f types.FldType) oaObj {
+	if len(f.Name) == 0 || f.Type == types.FldTypeVueComposition {
+		return nil
+	}
+	var res oaObj
+	switch f.Type {
+	case types.FldTypeString:
+		res = oaObj{"type": "string"}
+		if f.Sql.Size > 0 {
+			res["maxLength"] = f.Sql.Size
+		}
+	case types.FldTypeText:
+		res = oaObj{"type": "string"}
+	case types.FldTypeInt:
+		res = oaObj{"type": "integer", "format": "int32"}
+	case types.FldTypeInt64:
+		res = oaObj{"type": "integer", "format": "int64"}
+	case types.FldTypeDouble:
+		res = oaObj{"type": "number", "format": "double"}
+	case types.FldTypeDate:
+		res = oaObj{"type": "string", "format": "date"}
+	case types.FldTypeDatetime:
+		res = oaObj{"type": "string", "format": "date-time"}
+	case types.FldTypeBool:
+		res = oaObj{"type": "boolean"}
+	case types.FldTypeUuid:
+		res = oaObj{"type": "string", "format": "uuid"}
+	case types.FldTypeTextArray:
+		res = oaObj{"type": "array", "items": oaObj{"type": "string"}}
+	case types.FldTypeIntArray:
+		res = oaObj{"type": "array", "items": oaObj{"type": "integer"}}
+	case types.FldTypeDoubleArray:
+		res = oaObj{"type": "array", "items": oaObj{"type": "number"}}
+	default:
+		// jsonb - произвольное значение
+		res = oaObj{}
+	}
+	description := f.NameRu
+	if len(f.Sql.Ref) > 0 {
+		description = strings.TrimSpace(fmt.Sprintf("%s (id из %s)", f.NameRu, f.Sql.Ref))
+	}
+	if len(description) > 0 {
+		res["description"] = description
+	}
+	if len(f.Vue.Options) > 0 && f.Type != types.FldTypeTextArray {
+		enum := []interface{}{}
+		for _, o := range f.Vue.Options {
+			enum = append(enum, o.Value)
+		}
+		res["enum"] = enum
+	}
+	if len(f.Sql.Default) > 0 && f.Type == types.FldTypeString {
+		res["default"] = strings.Trim(f.Sql.Default, "'")
+	}
+	return res
+}
+
+type oaAuthEndpoint struct {
+	name    string
+	summary string
+	params  oaObj
+}
+
+// эндпоинты /auth/* из webServer/main.go. Вызываются без токена
+func oaAuthEndpoints(p types.ProjectType) []oaAuthEndpoint {
+	str := oaObj{"type": "string"}
+	obj := func(required []string, props ...string) oaObj {
+		res := oaObj{}
+		for _, v := range props {
+			res[v] = str
+			if strings.HasPrefix(v, "is_") {
+				res[v] = oaObj{"type": "boolean"}
+			}
+		}
+		if _, ok := res["options"]; ok {
+			res["options"] = oaObj{"type": "object"}
+		}
+		return oaObj{"type": "object", "properties": res, "required": required}
+	}
+	res := []oaAuthEndpoint{
+		{"email", "вход или регистрация по email", obj([]string{"login", "password"}, "login", "password", "last_name", "first_name", "is_register")},
+		{"check_user_email", "подтверждение email по токену из письма", obj([]string{"token"}, "token")},
+		{"email_auth_start_recover_password", "отправка письма для восстановления пароля", obj([]string{"email"}, "email")},
+		{"email_auth_recover_password", "установка нового пароля по токену из письма", obj([]string{"token"}, "password", "token", "is_token_check")},
+	}
+	if p.Config.Auth.ByPhone {
+		res = append(res,
+			oaAuthEndpoint{"phone", "вход или регистрация по номеру телефона", obj([]string{"login", "password"}, "login", "password", "last_name", "first_name", "options", "is_register")},
+			oaAuthEndpoint{"check_sms_code", "проверка кода из смс", obj([]string{"phone", "token"}, "phone", "token")},
+			oaAuthEndpoint{"phone_auth_start_recover_password", "отправка смс для восстановления пароля", obj([]string{"phone"}, "phone", "token")},
+			oaAuthEndpoint{"phone_auth_recover_password", "установка нового пароля по коду из смс", obj([]string{"phone", "token", "password"}, "phone", "token", "password")},
+		)
+	}
+	return res
+}
@@ -0,0 +1,119 @@
+package tmplGenerateStep2
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/NL-A/nla_framework/types"
+	"github.com/NL-A/nla_framework/utils"
+)
+
+// максимальная длина условия в подписи перехода. Полный текст условия - в action.sql
+const smConditionMaxLen = 80
+
+// диаграммы состояний в docs/sm/<doc>.mmd и <doc>.dot для документов с машиной состояний
+func SmDiagram(p types.ProjectType) {
+	distPath := p.DistPath + "/docs/sm"
+	for _, d := range p.Docs {
+		if d.StateMachine == nil || len(d.StateMachine.States) == 0 {
+			continue
+		}
+		err := utils.WriteFile(fmt.Sprintf("%s/%s.mmd", distPath, d.Name), []byte(printSmMermaid(*d.StateMachine)))
+		utils.CheckErr(err, "SmDiagram WriteFile")
+		err = utils.WriteFile(fmt.Sprintf("%s/%s.dot", distPath, d.Name), []byte(printSmDot(d.Name, *d.StateMachine)))
+		utils.CheckErr(err, "SmDiagram WriteFile")
+	}
+}
+
+func printSmMermaid(sm types.DocSm) string {
+	res := []string{"stateDiagram-v2"}
+	for _, st := range sm.States {
+		res = append(res, fmt.Sprintf("    state \"%s\" as %s", smMermaidEscape(smStateLabel(*st)), st.Title))
+	}
+	res = append(res, fmt.Sprintf("    [*] --> %s", sm.GetFirstState().Title))
+	for _, st := range sm.States {
+		for _, actn := range st.Actions {
+			label := smActionLabel(actn)
+			for i := range label {
+				label[i] = smMermaidEscape(label[i])
+			}
+			res = append(res, fmt.Sprintf("    %s --> %s : %s", st.ActionFrom(actn), actn.To, strings.Join(label, "<br/>")))
+		}
+	}
+	final := sm.FinalStates()
+	for _, st := range sm.States {
+		if final[st.Title] {
+			res = append(res, fmt.Sprintf("    %s --> [*]", st.Title))
+		}
+	}
+	return strings.Join(res, "\n") + "\n"
+}
+
+func printSmDot(name string, sm types.DocSm) string {
+	res := []string{
+		fmt.Sprintf("digraph %s {", strconv.Quote(name)),
+		"    graph [rankdir=LR];",
+		"    node [shape=box, style=rounded, fontname=\"Helvetica\", fontsize=10];",
+		"    edge [fontname=\"Helvetica\", fontsize=9];",
+		"    \"__start\" [shape=point, width=0.15];",
+	}
+	final := sm.FinalStates()
+	for _, st := range sm.States {
+		// конечное состояние - двойной рамкой
+		ext := ""
+		if final[st.Title] {
+			ext = ", peripheries=2"
+		}
+		res = append(res, fmt.Sprintf("    %s [label=%s%s];", strconv.Quote(st.Title), strconv.Quote(smStateLabel(*st)), ext))
+	}
+	res = append(res, fmt.Sprintf("    \"__start\" -> %s;", strconv.Quote(sm.GetFirstState().Title)))
+	for _, st := range sm.States {
+		for _, actn := range st.Actions {
+			res = append(res, fmt.Sprintf("    %s -> %s [label=%s];", strconv.Quote(st.ActionFrom(actn)), strconv.Quote(actn.To), strconv.Quote(strings.Join(smActionLabel(actn), "\n"))))
+		}
+	}
+	res = append(res, "}")
+	return strings.Join(res, "\n") + "\n"
+}
+
+func smStateLabel(st types.DocSmState) string {
+	if len(st.TitleRu) == 0 {
+		return st.Title
+	}
+	return fmt.Sprintf("%s (%s)", st.TitleRu, st.Title)
+}
+
+// подпись перехода: название, условия и поля, которые заполняются при переходе
+func smActionLabel(actn types.DocSmAction) []string {
+	res := []string{actn.Label}
+	for _, cond := range actn.Conditions {
+		if len(cond.SqlText) > 0 {
+			res = append(res, "если: "+smShorten(cond.SqlText))
+		}
+	}
+	flds := []string{}
+	for _, f := range actn.UpdateFlds {
+		if f.Name != "state" {
+			flds = append(flds, f.Name)
+		}
+	}
+	if len(flds) > 0 {
+		res = append(res, "поля: "+strings.Join(flds, ", "))
+	}
+	return res
+}
+
+// sql условия многострочные - в подпись выводим одной строкой
+func smShorten(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > smConditionMaxLen {
+		s = string(r[:smConditionMaxLen]) + "…"
+	}
+	return s
+}
+
+// символы, которые mermaid воспринимает как разметку, заменяем на коды
+func smMermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", ";", "#59;", ":", "#58;", "{", "#123;", "}", "#125;", "<", "#lt;", ">", "#gt;").Replace(s)
+}
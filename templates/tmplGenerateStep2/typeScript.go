@@ -0,0 +1,275 @@
+package tmplGenerateStep2
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/NL-A/nla_framework/types"
+	"github.com/NL-A/nla_framework/utils"
+	"github.com/iancoleman/strcase"
+	"github.com/serenize/snaker"
+)
+
+// типы документов и типизированный клиент для /api/call_pg_func:
+//   - webClient/src/app/api/types.d.ts - интерфейс записи каждого документа, union типы для полей с Options, параметры методов
+//   - webClient/src/app/api/client.ts - функция-обертка для каждого метода из pgFuncList: clientList(params): Promise<Client[]>
+//
+// Из js файлов типы доступны через jsdoc: /** @type {import('src/app/api/types').Client} */
+func TypeScriptApi(p types.ProjectType) {
+	distPath := p.DistPath + "/webClient/src/app/api"
+	err := utils.WriteFile(distPath+"/types.d.ts", []byte(printTsTypes(p)))
+	utils.CheckErr(err, "TypeScriptApi WriteFile")
+	err = utils.WriteFile(distPath+"/client.ts", []byte(printTsClient(p)))
+	utils.CheckErr(err, "TypeScriptApi WriteFile")
+}
+
+func printTsTypes(p types.ProjectType) string {
+	res := []string{
+		"// сгенерировано nla_framework по описанию документов. Не редактировать",
+		"",
+		"// общие параметры методов *_list",
+		"export interface ListParams {",
+		"  deleted?: boolean",
+		"  order_by?: string",
+		"  page?: number",
+		"  per_page?: number",
+		"  search_text?: string",
+		"  with_total?: boolean // общее количество записей по фильтру в meta_info.total",
+		"  cursor?: ListCursor | null // курсорная пагинация: null - первая страница, далее meta_info.next_cursor",
+		"  is_default_order?: boolean // order_by не выбран пользователем: при search_text сортировка по релевантности",
+		"}",
+		"",
+		"// позиция последней записи страницы для курсорной пагинации",
+		"export interface ListCursor {",
+		"  value: any",
+		"  id: number",
+		"}",
+		"",
+		"// meta_info ответа методов *_list",
+		"export interface ListMetaInfo {",
+		"  total?: number",
+		"  next_cursor?: ListCursor | null // null - записей больше нет",
+		"}",
+	}
+	for _, d := range p.Docs {
+		if d.Sql.IsHistory {
+			res = append(res,
+				"",
+				"// запись истории изменений документа (методы *_history_list)",
+				"export interface DocHistoryItem {",
+				"  id: number",
+				"  operation: 'INSERT' | 'UPDATE' | 'DELETE'",
+				"  old_values: Record<string, any> | null // прежние значения измененных полей",
+				"  new_values: Record<string, any> | null // новые значения измененных полей",
+				"  created_at: string",
+				"  user_id: number | null",
+				"  user_fullname: string | null",
+				"}",
+			)
+			break
+		}
+	}
+	for _, d := range p.Docs {
+		name := d.NameCamelCase()
+		// union типы для полей с фиксированным списком значений
+		for _, f := range d.Flds {
+			if u := tsOptionsUnion(f); len(u) > 0 {
+				res = append(res, "", fmt.Sprintf("export type %s%s = %s", name, snaker.SnakeToCamel(f.Name), u))
+			}
+		}
+
+		res = append(res, "", fmt.Sprintf("// %s", d.NameRu), fmt.Sprintf("export interface %s {", name), "  id: number")
+		isOptions := false
+		for _, f := range d.Flds {
+			t := tsFldType(d, f)
+			if len(t) == 0 {
+				continue
+			}
+			if f.Name == "options" {
+				isOptions = true
+			}
+			optional := "?"
+			if f.Sql.IsRequired {
+				optional = ""
+			}
+			res = append(res, tsFldLine(f.Name+optional, t, f.NameRu))
+			// название записи из ref таблицы добавляется в list и get_by_id (см FldType.RefTitleName).
+			// В list документа с IsSearchText для полей IsSearch название только в options.title
+			if len(f.Sql.Ref) > 0 {
+				res = append(res, tsFldLine(f.RefTitleName()+"?", "string", ""))
+			}
+		}
+		if !isOptions {
+			res = append(res, "  options?: Record<string, any>")
+		}
+		if d.Sql.IsFullTextSearch {
+			res = append(res, "  meta_info?: {rank: number, snippet: string} // в list при полнотекстовом поиске по search_text")
+		}
+		res = append(res, "  created_at: string", "  updated_at: string", "  deleted: boolean", "}")
+
+		// параметры стандартных методов
+		res = append(res, "", fmt.Sprintf("export interface %sListParams extends ListParams {", name))
+		for _, f := range d.Flds {
+			if f.Name != "title" && (len(f.Sql.Ref) > 0 || f.Sql.IsSearch) {
+				res = append(res, tsFldLine(f.Name+"?", tsFldType(d, f), ""))
+			}
+		}
+		for _, fp := range d.ListFilterParams() {
+			res = append(res, tsFldLine(fp.Param+"?", tsFilterParamType(d, fp), ""))
+		}
+		res = append(res, "}", "", fmt.Sprintf("export interface %sUpdateParams {", name), "  id: number // -1 - создание новой записи")
+		for _, f := range d.Flds {
+			if t := tsFldType(d, f); len(t) > 0 {
+				res = append(res, tsFldLine(f.Name+"?", t, ""))
+			}
+		}
+		res = append(res, "}")
+		if d.IsStateMachine() {
+			actions := []string{}
+			fldNames := []string{}
+			fldLines := []string{}
+			for _, st := range d.StateMachine.States {
+				for _, actn := range st.Actions {
+					actions = append(actions, fmt.Sprintf("'%s_to_%s'", st.Title, actn.To))
+					for _, f := range actn.UpdateFlds {
+						if t := tsFldType(d, f); len(t) > 0 && f.Name != "state" && !utils.CheckContainsSliceStr(f.Name, fldNames...) {
+							fldNames = append(fldNames, f.Name)
+							fldLines = append(fldLines, tsFldLine(f.Name+"?", t, ""))
+						}
+					}
+				}
+			}
+			actionName := "string"
+			if len(actions) > 0 {
+				actionName = strings.Join(actions, " | ")
+			}
+			res = append(res, "", fmt.Sprintf("export interface %sActionParams {", name), "  id: number", "  action_name: "+actionName)
+			res = append(res, fldLines...)
+			res = append(res, "}")
+		}
+	}
+	return strings.Join(res, "\n") + "\n"
+}
+
+func printTsClient(p types.ProjectType) string {
+	res := []string{
+		"// сгенерировано nla_framework по списку методов pgFuncList (webServer/apiCallPgFunc.go). Не редактировать",
+		"import config from 'src/app/plugins/config'",
+		"import type * as t from './types'",
+		"",
+		"// вызов метода через /api/call_pg_func. При ok = false - reject с текстом ошибки",
+		"export function callPgMethod<T = any>(method: string, params: Record<string, any> = {}): Promise<T> {",
+		"  const headers: Record<string, string> = {'Content-Type': 'application/json'}",
+		"  const authToken = localStorage.getItem(config.appName)",
+		"  if (authToken) headers['Auth-token'] = authToken",
+		"  return fetch(`${config.apiUrl()}/api/call_pg_func`, {method: 'POST', headers, body: JSON.stringify({method, params})})",
+		"    .then(res => res.json())",
+		"    .then(res => res.ok ? res.result as T : Promise.reject(new Error(res.message)))",
+		"}",
+		"",
+		"// вызов метода *_list вместе с meta_info (with_total, cursor)",
+		"export function callPgListMethod<T = any>(method: string, params: t.ListParams = {}): Promise<{result: T[], meta_info: t.ListMetaInfo}> {",
+		"  const headers: Record<string, string> = {'Content-Type': 'application/json'}",
+		"  const authToken = localStorage.getItem(config.appName)",
+		"  if (authToken) headers['Auth-token'] = authToken",
+		"  return fetch(`${config.apiUrl()}/api/call_pg_func`, {method: 'POST', headers, body: JSON.stringify({method, params})})",
+		"    .then(res => res.json())",
+		"    .then(res => res.ok ? {result: res.result || [], meta_info: res.meta_info || {}} : Promise.reject(new Error(res.message)))",
+		"}",
+	}
+	names := append([]string{}, oaUserMethods...)
+	for _, m := range p.ApiCallPgFuncMethods() {
+		if !utils.CheckContainsSliceStr(m.Name, names...) {
+			names = append(names, m.Name)
+		}
+	}
+	for _, name := range names {
+		params, result := "Record<string, any> = {}", "any"
+		if d, kind := docForApiMethod(p, name); d != nil {
+			docName := "t." + d.NameCamelCase()
+			switch kind {
+			case "list":
+				params, result = docName+"ListParams = {}", docName+"[]"
+			case "get_by_id":
+				params, result = "{id: number}", docName
+			case "update", "create":
+				params, result = docName+"UpdateParams", docName
+			case "action":
+				params, result = docName+"ActionParams", docName
+			case "history_list":
+				params, result = "{id: number, page?: number, per_page?: number}", "t.DocHistoryItem[]"
+			}
+		}
+		res = append(res, "", fmt.Sprintf("export const %s = (params: %s): Promise<%s> => callPgMethod('%s', params)", strcase.ToLowerCamel(name), params, result, name))
+	}
+	return strings.Join(res, "\n") + "\n"
+}
+
+func tsFldLine(name, t, comment string) string {
+	if len(comment) > 0 {
+		return fmt.Sprintf("  %s: %s // %s", name, t, comment)
+	}
+	return fmt.Sprintf("  %s: %s", name, t)
+}
+
+// тип поля документа. Пустая строка - поле не хранится в таблице
+func tsFldType(d types.DocType, f types.FldType) string {
+	if len(f.Name) == 0 || f.Type == types.FldTypeVueComposition {
+		return ""
+	}
+	if len(tsOptionsUnion(f)) > 0 {
+		name := d.NameCamelCase() + snaker.SnakeToCamel(f.Name)
+		if f.Type == types.FldTypeTextArray {
+			return name + "[]"
+		}
+		return name
+	}
+	switch f.Type {
+	case types.FldTypeString, types.FldTypeText, types.FldTypeDate, types.FldTypeDatetime, types.FldTypeUuid:
+		return "string"
+	case types.FldTypeInt, types.FldTypeInt64, types.FldTypeDouble:
+		return "number"
+	case types.FldTypeBool:
+		return "boolean"
+	case types.FldTypeTextArray:
+		return "string[]"
+	case types.FldTypeIntArray, types.FldTypeDoubleArray:
+		return "number[]"
+	}
+	// jsonb
+	return "any"
+}
+
+// тип параметра фильтра списка (см DocType.ListFilterParams)
+func tsFilterParamType(d types.DocType, fp types.DocListFilterParam) string {
+	t := tsFldType(d, fp.Fld)
+	if len(t) == 0 {
+		t = "any"
+	}
+	switch fp.Op {
+	case "between", "dateBetween":
+		return fmt.Sprintf("[%[1]s | null, %[1]s | null]", t)
+	case "in", "notIn":
+		return t + "[]"
+	case "isNull":
+		return "boolean"
+	case "arrayContains":
+		return "string | string[]"
+	}
+	return t
+}
+
+// union из значений Options для select, radio и multipleSelect
+func tsOptionsUnion(f types.FldType) string {
+	if len(f.Vue.Options) == 0 || !utils.CheckContainsSliceStr(f.Vue.Type, types.FldVueTypeSelect, types.FldVueTypeRadio, types.FldVueTypeMultipleSelect) {
+		return ""
+	}
+	arr := []string{}
+	for _, o := range f.Vue.Options {
+		v, err := json.Marshal(o.Value)
+		utils.CheckErr(err, "tsOptionsUnion")
+		arr = append(arr, strings.ReplaceAll(string(v), `"`, "'"))
+	}
+	return strings.Join(arr, " | ")
+}
@@ -22,6 +22,9 @@ func (d DocType) PrintSqlModelFlds() (res string) {
 	if d.Sql.IsSearchText {
 		arr = append(arr, "\t{name=\"search_text\",\t\t\ttype=\"text\",\tcomment=\"колонка для поиска\"}")
 	}
+	if d.Sql.IsFullTextSearch {
+		arr = append(arr, "\t{name=\"search_vector\",\t\t\ttype=\"tsvector\",\tcomment=\"колонка для полнотекстового поиска\"}")
+	}
 	arr = append(arr, "\t{name=\"options\",\t\t\t\ttype=\"jsonb\",\tcomment=\"разные дополнительные параметры\"}")
 	arr = append(arr, "\t{name=\"created_at\",\t\t\t\ttype=\"timestamp\",\text=\"with time zone\"}")
 	arr = append(arr, "\t{name=\"updated_at\",\t\t\t\ttype=\"timestamp\",\text=\"with time zone\"}")
@@ -93,6 +96,9 @@ func (d DocType) PrintSqlModelTriggers() (res string) {
 	if d.Sql.IsNotifyEventTrigger {
 		arr = append(arr, fmt.Sprintf("\t{name=\"%s_event\", when=\"after insert or update\", ref=\"for each row\", funcName=\"notify_event\"}", d.Name))
 	}
+	if d.Sql.IsHistory {
+		arr = append(arr, fmt.Sprintf("\t{name=\"%s_history\", when=\"after insert or update or delete\", ref=\"for each row\", funcName=\"doc_history\"}", d.Name))
+	}
 	for _, trigger := range d.Sql.CustomTriggers {
 		arr = append(arr, "\t"+trigger)
 	}
@@ -130,6 +136,9 @@ func (d DocType) PrintSqlModelMethods() (res string) {
 	if d.Sql.IsAfterTrigger {
 		arr = append(arr, fmt.Sprintf("\t\"%s_trigger_after\"", d.Name))
 	}
+	if len(d.Sql.AccessRules) > 0 {
+		arr = append(arr, fmt.Sprintf("\t\"%s_access\"", d.PgName()))
+	}
 
 	if len(arr) > 0 {
 		sort.Strings(arr)
@@ -172,6 +181,12 @@ func (d DocType) PrintSqlModelAlterScripts() (res string) {
 	if d.Sql.IsSearchText {
 		arr = append(arr, fmt.Sprintf("\t\"alter table %s add column if not exists search_text text;\"", d.PgName()))
 	}
+	if d.Sql.IsFullTextSearch {
+		arr = append(arr, fmt.Sprintf("\t\"alter table %s add column if not exists search_vector tsvector;\"", d.PgName()))
+		arr = append(arr, fmt.Sprintf("\t\"create index if not exists %[1]s_search_vector_idx on %[1]s using gin (search_vector);\"", d.PgName()))
+		// существующие записи заполняются один раз: у новых и измененных search_vector заполняет before триггер
+		arr = append(arr, strings.ReplaceAll(fmt.Sprintf("\t\"update %s doc set search_vector = %s where search_vector is null;\"", d.PgName(), d.searchVectorBackfillExpr()), `"user"`, `\"user\"`))
+	}
 
 	if len(arr) > 0 {
 		res = fmt.Sprintf("alterScripts = [\n%s\n]", strings.Join(arr, ",\n"))
@@ -190,27 +205,230 @@ func (d DocType) PrintSqlFuncGetById() (res string) {
 			if refTable == "user" {
 				refTable = `"user"`
 			}
-			// формируем имя для title. Нужно для тех случаев когда имя столба отличается от имени таблицы, на которую идет ссылка.
-			// например, from_location_id как ссылка на таблицу location. Тогда формируем поле from_location_title
-			fldNameWithTitle := strings.TrimSuffix(f.Name, "_id") + "_title"
-			arr = append(arr, fmt.Sprintf("\t\tt%v as (select t%[2]v.*, c.title as %[6]s from t%[2]v left join %[4]s c on c.id = t%[2]v.%[5]s)", cnt, cnt-1, f.Sql.Ref, refTable, f.Name, fldNameWithTitle))
+			// имя для title формируется из имени поля, а не таблицы (см RefTitleName), чтобы ссылки на одну таблицу не совпадали
+			arr = append(arr, fmt.Sprintf("\t\tt%v as (select t%[2]v.*, c.title as %[5]s from t%[2]v left join %[3]s c on c.id = t%[2]v.%[4]s)", cnt, cnt-1, refTable, f.Name, f.RefTitleName()))
 		}
 	}
-	res = fmt.Sprintf("%s\n \tselect row_to_json(t%v.*)::jsonb into result from t%v;", strings.Join(arr, ",\n"), cnt, cnt)
+	// служебная колонка search_vector клиенту не отдается
+	exclude := ""
+	if d.Sql.IsFullTextSearch {
+		exclude = " - 'search_vector'"
+	}
+	res = fmt.Sprintf("%s\n \tselect row_to_json(t%v.*)::jsonb%s into result from t%[2]v;", strings.Join(arr, ",\n"), cnt, exclude)
 	return
 }
 
-func (d DocType) PrintSqlFuncListRoleConditions() string {
-	res := ""
-	res = fmt.Sprintf(`if is_user_role((params->>'user_id')::int, '{"admin"}') is not true then
-        params = params || jsonb_build_object('manager_id', params->>'user_id');
-    end if;`)
+// есть ли в документе поля с доступом по ролям (см FldRoles). mode - read (видимость) или write (изменение)
+func (d DocType) IsFldRoles(mode string) bool {
+	return len(d.sqlFldRoles(mode)) > 0
+}
+
+// jsonb для функции user_denied_flds: поле - роли, которым оно доступно
+func (d DocType) PrintSqlFldRolesJson(mode string) string {
+	return fmt.Sprintf("'{%s}'::jsonb", strings.Join(d.sqlFldRoles(mode), ", "))
+}
+
+// jsonb для функции user_denied_flds по одному полю. Пустая строка, если у поля нет ролей
+func (d DocType) PrintSqlFldRolesJsonByName(mode, fldName string) string {
+	arr := d.sqlFldRoles(mode, fldName)
+	if len(arr) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("'{%s}'::jsonb", strings.Join(arr, ", "))
+}
+
+// jsonb для функции jsonb_hide_flds: поле, скрытое по ролям - ключи записи, которые вычисляются из него.
+// title ссылки, значения в options.title и колонка search_text (DocSql.IsSearchText)
+func (d DocType) PrintSqlFldHiddenKeysJson() string {
+	arr := []string{}
+	for _, fld := range d.sqlReadRoleFlds() {
+		keys := []string{}
+		if len(fld.Sql.Ref) > 0 {
+			keys = append(keys, fld.RefTitleName())
+		}
+		if d.Sql.IsSearchText && fld.Sql.IsSearch {
+			// см GetSearchTextJson
+			if len(fld.Sql.Ref) == 0 {
+				keys = append(keys, "options.title."+fld.Name)
+			} else {
+				fldName := strings.TrimSuffix(fld.Name, "_id")
+				keys = append(keys, fmt.Sprintf("options.title.%s_title", fldName))
+				if fld.Sql.Ref == "user" {
+					keys = append(keys, fmt.Sprintf("options.title.%s_avatar", fldName))
+				}
+				for _, v := range fld.Sql.RefFldsForOptions {
+					keys = append(keys, fmt.Sprintf("options.title.%s_%s", fldName, v))
+				}
+			}
+			keys = append(keys, "search_text")
+		}
+		if len(keys) > 0 {
+			arr = append(arr, fmt.Sprintf(`"%s": ["%s"]`, fld.Name, strings.Join(keys, `", "`)))
+		}
+	}
+	return fmt.Sprintf("'{%s}'::jsonb", strings.Join(arr, ", "))
+}
 
+// jsonb для функции check_denied_params: поле, скрытое по ролям - параметры list, которые по нему фильтруют.
+// Фильтры (см PrintSqlFuncListWhereCond) и search_text, если поле участвует в поиске
+func (d DocType) PrintSqlFldDeniedParamsJson() string {
+	filterParams := d.ListFilterParams()
+	arr := []string{}
+	for _, fld := range d.sqlReadRoleFlds() {
+		params := []string{}
+		if fld.Name != "title" && (len(fld.Sql.Ref) > 0 || fld.Sql.IsSearch) {
+			params = append(params, fld.Name)
+		}
+		for _, fp := range filterParams {
+			if fp.Fld.Name == fld.Name {
+				params = append(params, fp.Param)
+			}
+		}
+		// тэги попадают в search_vector (см searchVectorParts)
+		if fld.Sql.IsSearch || (d.Sql.IsFullTextSearch && fld.Vue.Type == FldVueTypeTags) {
+			params = append(params, "search_text")
+		}
+		if len(params) > 0 {
+			arr = append(arr, fmt.Sprintf(`"%s": ["%s"]`, fld.Name, strings.Join(params, `", "`)))
+		}
+	}
+	return fmt.Sprintf("'{%s}'::jsonb", strings.Join(arr, ", "))
+}
+
+// поля, видимые только по ролям (см sqlFldRoles)
+func (d DocType) sqlReadRoleFlds() []FldType {
+	res := []FldType{}
+	for _, fld := range d.Flds {
+		if len(fld.Name) > 0 && !fld.Sql.IsOptionFld && len(fld.ReadRoles()) > 0 {
+			res = append(res, fld)
+		}
+	}
 	return res
 }
 
+// fldNames - только указанные поля. Если не указаны, то все поля документа
+func (d DocType) sqlFldRoles(mode string, fldNames ...string) []string {
+	arr := []string{}
+	for _, fld := range d.Flds {
+		// поля из options отдельно не фильтруются
+		if len(fld.Name) == 0 || fld.Sql.IsOptionFld {
+			continue
+		}
+		if len(fldNames) > 0 && !utils.CheckContainsSliceStr(fld.Name, fldNames...) {
+			continue
+		}
+		roles := fld.ReadRoles()
+		if mode == "write" {
+			roles = fld.WriteRoles()
+		}
+		if len(roles) > 0 {
+			arr = append(arr, fmt.Sprintf(`"%s": ["%s"]`, fld.Name, strings.Join(roles, `", "`)))
+		}
+	}
+	return arr
+}
+
+// access.sql условия доступа по ролям из DocSql.AccessRules. Роли объединяются через OR
+func (d DocType) PrintSqlFuncAccessCond() string {
+	res := []string{}
+	for _, role := range utils.SortedKeys(d.Sql.AccessRules) {
+		rule := d.Sql.AccessRules[role]
+		conds := []string{fmt.Sprintf("'%s' = ANY (u.role)", role)}
+		if len(rule.OwnerFld) > 0 {
+			conds = append(conds, fmt.Sprintf("doc.%s = userId", rule.OwnerFld))
+		}
+		if dep := rule.Department; dep != nil {
+			linkFld, userFld := dep.LinkFld, dep.UserFld
+			if len(linkFld) == 0 {
+				linkFld = dep.Fld
+			}
+			if len(userFld) == 0 {
+				userFld = "user_id"
+			}
+			conds = append(conds, fmt.Sprintf("doc.%s IN (SELECT l.%s FROM %s l WHERE l.%s = userId)", dep.Fld, linkFld, dep.LinkTable, userFld))
+		}
+		if len(rule.States) > 0 {
+			conds = append(conds, fmt.Sprintf("doc.state IN ('%s')", strings.Join(rule.States, "', '")))
+		}
+		if len(rule.Sql) > 0 {
+			conds = append(conds, fmt.Sprintf("(%s)", rule.Sql))
+		}
+		res = append(res, fmt.Sprintf("        OR (%s)", strings.Join(conds, " AND ")))
+	}
+	return strings.Join(res, "\n")
+}
+
+// проверка, что существующая запись доступна пользователю (DocSql.AccessRules). Недоступная запись выглядит как ненайденная
+func (d DocType) PrintSqlFuncAccessCheck(idExpr, msg string) string {
+	return fmt.Sprintf(`-- записи, недоступные пользователю по DocSql.AccessRules, считаются ненайденными
+    IF NOT exists(SELECT 1 FROM %[1]s doc WHERE doc.id = %[2]s AND %[1]s_access((params ->> 'user_id')::int, doc))
+    THEN
+        RETURN json_build_object('ok', FALSE, 'message', '%[3]s');
+    END IF;`, d.PgName(), idExpr, msg)
+}
+
+// проверка доступа к записи перед update. У документов с интеграцией запись ищется не по id, а по внешнему ключу
+func (d DocType) PrintSqlFuncUpdateAccessCheck() string {
+	idExpr := "(params ->> 'id')::int"
+	if d.IsBitrixIntegration() || d.IsOdataIntegration() {
+		idExpr = d.Name + "Row.id"
+	}
+	// проверка внутри ветки else, поэтому с дополнительным отступом
+	return strings.ReplaceAll(d.PrintSqlFuncAccessCheck(idExpr, "wrong id"), "\n    ", "\n        ")
+}
+
+// после изменения запись должна остаться доступной пользователю. Исключение перехватывает PrintSqlFuncAccessDeniedHandler:
+// изменения откатываются, а клиент получает обычный ответ с ошибкой. При создании не проверяется - у новой записи
+// еще может не быть связей (например, в таблице подразделений) и не заполнено поле владельца
+func (d DocType) PrintSqlFuncAccessCheckRow(rowVar string) string {
+	return fmt.Sprintf(`-- запись должна остаться доступной пользователю по DocSql.AccessRules (например, нельзя передать ее другому менеджеру)
+    IF NOT %[1]s_access((params ->> 'user_id')::int, %[2]s)
+    THEN
+        RAISE EXCEPTION USING ERRCODE = '%[3]s', MESSAGE = 'access denied';
+    END IF;`, d.PgName(), rowVar, sqlAccessDeniedErrCode)
+}
+
+// проверка доступа к записи после update. Стоит внутри ветки else, поэтому с дополнительным отступом
+func (d DocType) PrintSqlFuncUpdateAccessCheckRow() string {
+	return strings.ReplaceAll(d.PrintSqlFuncAccessCheckRow(d.Name+"Row"), "\n    ", "\n        ")
+}
+
+// код ошибки, которой PrintSqlFuncAccessCheckRow прерывает изменение недоступной записи
+const sqlAccessDeniedErrCode = "NL403"
+
+// секция EXCEPTION функции для ошибки из PrintSqlFuncAccessCheckRow. Блок с EXCEPTION выполняется в отдельной транзакции,
+// поэтому изменения, сделанные функцией, откатываются
+func (d DocType) PrintSqlFuncAccessDeniedHandler() string {
+	return fmt.Sprintf(`EXCEPTION
+    -- запись стала недоступна пользователю по DocSql.AccessRules
+    WHEN SQLSTATE '%s' THEN
+        RETURN json_build_object('ok', FALSE, 'message', SQLERRM);`, sqlAccessDeniedErrCode)
+}
+
+// list.sql ограничение записей по DocSql.AccessRules. Добавляется к whereStr после where_str_build
+func (d DocType) PrintSqlFuncListAccessCond() string {
+	return fmt.Sprintf(`-- ограничение записей по ролям пользователя (DocSql.AccessRules)
+    whereStr = concat(whereStr, ' AND %s_access(', quote_nullable(params ->> 'user_id'), '::int, doc)');`, d.PgName())
+}
+
+// Deprecated: используйте DocSql.AccessRules, условие в list.sql печатает PrintSqlFuncListAccessCond.
+// Оставлено для шаблонов проектов, которые вызывают эту функцию. Если AccessRules заданы, то вызов должен стоять после сборки whereStr.
+// Без AccessRules - прежнее поведение: не admin видит только записи, где manager_id = user_id
+func (d DocType) PrintSqlFuncListRoleConditions() string {
+	if len(d.Sql.AccessRules) > 0 {
+		return d.PrintSqlFuncListAccessCond()
+	}
+	return `if is_user_role((params->>'user_id')::int, '{"admin"}') is not true then
+        params = params || jsonb_build_object('manager_id', params->>'user_id');
+    end if;`
+}
+
 func (d DocType) PrintSqlFuncListWhereCond() string {
 	arr := []string{"['ilike', 'search_text', 'search_text']"}
+	// полнотекстовый поиск по search_vector вместо ilike (см search_tsquery)
+	if d.Sql.IsFullTextSearch {
+		arr = []string{fmt.Sprintf("['format', 'search_text', 'doc.search_vector @@ search_tsquery(''%s'', %%s)']", d.Sql.GetFullTextSearchConfig())}
+	}
 	for _, fld := range d.Flds {
 		if fld.Name == "title" {
 			continue
@@ -227,9 +445,55 @@ func (d DocType) PrintSqlFuncListWhereCond() string {
 			arr = append(arr, fmt.Sprintf("\t\t['%[1]s', '%[2]s', 'doc.%[2]s']", typeStr, fld.Name))
 		}
 	}
+	for _, fp := range d.ListFilterParams() {
+		arr = append(arr, fmt.Sprintf("\t\t['%s', '%s', '%s']", fp.Op, fp.Param, strings.ReplaceAll(fp.Column, "'", "''")))
+	}
 	return strings.Join(arr, ",\n")
 }
 
+// параметры метода list для фильтров списка с видом (VueDocListFilter.Kind). Фильтры по одному значению идут через поля IsSearch и ссылки
+func (d DocType) ListFilterParams() []DocListFilterParam {
+	res := []DocListFilterParam{}
+	isAdded := map[string]bool{}
+	add := func(op, param, column string, fld FldType) {
+		if !isAdded[param] {
+			isAdded[param] = true
+			res = append(res, DocListFilterParam{Op: op, Param: param, Column: column, Fld: fld})
+		}
+	}
+	flds := map[string]FldType{}
+	for _, fld := range d.Flds {
+		flds[fld.Name] = fld
+	}
+	for _, f := range d.Vue.FilterList {
+		// неизвестное поле - ошибка в ValidateProject
+		fld, ok := flds[f.FldName]
+		if !ok {
+			continue
+		}
+		column := "doc." + fld.Name
+		switch f.Kind {
+		case VueDocListFilterKindDateRange:
+			add("dateBetween", fld.Name+"_between", column, fld)
+		case VueDocListFilterKindNumberRange:
+			add("gte", fld.Name+"_gte", column, fld)
+			add("lte", fld.Name+"_lte", column, fld)
+		case VueDocListFilterKindIn, VueDocListFilterKindRefMulti:
+			add("in", fld.Name+"_in", column, fld)
+		case VueDocListFilterKindNotIn:
+			add("notIn", fld.Name+"_not_in", column, fld)
+		case VueDocListFilterKindIsNull:
+			add("isNull", fld.Name+"_is_null", column, fld)
+		case VueDocListFilterKindArrayContains:
+			add("arrayContains", fld.Name+"_contains", column, fld)
+		case VueDocListFilterKindOption:
+			// отдельный суффикс, чтобы параметр не совпал с фильтром по полю (см PrintSqlFuncListWhereCond)
+			add("jsonPath", fld.Name+"_option", fmt.Sprintf("doc.options -> '%s'", fld.Name), fld)
+		}
+	}
+	return res
+}
+
 // get_by_id.sql функиця по добавлению join
 func (d DocType) PrintSqlFuncList() (res string) {
 	cnt := 1
@@ -246,9 +510,15 @@ func (d DocType) PrintSqlFuncList() (res string) {
 			if refTable == "user" {
 				refTable = `"user"`
 			}
-			arr = append(arr, fmt.Sprintf("\t\tt%v as (select t%[2]v.*, c.title as %[3]s_title from t%[2]v left join %[4]s c on c.id = t%[2]v.%[5]s)", cnt, cnt-1, f.Sql.Ref, refTable, f.Name))
+			// то же имя title, что в get_by_id (см RefTitleName)
+			arr = append(arr, fmt.Sprintf("\t\tt%v as (select t%[2]v.*, c.title as %[5]s from t%[2]v left join %[3]s c on c.id = t%[2]v.%[4]s)", cnt, cnt-1, refTable, f.Name, f.RefTitleName()))
 		}
 	}
+	if d.Sql.IsFullTextSearch {
+		// служебная колонка search_vector клиенту не отдается
+		res = fmt.Sprintf("%s\n \tselect json_agg(to_jsonb(t%v.*) - ''search_vector'') from t%[2]v') into result;", strings.Join(arr, ",\n"), cnt)
+		return
+	}
 	res = fmt.Sprintf("%s\n \tselect array_to_json(array_agg(t%v.*)) from t%v') into result;", strings.Join(arr, ",\n"), cnt, cnt)
 
 	return
@@ -444,6 +714,73 @@ func (d DocType) GetSearchTextString() string {
 	return strings.Join(arr, ", ' ', ")
 }
 
+// для BEFORE TRIGGER
+// формирование tsvector из полей с IsSearch для полнотекстового поиска (DocSql.IsFullTextSearch)
+func (d DocType) GetSearchVectorString() string {
+	refTitle := func(fld FldType) string {
+		return snaker.SnakeToCamelLower(strings.TrimSuffix(fld.Name, "_id")) + "Title"
+	}
+	// тэги собираются в searchTxtVar
+	return strings.Join(d.searchVectorParts("new", refTitle, "searchTxtVar"), " ||\n            ")
+}
+
+// tsvector для заполнения search_vector у существующих записей. То же, что в before триггере, но без переменных триггера
+func (d DocType) searchVectorBackfillExpr() string {
+	refTitle := func(fld FldType) string {
+		refTable := fld.Sql.Ref
+		if refTable == "user" {
+			refTable = `"user"`
+		}
+		return fmt.Sprintf("(select r.title from %s r where r.id = doc.%s)", refTable, fld.Name)
+	}
+	tags := "''"
+	for _, fld := range d.Flds {
+		if fld.Vue.Type == FldVueTypeTags {
+			tags = fmt.Sprintf("%s || (doc.%s)::text", tags, fld.Name)
+		}
+	}
+	return strings.Join(d.searchVectorParts("doc", refTitle, tags), " || ")
+}
+
+// части tsvector по полям с IsSearch с весами. row - запись (new в триггере), refTitle - выражение для title ссылки, tags - текст тэгов
+func (d DocType) searchVectorParts(row string, refTitle func(FldType) string, tags string) []string {
+	config := d.Sql.GetFullTextSearchConfig()
+	arr := []string{}
+	for _, fld := range d.Flds {
+		if !fld.Sql.IsSearch {
+			continue
+		}
+		value := fmt.Sprintf("%s.%s::text", row, fld.Name)
+		if len(fld.Sql.Ref) > 0 {
+			value = refTitle(fld)
+		}
+		arr = append(arr, fmt.Sprintf("setweight(to_tsvector('%s', coalesce(%s, '')), '%s')", config, value, fld.GetSearchWeight()))
+	}
+	return append(arr, fmt.Sprintf("setweight(to_tsvector('%s', coalesce(%s, '')), 'D')", config, tags))
+}
+
+// list.sql текст для выделения найденных фрагментов (ts_headline). Берется из записи списка, поля скрытые по ролям не используются
+func (d DocType) PrintSqlFuncSearchHeadlineText() string {
+	arr := []string{}
+	for _, fld := range d.Flds {
+		if !fld.Sql.IsSearch || len(fld.ReadRoles()) > 0 {
+			continue
+		}
+		if len(fld.Sql.Ref) == 0 {
+			arr = append(arr, fmt.Sprintf("e.value ->> '%s'", fld.Name))
+		} else if d.Sql.IsSearchText {
+			// см PrintSqlFuncList: title ссылки берется из options.title
+			arr = append(arr, fmt.Sprintf("e.value -> 'options' -> 'title' ->> '%s_title'", strings.TrimSuffix(fld.Name, "_id")))
+		} else {
+			arr = append(arr, fmt.Sprintf("e.value ->> '%s'", fld.RefTitleName()))
+		}
+	}
+	if len(arr) == 0 {
+		return "''"
+	}
+	return strings.Join(arr, ", ")
+}
+
 // формирование json из полей для search_txt
 func (d DocType) GetSearchTextJson() string {
 	arr := []string{}
@@ -470,7 +807,7 @@ func (d DocType) GetSearchTextJson() string {
 
 // формирование списка переменных для before триггера
 func (d DocType) GetBeforeTriggerDeclareVars() string {
-	if !d.Sql.IsSearchText {
+	if !d.Sql.IsSearchText && !d.Sql.IsFullTextSearch {
 		return ""
 	}
 	res := ""
@@ -491,7 +828,7 @@ func (d DocType) GetBeforeTriggerDeclareVars() string {
 	return res
 }
 func (d DocType) GetBeforeTriggerFillRefVars() string {
-	if !d.Sql.IsSearchText {
+	if !d.Sql.IsSearchText && !d.Sql.IsFullTextSearch {
 		return ""
 	}
 	res := ""
@@ -546,6 +883,14 @@ func (ds *DocSql) FillBaseMethods(docName string, roles ...string) {
 	}
 }
 
+// конфигурация полнотекстового поиска (см DocSql.FullTextSearchConfig). Дефолт: russian
+func (ds DocSql) GetFullTextSearchConfig() string {
+	if len(ds.FullTextSearchConfig) > 0 {
+		return ds.FullTextSearchConfig
+	}
+	return "russian"
+}
+
 func (d DocType) PrintAfterTriggerUpdateLinkedRecords() string {
 	res := ""
 	// ищем таблицы, которые ссылаются на эту и если такие есть, то прописываем триггер, чтобы при обновлении записи, обновляем связанные записи чтобы обновились ссылки
@@ -169,8 +169,8 @@ func (d DocType) PrintVueVars(tmplName string) string {
 	}
 	// печатаем переменные
 	res := ""
-	for vName, value := range vars {
-		res = fmt.Sprintf("%s\t%s: %s,\n", res, vName, value)
+	for _, vName := range utils.SortedKeys(vars) {
+		res = fmt.Sprintf("%s\t%s: %s,\n", res, vName, vars[vName])
 	}
 	return res
 }
@@ -188,8 +188,8 @@ func (d DocType) PrintVueMethods(tmplName string) string {
 	}
 	// печатаем методы
 	res := ""
-	for mName, funcTxt := range methods {
-		res = fmt.Sprintf("%s\t%s {\n\t\t\t\t%s\n\t\t\t\t\t\t},\n", res, mName, funcTxt)
+	for _, mName := range utils.SortedKeys(methods) {
+		res = fmt.Sprintf("%s\t%s {\n\t\t\t\t%s\n\t\t\t\t\t\t},\n", res, mName, methods[mName])
 	}
 	return res
 }
@@ -490,7 +490,7 @@ func GetFldVueCompositionRefList(d *DocType, refDoc VueCompRefListWidgetParams,
 	}
 	d.Templates[fmt.Sprintf("%s_ref_list_widget", refDoc.FldName)] = &DocTemplate{
 		Source:       fmt.Sprintf("%s/templates/webClient/quasar_%v/doc/comp/refListWidget.vue", getRootDirPath(), d.GetProject().GetQuasarVersion()),
-		DistPath:     fmt.Sprintf("../src/webClient/src/app/components/%s/comp", d.PgName()),
+		DistPath:     fmt.Sprintf("%s/webClient/src/app/components/%s/comp", DefaultDistPath, d.PgName()),
 		DistFilename: snaker.SnakeToCamelLower(refDoc.FldName) + "RefListWidget.vue",
 		FuncMap: map[string]interface{}{
 			"GetTableName":  func() string { return refDoc.TableName },
@@ -0,0 +1,144 @@
+package types
+
+import (
+	"log"
+	"text/template"
+
+	"github.com/NL-A/nla_framework/utils"
+)
+
+type (
+	// GeneratorPlugin расширение генератора: интеграция с внешней системой и пр. Плагин регистрируется через RegisterPlugin
+	// (обычно в init() пакета плагина) и участвует в генерации, если IsEnabled возвращает true.
+	// Чтобы не реализовывать все методы, достаточно встроить PluginBase и переопределить нужные
+	GeneratorPlugin interface {
+		Name() string
+		// плагин включен для проекта. Например, если заполнены настройки интеграции
+		IsEnabled(p ProjectType) bool
+		// функции, которые добавляются во все шаблоны
+		FuncMap(p ProjectType) template.FuncMap
+		// файлы уровня проекта
+		ProjectTemplates(p ProjectType) []PluginTemplate
+		// файлы документа. Ключ - название шаблона в DocType.Templates. Source шаблона парсится вместе со стандартными функциями
+		DocTemplates(p ProjectType, d DocType) map[string]*DocTemplate
+		// секции config.toml, которые читаются в types.Config генерируемого проекта
+		ConfigSections(p ProjectType) []PluginConfigSection
+		// содержимое именованных слотов в копируемых файлах. Например, pluginsConfig - строки объекта config во vue (app/plugins/config.js)
+		Slots(p ProjectType) map[string][]string
+		// go код генерируемого проекта: импорты, флаги, роуты, job'ы, код перед запуском веб сервера. Добавляется к ProjectType.Go
+		Go(p ProjectType) ProjectGo
+		// дополнительные sql методы документа
+		SqlMethods(p ProjectType, d DocType) []*DocSqlMethod
+		// проверка описания проекта. Вызывается для всех зарегистрированных плагинов, в том числе выключенных
+		Validate(p ProjectType) []ValidationError
+	}
+
+	// PluginBase пустая реализация GeneratorPlugin
+	PluginBase struct{}
+
+	// файл, который генерируется плагином на уровне проекта
+	PluginTemplate struct {
+		Source  string           // путь к шаблону. Для файлов фреймворка - utils.FrameworkPath(...)
+		Dist    string           // путь к файлу относительно DistPath. Например /bitrix/main.go
+		FuncMap template.FuncMap // дополнительные функции для шаблона
+	}
+
+	// секция config.toml. В types.Config генерируемого проекта добавляется поле UpperCaseFirst(Name) с типом UpperCaseFirst(Name)+"Config"
+	PluginConfigSection struct {
+		Name string // название секции в config.toml, например telegram
+		Flds []PluginConfigFld
+	}
+
+	// строковое поле секции config.toml
+	PluginConfigFld struct {
+		Name string // название в config.toml, например botName. Поле в go - BotName
+		Env  string // если указано и переменная окружения заполнена, то она перезаписывает значение из config.toml
+	}
+)
+
+var plugins []GeneratorPlugin
+
+// RegisterPlugin регистрация плагина генератора
+func RegisterPlugin(pl GeneratorPlugin) {
+	for _, v := range plugins {
+		if v.Name() == pl.Name() {
+			log.Fatalf("RegisterPlugin: plugin '%s' already registered", pl.Name())
+		}
+	}
+	plugins = append(plugins, pl)
+}
+
+// Plugins все зарегистрированные плагины в порядке регистрации
+func Plugins() []GeneratorPlugin {
+	return plugins
+}
+
+// EnabledPlugins плагины, которые включены для проекта
+func (p ProjectType) EnabledPlugins() []GeneratorPlugin {
+	res := []GeneratorPlugin{}
+	for _, pl := range plugins {
+		if pl.IsEnabled(p) {
+			res = append(res, pl)
+		}
+	}
+	return res
+}
+
+// ApplyPlugins добавляем в описание проекта go код и sql методы включенных плагинов
+func (p *ProjectType) ApplyPlugins() {
+	for _, pl := range p.EnabledPlugins() {
+		g := pl.Go(*p)
+		p.Go.JobList = append(p.Go.JobList, g.JobList...)
+		p.Go.Routes.Imports = append(p.Go.Routes.Imports, g.Routes.Imports...)
+		p.Go.Routes.NotAuth = append(p.Go.Routes.NotAuth, g.Routes.NotAuth...)
+		p.Go.Routes.Api = append(p.Go.Routes.Api, g.Routes.Api...)
+		p.Go.Routes.Static = append(p.Go.Routes.Static, g.Routes.Static...)
+		p.Go.Routes.ImportsMainGo = append(p.Go.Routes.ImportsMainGo, g.Routes.ImportsMainGo...)
+		p.Go.HooksBeforeStartWebServer = append(p.Go.HooksBeforeStartWebServer, g.HooksBeforeStartWebServer...)
+		p.Go.MainGoImports = append(p.Go.MainGoImports, g.MainGoImports...)
+		p.Go.Flags = append(p.Go.Flags, g.Flags...)
+		for i, d := range p.Docs {
+			for _, m := range pl.SqlMethods(*p, d) {
+				if d.Sql.Methods == nil {
+					d.Sql.Methods = map[string]*DocSqlMethod{}
+				}
+				d.Sql.Methods[m.Name] = m
+			}
+			p.Docs[i] = d
+		}
+	}
+}
+
+// PluginConfigSections секции config.toml включенных плагинов. Используется в шаблонах types/main.go и types/config.go
+func (p ProjectType) PluginConfigSections() []PluginConfigSection {
+	res := []PluginConfigSection{}
+	for _, pl := range p.EnabledPlugins() {
+		res = append(res, pl.ConfigSections(p)...)
+	}
+	return res
+}
+
+// GoName название поля в types.Config
+func (s PluginConfigSection) GoName() string {
+	return utils.UpperCaseFirst(s.Name)
+}
+
+// GoType название типа секции
+func (s PluginConfigSection) GoType() string {
+	return s.GoName() + "Config"
+}
+
+// GoName название поля в типе секции
+func (f PluginConfigFld) GoName() string {
+	return utils.UpperCaseFirst(f.Name)
+}
+
+func (PluginBase) IsEnabled(p ProjectType) bool                                  { return true }
+func (PluginBase) FuncMap(p ProjectType) template.FuncMap                        { return nil }
+func (PluginBase) ProjectTemplates(p ProjectType) []PluginTemplate               { return nil }
+func (PluginBase) DocTemplates(p ProjectType, d DocType) map[string]*DocTemplate { return nil }
+func (PluginBase) ConfigSections(p ProjectType) []PluginConfigSection            { return nil }
+func (PluginBase) Slots(p ProjectType) map[string][]string                       { return nil }
+func (PluginBase) Go(p ProjectType) ProjectGo                                    { return ProjectGo{} }
+func (PluginBase) SqlMethods(p ProjectType, d DocType) []*DocSqlMethod           { return nil }
+func (PluginBase) Validate(p ProjectType) []ValidationError                      { return nil }
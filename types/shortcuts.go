@@ -3,8 +3,6 @@ package types
 import (
 	"fmt"
 	"log"
-	"path"
-	"runtime"
 	"strconv"
 	"strings"
 	"text/template"
@@ -374,7 +372,7 @@ func GetFldTag(name, nameRu string, rowCol [][]int, params ...string) (fld FldTy
 //	}),
 func GetFldLinkListWidget(linkTable string, rowCol [][]int, classStr string, opts map[string]interface{}) (fld FldType) {
 	classStr = getDefaultClassStr(classStr)
-	return FldType{Type: FldTypeVueComposition, Vue: FldVue{RowCol: rowCol, Class: []string{classStr}, Composition: func(p ProjectType, d DocType, fld FldType) string {
+	return FldType{Type: FldTypeVueComposition, Vue: FldVue{RowCol: rowCol, Class: []string{classStr}, LinkTable: linkTable, Composition: func(p ProjectType, d DocType, fld FldType) string {
 		return GetVueCompLinkListWidget(p, d, linkTable, opts)
 	}}}
 }
@@ -542,7 +540,7 @@ func (vt VueTab) AddCounter(d *DocType, tabName, pgMethod, pgParams string) VueT
 	if len(d.Vue.Path) > 0 {
 		docRouteName = d.Vue.Path
 	}
-	distPath := fmt.Sprintf("../src/webClient/src/app/components/%s/mixins", docRouteName)
+	distPath := fmt.Sprintf("%s/webClient/src/app/components/%s/mixins", DefaultDistPath, docRouteName)
 	d.Templates["webClient_mixin_tabCounter"+tabName+".js"] = &DocTemplate{
 		Source:       sourcePath,
 		DistPath:     distPath,
@@ -574,10 +572,7 @@ func getDefaultClassStr(v string) string {
 	return v
 }
 
+// корень фреймворка. Файлы фреймворка читаются из embed.FS (см utils.FrameworkPathPrefix)
 func getRootDirPath() string {
-	_, filename, _, ok := runtime.Caller(0)
-	if !ok {
-		log.Fatalf("ParseTemplates runtime.Caller: No caller information")
-	}
-	return strings.TrimSuffix(path.Dir(filename), "/types")
+	return utils.FrameworkPathPrefix
 }
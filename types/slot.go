@@ -0,0 +1,148 @@
+package types
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type (
+	// SlotType именованное место вставки в копируемых файлах фреймворка (sourceFiles, webClient).
+	// В файле слот объявляется маркером:
+	//   - блочный: строка с комментарием "codeGenerate slot:<name>" (// codeGenerate slot:routes, -- codeGenerate slot:..., # codeGenerate slot:...).
+	//     Маркер остается в файле, каждая часть содержимого вставляется после него отдельной строкой с отступом маркера
+	//   - строчный: [[slot:<name>]]. Маркер заменяется частями содержимого, соединенными через Sep
+	// Содержимое слота: Content, затем ProjectType.Slots, DocType.Slots (в порядке документов) и GeneratorPlugin.Slots
+	SlotType struct {
+		Name    string
+		Sep     string                       // разделитель частей в строковом слоте
+		Content func(p ProjectType) []string // встроенное содержимое слота. Может быть nil
+	}
+)
+
+var (
+	slots = map[string]SlotType{}
+
+	slotBlockRe  = regexp.MustCompile(`(?m)^([ \t]*)(.*codeGenerate slot:([A-Za-z][\w.]*))`)
+	slotInlineRe = regexp.MustCompile(`\[\[slot:([A-Za-z][\w.]*)\]\]`)
+)
+
+// RegisterSlot регистрация слота. Стандартные слоты регистрирует фреймворк, плагины могут регистрировать слоты для своих файлов
+func RegisterSlot(s SlotType) {
+	if _, ok := slots[s.Name]; ok {
+		log.Fatalf("RegisterSlot: slot '%s' already registered", s.Name)
+	}
+	slots[s.Name] = s
+}
+
+// IsSlotRegistered есть ли слот с таким названием
+func IsSlotRegistered(name string) bool {
+	_, ok := slots[name]
+	return ok
+}
+
+// AddToSlot добавление содержимого в слот на уровне проекта
+func (p *ProjectType) AddToSlot(name string, content ...string) *ProjectType {
+	if p.Slots == nil {
+		p.Slots = map[string][]string{}
+	}
+	p.Slots[name] = append(p.Slots[name], content...)
+	return p
+}
+
+// AddToSlot добавление содержимого в слот из документа
+func (d *DocType) AddToSlot(name string, content ...string) *DocType {
+	if d.Slots == nil {
+		d.Slots = map[string][]string{}
+	}
+	d.Slots[name] = append(d.Slots[name], content...)
+	return d
+}
+
+// SlotContent собранное содержимое всех зарегистрированных слотов
+func (p ProjectType) SlotContent() map[string][]string {
+	res := map[string][]string{}
+	for name, s := range slots {
+		if s.Content != nil {
+			res[name] = append(res[name], s.Content(p)...)
+		}
+	}
+	add := func(m map[string][]string) {
+		for name, content := range m {
+			res[name] = append(res[name], content...)
+		}
+	}
+	add(p.Slots)
+	for _, d := range p.Docs {
+		add(d.Slots)
+	}
+	for _, pl := range p.EnabledPlugins() {
+		add(pl.Slots(p))
+	}
+	return res
+}
+
+// ValidateSlots проверка, что содержимое добавляется только в зарегистрированные слоты
+func (p ProjectType) ValidateSlots() []ValidationError {
+	res := []ValidationError{}
+	check := func(docName, path string, m map[string][]string) {
+		for _, name := range sortedSlotNames(m) {
+			if !IsSlotRegistered(name) {
+				res = append(res, ValidationError{Doc: docName, Path: fmt.Sprintf("%s[%s]", path, name), Msg: fmt.Sprintf("unknown slot '%s'", name)})
+			}
+		}
+	}
+	check("", "Slots", p.Slots)
+	for _, d := range p.Docs {
+		check(d.Name, fmt.Sprintf("Docs[%s].Slots", d.Name), d.Slots)
+	}
+	for _, pl := range p.EnabledPlugins() {
+		check("", fmt.Sprintf("Plugins[%s].Slots", pl.Name()), pl.Slots(p))
+	}
+	return res
+}
+
+// FillSlots вставка содержимого слотов в файл. Маркеры незарегистрированных слотов возвращаются ошибкой
+func FillSlots(file []byte, content map[string][]string) ([]byte, error) {
+	unresolved := []string{}
+	check := func(name string) bool {
+		if !IsSlotRegistered(name) {
+			unresolved = append(unresolved, name)
+			return false
+		}
+		return true
+	}
+	res := slotBlockRe.ReplaceAllStringFunc(string(file), func(m string) string {
+		sm := slotBlockRe.FindStringSubmatch(m)
+		if !check(sm[3]) {
+			return m
+		}
+		indent := sm[1]
+		for _, part := range content[sm[3]] {
+			m += "\n" + indent + strings.ReplaceAll(part, "\n", "\n"+indent)
+		}
+		return m
+	})
+	res = slotInlineRe.ReplaceAllStringFunc(res, func(m string) string {
+		name := slotInlineRe.FindStringSubmatch(m)[1]
+		if !check(name) {
+			return m
+		}
+		return strings.Join(content[name], slots[name].Sep)
+	})
+	if len(unresolved) > 0 {
+		return nil, fmt.Errorf("unresolved slots: %s", strings.Join(unresolved, ", "))
+	}
+	return []byte(res), nil
+}
+
+func sortedSlotNames(m map[string][]string) []string {
+	res := []string{}
+	for k := range m {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
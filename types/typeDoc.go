@@ -3,7 +3,6 @@ package types
 import (
 	"errors"
 	"fmt"
-	"log"
 	"strings"
 	"text/template"
 
@@ -16,6 +15,18 @@ const (
 	VueIsNotNew      = "item.id != -1"
 )
 
+// виды фильтров списка (VueDocListFilter.Kind). Пустой - фильтр по одному значению (IsRef, IsDate или select)
+const (
+	VueDocListFilterKindDateRange     = "dateRange"     // период: параметр <fld>_between [с, по], дата "по" включается целиком
+	VueDocListFilterKindNumberRange   = "numberRange"   // диапазон чисел: параметры <fld>_gte, <fld>_lte
+	VueDocListFilterKindIn            = "in"            // одно из значений Options: параметр <fld>_in
+	VueDocListFilterKindNotIn         = "notIn"         // кроме значений Options: параметр <fld>_not_in
+	VueDocListFilterKindIsNull        = "isNull"        // пустое / заполненное: параметр <fld>_is_null
+	VueDocListFilterKindRefMulti      = "refMulti"      // несколько ссылок на RefTable: параметр <fld>_in
+	VueDocListFilterKindArrayContains = "arrayContains" // массив (text[], тэги) содержит значения: параметр <fld>_contains
+	VueDocListFilterKindOption        = "option"        // значение поля из options (IsOptionFld): параметр <fld>_option
+)
+
 type (
 	DocType struct {
 		Project              *ProjectType // ссылка на проект
@@ -34,6 +45,7 @@ type (
 		IsRecursion          bool // признак, что документ имеет рекурсию. Есть parent_id - ссылка на самого себя
 		Integrations         DocIntegrations
 		I18n                 map[string]map[string]string //RU : save: 'сохранить'
+		Slots                map[string][]string          // содержимое для именованных слотов в копируемых файлах (см SlotType)
 	}
 
 	TmplPathOverride struct {
@@ -118,6 +130,7 @@ type (
 	VueDocListFilter struct {
 		Label              string
 		FldName            string
+		Kind               string // вид фильтра, см VueDocListFilterKind...
 		IsRef              bool
 		IsDate             bool
 		RefTable           string
@@ -128,7 +141,15 @@ type (
 
 	VueDocListSort struct {
 		Label string
-		Value string
+		Value string // колонка или несколько колонок через запятую, например 'state, created_at'
+	}
+
+	// параметр метода list для фильтра списка, см DocType.ListFilterParams
+	DocListFilterParam struct {
+		Op     string // тип условия в where_str_build: between, gte, in...
+		Param  string // название параметра
+		Column string // выражение для колонки в sql
+		Fld    FldType
 	}
 
 	VueDocListCreateNewModal struct {
@@ -147,16 +168,21 @@ type (
 
 	DocSql struct {
 		Methods              map[string]*DocSqlMethod
-		IsUniqLink           bool                    // флаг, что таблица является связью двух таблиц и связь между ними уникальная
-		IsBeforeTrigger      bool                    // флаг что добавляем before триггер
-		IsAfterTrigger       bool                    // флаг что добавляем after триггер
-		IsNotifyEventTrigger bool                    // флаг что добавляем notify_event триггер
-		CustomTriggers       []string                // дополнительные строчки с триггерами. Пример: {name="acquire_game_player_created", when="before insert or update", ref="for each row", funcName="builtin_fld_update"}
-		IsSearchText         bool                    // флаг что добавляем поле search_text
-		Indexes              []string                // индексы
-		Hooks                DocSqlHooks             // куски sql кода
-		CheckConstrains      []DocSqlCheckConstraint // список ограничений в таблице
-		UniqConstrains       []DocSqlUniqConstraint  // список ограничений на уникаальность
+		IsUniqLink           bool                        // флаг, что таблица является связью двух таблиц и связь между ними уникальная
+		IsBeforeTrigger      bool                        // флаг что добавляем before триггер
+		IsAfterTrigger       bool                        // флаг что добавляем after триггер
+		IsNotifyEventTrigger bool                        // флаг что добавляем notify_event триггер
+		CustomTriggers       []string                    // дополнительные строчки с триггерами. Пример: {name="acquire_game_player_created", when="before insert or update", ref="for each row", funcName="builtin_fld_update"}
+		IsSearchText         bool                        // флаг что добавляем поле search_text
+		Indexes              []string                    // индексы
+		Hooks                DocSqlHooks                 // куски sql кода
+		CheckConstrains      []DocSqlCheckConstraint     // список ограничений в таблице
+		UniqConstrains       []DocSqlUniqConstraint      // список ограничений на уникаальность
+		PrevNames            []string                    // прежние названия таблицы. По ним миграция переименовывает таблицу, а не создает новую пустую
+		IsHistory            bool                        // флаг что изменения записей пишутся в таблицу <doc>_history. Добавляется метод <doc>_history_list и таб "история"
+		AccessRules          map[string]DocSqlAccessRule // ограничение доступа к записям: роль - условие. Если заполнено, то роли без правила записей не видят. admin видит все
+		IsFullTextSearch     bool                        // флаг что добавляем колонку search_vector (tsvector) для полнотекстового поиска в list. Заполняется в before триггере из полей с IsSearch
+		FullTextSearchConfig string                      // конфигурация полнотекстового поиска postgres. По умолчанию russian
 	}
 
 	DocIsBaseTemplates struct {
@@ -196,6 +222,22 @@ type (
 		CheckConditions string //
 	}
 
+	// условие доступа роли к записи документа. Заполненные условия объединяются через AND. Пустое правило - доступ ко всем записям.
+	// Проверяется в list, get_by_id, update, action и списках тэгов (см функцию <doc>_access)
+	DocSqlAccessRule struct {
+		OwnerFld   string                  // поле со ссылкой на пользователя. Запись доступна, если в поле id текущего пользователя. Например manager_id
+		Department *DocSqlAccessDepartment // запись доступна, если ее подразделение - одно из подразделений пользователя
+		States     []string                // запись доступна только в этих состояниях (поле state)
+		Sql        string                  // произвольное условие. Запись - doc, id пользователя - userId. Например "doc.amount < 100000"
+	}
+
+	DocSqlAccessDepartment struct {
+		Fld       string // поле документа со ссылкой на подразделение. Например department_id
+		LinkTable string // таблица связи подразделений и пользователей. Например department_user_link
+		LinkFld   string // поле таблицы связи со ссылкой на подразделение. По умолчанию совпадает с Fld
+		UserFld   string // поле таблицы связи со ссылкой на пользователя. По умолчанию user_id
+	}
+
 	DocSqlUniqConstraint struct {
 		Name           string
 		UniqConditions string //
@@ -255,8 +297,9 @@ func (d DocType) Fld(fldName string) *FldType {
 			return &f
 		}
 	}
-	log.Fatalf("d.Fld: doc '%s' fld '%s' not found", d.Name, fldName)
-	return nil
+	// поле не найдено - запоминаем ошибку для отчета ValidateProject и возвращаем пустое поле, чтобы продолжить сборку проекта
+	addBuildError(ValidationError{Doc: d.Name, Fld: fldName, Path: fmt.Sprintf("Docs[%s].Fld(%q)", d.Name, fldName), Msg: "field not found"})
+	return &FldType{Name: fldName}
 }
 
 // место вызова разных доп функций для инициализации документа, после того как основные поля заполнены
@@ -273,6 +316,10 @@ func (d *DocType) Init() {
 	//}
 
 	d.Filli18n()
+	// search_vector для полнотекстового поиска заполняется в before триггере
+	if d.Sql.IsFullTextSearch {
+		d.Sql.IsBeforeTrigger = true
+	}
 	if len(d.Vue.Readonly) == 0 {
 		d.Vue.Readonly = "false"
 	}
@@ -283,6 +330,22 @@ func (d *DocType) Init() {
 			d.Flds[i].Vue.Readonly = d.Vue.Readonly
 		}
 	}
+	// история изменений показывается отдельным табом. Если табов нет, то форма документа переносится в таб "инфо".
+	// Метод <doc>_history_list генерируется только вместе со стандартными sql шаблонами
+	if d.Sql.IsHistory && d.IsBaseTemplates.Sql {
+		isHistoryTab := false
+		for _, tab := range d.Vue.Tabs {
+			if tab.TmplName == "tabHistory.vue" {
+				isHistoryTab = true
+			}
+		}
+		if !isHistoryTab {
+			if len(d.Vue.Tabs) == 0 {
+				d.Vue.Tabs = []VueTab{{"info", "инфо", "tabInfo.vue", "assignment", "", ""}}
+			}
+			d.Vue.Tabs = append(d.Vue.Tabs, VueTab{"history", "история", "tabHistory.vue", "history", "", ""})
+		}
+	}
 	// если есть табы и к документу можно присоединять задачи, то прописываем миксин
 	if d.IsTaskAllowed && len(d.Vue.Tabs) > 0 {
 		if d.Vue.Mixins == nil {
@@ -315,6 +378,12 @@ func (d DocType) IsStateMachine() bool {
 	return d.StateMachine != nil
 }
 
+// список строит стандартная функция list.sql, а не шаблон документа. Только она поддерживает курсорную пагинацию и with_total
+func (d DocType) IsBaseListFunc() bool {
+	dt, ok := d.Templates["sql_function_list.sql"]
+	return ok && len(dt.Source) == 0
+}
+
 func (d DocType) IsBitrixIntegration() bool {
 	return len(d.Integrations.Bitrix.UrlName) > 0
 }
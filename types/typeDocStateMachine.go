@@ -19,6 +19,7 @@ type (
 		Actions          []DocSmAction
 		UpdateFlds       []FldType // поля, которые можно редактировать в этом стейте
 		IconSrc          string
+		IsFinal          bool // конечное состояние, переходов из него нет. Состояние без переходов считается конечным и без флага
 		FuncMapForCard   map[string]interface{}
 		FuncMapForAction map[string]interface{}
 	}
@@ -161,6 +162,59 @@ func (st DocSm) GetFirstState() DocSmState {
 	return DocSmState{}
 }
 
+// ActionFrom состояние, из которого выполняется переход. Если From не указан, то состояние, в котором описан переход
+func (st DocSmState) ActionFrom(actn DocSmAction) string {
+	if len(actn.From) > 0 {
+		return actn.From
+	}
+	return st.Title
+}
+
+// ReachableStates состояния, в которые можно попасть из первого состояния (см GetFirstState)
+func (sm DocSm) ReachableStates() map[string]bool {
+	res := map[string]bool{}
+	if len(sm.States) == 0 {
+		return res
+	}
+	next := map[string][]string{}
+	for _, st := range sm.States {
+		for _, actn := range st.Actions {
+			from := st.ActionFrom(actn)
+			next[from] = append(next[from], actn.To)
+		}
+	}
+	queue := []string{sm.GetFirstState().Title}
+	res[queue[0]] = true
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, to := range next[name] {
+			if !res[to] {
+				res[to] = true
+				queue = append(queue, to)
+			}
+		}
+	}
+	return res
+}
+
+// FinalStates конечные состояния: отмеченные IsFinal и состояния, из которых нет переходов
+func (sm DocSm) FinalStates() map[string]bool {
+	hasExit := map[string]bool{}
+	for _, st := range sm.States {
+		for _, actn := range st.Actions {
+			hasExit[st.ActionFrom(actn)] = true
+		}
+	}
+	res := map[string]bool{}
+	for _, st := range sm.States {
+		if st.IsFinal || !hasExit[st.Title] {
+			res[st.Title] = true
+		}
+	}
+	return res
+}
+
 func (st DocSmState) GetStateUpdateFldsGrid() func() [][]FldType {
 	res := [][]FldType{}
 	for _, f := range st.UpdateFlds {
@@ -230,7 +284,7 @@ func (sm *DocSm) GenerateTmpls(doc *DocType, params map[string]interface{}) {
 		}
 		doc.Templates[fileName] = &DocTemplate{
 			Source:       cardTmplPath,
-			DistPath:     fmt.Sprintf("../src/webClient/src/app/components/%s/comp", doc.Name),
+			DistPath:     fmt.Sprintf("%s/webClient/src/app/components/%s/comp", DefaultDistPath, doc.Name),
 			DistFilename: fileName,
 			FuncMap: map[string]interface{}{
 				"GetStateName": func() string { return stTitle },
@@ -272,7 +326,7 @@ func (sm *DocSm) GenerateTmpls(doc *DocType, params map[string]interface{}) {
 			}
 			doc.Templates[fileName] = &DocTemplate{
 				Source:       actionBtnPath,
-				DistPath:     fmt.Sprintf("../src/webClient/src/app/components/%s/comp", doc.Name),
+				DistPath:     fmt.Sprintf("%s/webClient/src/app/components/%s/comp", DefaultDistPath, doc.Name),
 				DistFilename: fileName,
 				FuncMap: map[string]interface{}{
 					"GetLabel":          func() string { return actnLabel },
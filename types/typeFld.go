@@ -2,7 +2,6 @@ package types
 
 import (
 	"fmt"
-	"log"
 	"strings"
 
 	"github.com/NL-A/nla_framework/utils"
@@ -46,6 +45,13 @@ type (
 		Sql             FldSql
 		Doc             *DocType               // ссылка на сам документ, к которому принадлежит поле
 		IntegrationData map[string]interface{} // информация по интеграции с разными системами
+		Roles           FldRoles               // доступ к полю по ролям. Проверяется и в sql, и во vue. Для полей IsOptionFld не поддерживается
+	}
+
+	// доступ к полю по ролям. Пустой список - без ограничений. admin и системные вызовы (без user_id) имеют полный доступ
+	FldRoles struct {
+		Read  []string // роли, которым поле видно. Остальным поле не показывается и не возвращается из get_by_id и list
+		Write []string // роли, которым поле можно изменять. Остальным поле только для чтения, а значение из update игнорируется
 	}
 
 	FldVue struct {
@@ -62,6 +68,7 @@ type (
 		Composition  func(ProjectType, DocType, FldType) string
 		Vif          string
 		JsonList     FldVueJsonList
+		LinkTable    string // таблица связи многие-к-многим для виджета GetFldLinkListWidget
 	}
 
 	FldSql struct {
@@ -73,8 +80,10 @@ type (
 		Size                     int
 		IsOptionFld              bool // признак что поле пишется не в отдельную колонку таблицы, а в json поле options
 		Default                  string
-		IsNotUpdatable           bool   // признак, что поле не обновляется вручную. Либо заполняется только при создании, либо обновляется триггером
-		FillValueInBeforeTrigger string // строка, которая выполняется в trigger и результат, которой присваивается полю. Например new.fullname
+		IsNotUpdatable           bool     // признак, что поле не обновляется вручную. Либо заполняется только при создании, либо обновляется триггером
+		FillValueInBeforeTrigger string   // строка, которая выполняется в trigger и результат, которой присваивается полю. Например new.fullname
+		PrevNames                []string // прежние названия колонки. По ним миграция переименовывает колонку, а не создает новую пустую
+		SearchWeight             string   // вес поля в полнотекстовом поиске (DocSql.IsFullTextSearch): A, B, C или D. По умолчанию title - A, остальные - B
 	}
 
 	FldVueOptionsItem struct {
@@ -144,8 +153,10 @@ func (fld *FldType) GoType() string {
 		return "[]float64"
 	case FldTypeTextArray:
 		return "[]string"
-	case FldTypeDate, FldTypeDatetime, FldTypeUuid:
+	case FldTypeDate, FldTypeDatetime, FldTypeUuid, FldTypeText:
 		return "string"
+	case FldTypeJsonb:
+		return "interface{}"
 	default:
 		return fld.Type
 	}
@@ -188,11 +199,9 @@ func (fld *FldType) PgUpdateType() string {
 // переписываем значение колонки и строки. Третье число - ширина колонки
 func (fld FldType) SetRowCol(n ...int) FldType {
 	if len(n) < 2 {
-		docName := ""
-		if fld.Doc != nil {
-			docName = fld.Doc.Name
-		}
-		log.Fatalf("Doc: '%s' Fld: '%s' SetRowCol params must be more tan two numbers. Get %v", docName, fld.Name, n)
+		// некорректное значение сохраняем как есть. Ошибка с указанием документа выводится в отчете ValidateProject
+		fld.Vue.RowCol = [][]int{n}
+		return fld
 	}
 	fld.Vue.RowCol = [][]int{{n[0], n[1]}}
 	// если указано третье число, то заменяем класс, описыающий ширину колонки
@@ -256,6 +265,69 @@ func (fld FldType) SetDefault(s string) FldType {
 	return fld
 }
 
+// прежние названия колонки (см FldSql.PrevNames). Например, при переименовании inn в tax_number: SetPrevNames("inn")
+func (fld FldType) SetPrevNames(names ...string) FldType {
+	fld.Sql.PrevNames = names
+	return fld
+}
+
+// роли, которым поле видно (см FldRoles.Read). Роли из FldRoles.Write видят поле автоматически
+func (fld FldType) SetReadRoles(roles ...string) FldType {
+	fld.Roles.Read = roles
+	return fld
+}
+
+// роли, которым поле можно изменять (см FldRoles.Write)
+func (fld FldType) SetWriteRoles(roles ...string) FldType {
+	fld.Roles.Write = roles
+	return fld
+}
+
+// ключ с названием записи из ref таблицы в результатах list и get_by_id. Например, from_location_id -> from_location_title
+func (fld FldType) RefTitleName() string {
+	return strings.TrimSuffix(fld.Name, "_id") + "_title"
+}
+
+// итоговый список ролей, которым поле видно. nil - ограничений нет
+func (fld FldType) ReadRoles() []string {
+	if len(fld.Roles.Read) == 0 {
+		return nil
+	}
+	res := append([]string{}, fld.Roles.Read...)
+	for _, r := range fld.Roles.Write {
+		if !utils.CheckContainsSliceStr(r, res...) {
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
+// итоговый список ролей, которым поле можно изменять. Скрытое поле изменять тоже нельзя. nil - ограничений нет
+func (fld FldType) WriteRoles() []string {
+	if len(fld.Roles.Write) > 0 {
+		return fld.Roles.Write
+	}
+	return fld.ReadRoles()
+}
+
+// вес поля в полнотекстовом поиске с учетом значения по умолчанию
+func (fld FldType) GetSearchWeight() string {
+	if len(fld.Sql.SearchWeight) > 0 {
+		return fld.Sql.SearchWeight
+	}
+	if fld.Name == "title" {
+		return "A"
+	}
+	return "B"
+}
+
+// вес поля в полнотекстовом поиске (см FldSql.SearchWeight). Поле сразу участвует в поиске
+func (fld FldType) SetSearchWeight(weight string) FldType {
+	fld.Sql.IsSearch = true
+	fld.Sql.SearchWeight = weight
+	return fld
+}
+
 func (fld FldType) SetIsNotUpdatable() FldType {
 	fld.Sql.IsNotUpdatable = true
 	return fld
@@ -313,20 +385,21 @@ func (fld FldType) SetVif(s string) FldType {
 	return fld
 }
 
-func (fld FldType) SetBitrixInfo(b BitrixFld) FldType {
+// SetIntegrationData данные поля для плагина (см GeneratorPlugin). Ключ - название плагина
+func (fld FldType) SetIntegrationData(pluginName string, v interface{}) FldType {
 	if fld.IntegrationData == nil {
 		fld.IntegrationData = map[string]interface{}{}
 	}
-	fld.IntegrationData["bitrix"] = b
+	fld.IntegrationData[pluginName] = v
 	return fld
 }
 
+func (fld FldType) SetBitrixInfo(b BitrixFld) FldType {
+	return fld.SetIntegrationData("bitrix", b)
+}
+
 func (fld FldType) SetOdataInfo(b OdataFld) FldType {
-	if fld.IntegrationData == nil {
-		fld.IntegrationData = map[string]interface{}{}
-	}
-	fld.IntegrationData["odata"] = b
-	return fld
+	return fld.SetIntegrationData("odata", b)
 }
 
 func (fld FldVue) ClassPrint() string {
@@ -16,6 +16,17 @@ import (
 	"github.com/serenize/snaker"
 )
 
+const (
+	// корень генерируемого проекта и путь к исходникам по умолчанию (относительно projectTemplate).
+	// Пути шаблонов, которые заполняются при описании документа (до вызова Start), начинаются с DefaultDistPath
+	// и при генерации заменяются на ProjectType.DistPath
+	DefaultOutputRoot = ".."
+	DefaultDistPath   = DefaultOutputRoot + "/src"
+)
+
+// служебные таблицы, которые создаются фреймворком и на которые можно ссылаться через FldSql.Ref
+var SystemTables = []string{"user", "user_auth", "user_temp_email_auth", "file", "message", "task_type", "task", "chat", "chat_message"}
+
 type (
 	ProjectType struct {
 		Name                     string
@@ -29,6 +40,13 @@ type (
 		IsDebugMode              bool
 		OverridePathForTemplates map[string]string // map для замены путей к исходным файлам. Ключ - путь к генерируемому файлу, значение - новый путь к исходному файлу.
 		I18n                     I18nType
+		Generator                GeneratorConfig     // настройки самого процесса генерации
+		Slots                    map[string][]string // содержимое для именованных слотов в копируемых файлах (см SlotType)
+	}
+	GeneratorConfig struct {
+		IsForceOverwrite bool         // перезаписывать/удалять файлы, даже если они были изменены вручную после прошлой генерации
+		OutputRoot       string       // корневая директория генерируемого проекта. По умолчанию '..' (относительно projectTemplate). Исходники пишутся в OutputRoot/src
+		Output           utils.Output // куда записываются файлы. По умолчанию на диск (utils.DirOutput). Для генерации в память - utils.NewMemOutput(nil)
 	}
 	ProjectConfig struct {
 		Logo             string
@@ -250,6 +268,8 @@ func (p *ProjectType) FillDocTemplatesFields() {
 			d.Templates = map[string]*DocTemplate{}
 		}
 		for tName, t := range d.Templates {
+			// путь, заданный относительно дефолтного расположения проекта, переносим в p.DistPath
+			t.DistPath = p.ReplaceDefaultDistPath(t.DistPath)
 			// прописываем полный путь к файлу шаблона
 			if len(t.Source) == 0 {
 				// учитывааем что возможен префикс, если папка с документом вложена в другую папку
@@ -274,6 +294,17 @@ func (p *ProjectType) FillDocTemplatesFields() {
 	}
 }
 
+// ReplaceDefaultDistPath замена префикса DefaultDistPath в пути на p.DistPath
+func (p ProjectType) ReplaceDefaultDistPath(path string) string {
+	if len(p.DistPath) == 0 || p.DistPath == DefaultDistPath {
+		return path
+	}
+	if path == DefaultDistPath || strings.HasPrefix(path, DefaultDistPath+"/") {
+		return p.DistPath + strings.TrimPrefix(path, DefaultDistPath)
+	}
+	return path
+}
+
 // заполняем незаполненные поля для Vue
 func (p *ProjectType) FillVueFlds() {
 	for i, d := range p.Docs {
@@ -323,7 +354,8 @@ func (p *ProjectType) FillSideMenu() {
 		if len(v.DocName) > 0 {
 			d := p.GetDocByName(v.DocName)
 			if d == nil {
-				log.Fatalf("ProjectType.FillSideMenu p.GetDocByName doc '%s' not found", v.DocName)
+				// ошибка выводится в отчете ValidateProject
+				continue
 			}
 			if len(v.Icon) == 0 {
 				p.Vue.Menu[i].Icon = d.Vue.MenuIcon
@@ -350,7 +382,7 @@ func (p *ProjectType) FillSideMenu() {
 				if len(v1.DocName) > 0 {
 					d := p.GetDocByName(v1.DocName)
 					if d == nil {
-						log.Fatalf("ProjectType.FillSideMenu p.GetDocByName doc '%s' not found", v1.DocName)
+						continue
 					}
 					if len(v1.Icon) == 0 {
 						p.Vue.Menu[i].LinkList[j].Icon = d.Vue.MenuIcon
@@ -490,14 +522,18 @@ func sortedKeys(m map[string]DocSqlMethod) []string {
 
 func (p ProjectType) PrintApiCallPgFuncMethods() string {
 	res := ""
-	printPgMethod := func(m DocSqlMethod) {
+	for _, m := range p.ApiCallPgFuncMethods() {
 		var roles string
 		if len(m.Roles) > 0 {
 			roles = fmt.Sprintf(`"%s"`, strings.Join(m.Roles, `", "`))
 		}
 		res = fmt.Sprintf("%s\n\t\tPgMethod{\"%s\", []string{%s}, nil, BeforeHookAddUserId},", res, m.Name, roles)
 	}
+	return res
+}
 
+// ApiCallPgFuncMethods список sql методов проекта и документов, доступных через /api/call_pg_func. Отсортирован по названию
+func (p ProjectType) ApiCallPgFuncMethods() []DocSqlMethod {
 	methods := map[string]DocSqlMethod{}
 
 	if project.Sql.Methods != nil {
@@ -513,10 +549,10 @@ func (p ProjectType) PrintApiCallPgFuncMethods() string {
 		}
 	}
 
+	res := []DocSqlMethod{}
 	for _, k := range sortedKeys(methods) {
-		printPgMethod(methods[k])
+		res = append(res, methods[k])
 	}
-
 	return res
 }
 
@@ -0,0 +1,51 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	// ошибка в описании проекта. Doc, Fld, Path - контекст, где найдена ошибка
+	ValidationError struct {
+		Doc  string
+		Fld  string
+		Path string // путь к свойству в описании проекта. Например Docs[client].Flds[city_id].Sql.Ref
+		Msg  string
+	}
+)
+
+// ошибки, найденные при сборке описания проекта (d.Fld, SetRowCol, FillSideMenu...).
+// Раньше в этих местах был log.Fatalf, теперь ошибка запоминается и выводится в общем отчете ValidateProject
+var buildErrors []ValidationError
+
+func (e ValidationError) Error() string {
+	ctx := []string{}
+	if len(e.Doc) > 0 {
+		ctx = append(ctx, fmt.Sprintf("doc: '%s'", e.Doc))
+	}
+	if len(e.Fld) > 0 {
+		ctx = append(ctx, fmt.Sprintf("fld: '%s'", e.Fld))
+	}
+	if len(e.Path) > 0 {
+		ctx = append(ctx, fmt.Sprintf("path: %s", e.Path))
+	}
+	if len(ctx) == 0 {
+		return e.Msg
+	}
+	return fmt.Sprintf("%s (%s)", e.Msg, strings.Join(ctx, ", "))
+}
+
+func addBuildError(e ValidationError) {
+	buildErrors = append(buildErrors, e)
+}
+
+// BuildErrors список ошибок, накопленных при сборке описания проекта
+func BuildErrors() []ValidationError {
+	return buildErrors
+}
+
+// ResetBuildErrors очистка ошибок сборки перед повторной сборкой описания проекта (см nla_framework.Watch)
+func ResetBuildErrors() {
+	buildErrors = nil
+}
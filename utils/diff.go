@@ -0,0 +1,161 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// количество строк контекста вокруг изменений в unified diff
+const diffContextLines = 3
+
+type diffOp struct {
+	kind byte // ' ' - без изменений, '-' - удалено, '+' - добавлено
+	text string
+}
+
+// UnifiedDiff строит unified diff между старым и новым содержимым файла. Если содержимое не изменилось, то пустая строка
+func UnifiedDiff(name string, oldData, newData []byte) string {
+	if ByteSliceEqual(oldData, newData) {
+		return ""
+	}
+	a := splitLines(string(oldData))
+	b := splitLines(string(newData))
+	ops := diffLines(a, b)
+
+	oldName, newName := "a/"+strings.TrimPrefix(name, "/"), "b/"+strings.TrimPrefix(name, "/")
+	if oldData == nil {
+		oldName = "/dev/null"
+	}
+	if newData == nil {
+		newName = "/dev/null"
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("--- %s\n+++ %s\n", oldName, newName))
+
+	// разбиваем список операций на блоки (hunk) с контекстом
+	i := 0
+	for i < len(ops) {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		start := i - diffContextLines
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			// смотрим, есть ли еще изменения в пределах двойного контекста
+			j := end
+			for j < len(ops) && ops[j].kind == ' ' && j-end < 2*diffContextLines {
+				j++
+			}
+			if j < len(ops) && ops[j].kind != ' ' {
+				end = j
+				continue
+			}
+			end += diffContextLines
+			if end > len(ops) {
+				end = len(ops)
+			}
+			break
+		}
+		// вычисляем номера строк для заголовка блока
+		oldStart, newStart := 1, 1
+		for _, op := range ops[:start] {
+			if op.kind != '+' {
+				oldStart++
+			}
+			if op.kind != '-' {
+				newStart++
+			}
+		}
+		oldCount, newCount := 0, 0
+		for _, op := range ops[start:end] {
+			if op.kind != '+' {
+				oldCount++
+			}
+			if op.kind != '-' {
+				newCount++
+			}
+		}
+		if oldCount == 0 {
+			oldStart--
+		}
+		if newCount == 0 {
+			newStart--
+		}
+		sb.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount))
+		for _, op := range ops[start:end] {
+			sb.WriteByte(op.kind)
+			sb.WriteString(op.text)
+			sb.WriteByte('\n')
+		}
+		i = end
+	}
+	return sb.String()
+}
+
+func splitLines(s string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines построчное сравнение через поиск наибольшей общей подпоследовательности
+func diffLines(a, b []string) []diffOp {
+	// отрезаем общие начало и конец, чтобы уменьшить размер таблицы
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	ops := []diffOp{}
+	for _, s := range a[:prefix] {
+		ops = append(ops, diffOp{' ', s})
+	}
+	ma, mb := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	// lcs[i][j] - длина общей подпоследовательности для ma[i:] и mb[j:]
+	lcs := make([][]int, len(ma)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(mb)+1)
+	}
+	for i := len(ma) - 1; i >= 0; i-- {
+		for j := len(mb) - 1; j >= 0; j-- {
+			if ma[i] == mb[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	i, j := 0, 0
+	for i < len(ma) || j < len(mb) {
+		switch {
+		case i < len(ma) && j < len(mb) && ma[i] == mb[j]:
+			ops = append(ops, diffOp{' ', ma[i]})
+			i++
+			j++
+		case j < len(mb) && (i == len(ma) || lcs[i][j+1] > lcs[i+1][j]):
+			ops = append(ops, diffOp{'+', mb[j]})
+			j++
+		default:
+			ops = append(ops, diffOp{'-', ma[i]})
+			i++
+		}
+	}
+	for _, s := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', s})
+	}
+	return ops
+}
@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var (
+	// куда записываются сгенерированные файлы. По умолчанию на диск
+	output Output = DirOutput{}
+	// режим "сухого" прогона: файлы не записываются в output, а накапливаются в памяти
+	isDryRun bool
+)
+
+// SetDryRun включает/выключает режим генерации в память. Применяется при следующем вызове SetOutput
+func SetDryRun(v bool) {
+	isDryRun = v
+}
+
+func IsDryRun() bool {
+	return isDryRun
+}
+
+// SetOutput устанавливаем output для генерации. Если nil, то запись на диск.
+// В режиме dry run запись идет в память поверх указанного output
+func SetOutput(o Output) {
+	if o == nil {
+		o = DirOutput{}
+	}
+	if isDryRun {
+		o = NewMemOutput(o)
+	}
+	output = o
+}
+
+func GetOutput() Output {
+	return output
+}
+
+// DryRunFiles возвращает файлы, сгенерированные в режиме dry run. Ключ - очищенный путь к файлу
+func DryRunFiles() map[string][]byte {
+	if m, ok := output.(*MemOutput); ok && isDryRun {
+		return m.Files
+	}
+	return nil
+}
+
+// WriteFile запись сгенерированного файла в output.
+// Файл, измененный вручную после прошлой генерации, не перезаписывается - рядом пишется файл с суффиксом .new (см manifest.go).
+func WriteFile(path string, data []byte) error {
+	path = manifestAddFile(cleanPath(path), data)
+	return output.WriteFile(path, data)
+}
+
+// ReadFile чтение ранее сгенерированного файла из output
+func ReadFile(path string) ([]byte, error) {
+	return output.ReadFile(path)
+}
+
+// ReadDirNames список имен файлов (без поддиректорий) в директории, отсортированный
+func ReadDirNames(path string) ([]string, error) {
+	files, err := output.ListFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	prefix := cleanPath(path) + "/"
+	res := []string{}
+	for _, f := range files {
+		name := strings.TrimPrefix(cleanPath(f), prefix)
+		if !strings.Contains(name, "/") {
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+	return res, nil
+}
+
+// RemoveAll удаление директории из output
+func RemoveAll(path string) error {
+	return output.RemoveAll(path)
+}
+
+// удаление файла и опустевших после этого родительских директорий (вплоть до root)
+func removeFileWithEmptyDirs(path, root string) error {
+	err := output.Remove(path)
+	if err != nil {
+		return err
+	}
+	root = cleanPath(root)
+	for dir := cleanPath(filepath.Dir(path)); dir != root && dir != "." && strings.HasPrefix(dir, root); dir = cleanPath(filepath.Dir(dir)) {
+		files, err := output.ListFiles(dir)
+		if err != nil || len(files) > 0 {
+			break
+		}
+		if err = output.Remove(dir); err != nil {
+			break
+		}
+	}
+	return nil
+}
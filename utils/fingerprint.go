@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"reflect"
+	"sort"
+)
+
+// Fingerprint хэш значения (описания документа, проекта и т.п.) для определения, изменилось ли оно с прошлой генерации.
+// Функции не учитываются. Указатели с типами из skipTypes не обходятся - это обратные ссылки (DocType.Project, FldType.Doc) и уже распарсенные шаблоны
+func Fingerprint(v interface{}, skipTypes ...reflect.Type) string {
+	h := sha256.New()
+	skip := map[reflect.Type]bool{}
+	for _, t := range skipTypes {
+		skip[t] = true
+	}
+	fingerprintValue(h, reflect.ValueOf(v), skip, map[uintptr]bool{})
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func fingerprintValue(h hash.Hash, v reflect.Value, skip map[reflect.Type]bool, visited map[uintptr]bool) {
+	if !v.IsValid() {
+		h.Write([]byte("nil;"))
+		return
+	}
+	switch v.Kind() {
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return
+	case reflect.Ptr:
+		if v.IsNil() || skip[v.Type()] {
+			h.Write([]byte("nil;"))
+			return
+		}
+		if visited[v.Pointer()] {
+			return
+		}
+		visited[v.Pointer()] = true
+		fingerprintValue(h, v.Elem(), skip, visited)
+		delete(visited, v.Pointer())
+	case reflect.Interface:
+		if v.IsNil() {
+			h.Write([]byte("nil;"))
+			return
+		}
+		fingerprintValue(h, v.Elem(), skip, visited)
+	case reflect.Struct:
+		h.Write([]byte(v.Type().String() + "{"))
+		for i := 0; i < v.NumField(); i++ {
+			h.Write([]byte(v.Type().Field(i).Name + ":"))
+			fingerprintValue(h, v.Field(i), skip, visited)
+		}
+		h.Write([]byte("}"))
+	case reflect.Slice, reflect.Array:
+		h.Write([]byte(fmt.Sprintf("[%v:", v.Len())))
+		for i := 0; i < v.Len(); i++ {
+			fingerprintValue(h, v.Index(i), skip, visited)
+		}
+		h.Write([]byte("]"))
+	case reflect.Map:
+		// порядок ключей в map случайный - сортируем
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface()) })
+		h.Write([]byte(fmt.Sprintf("map%v{", v.Len())))
+		for _, k := range keys {
+			h.Write([]byte(fmt.Sprint(k.Interface()) + ":"))
+			fingerprintValue(h, v.MapIndex(k), skip, visited)
+		}
+		h.Write([]byte("}"))
+	default:
+		h.Write([]byte(fmt.Sprintf("%#v;", v.Interface())))
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"github.com/serenize/snaker"
 	"log"
+	"reflect"
+	"sort"
 	"strings"
 )
 
@@ -22,6 +24,13 @@ func UpperCaseFirst(str string) string {
 	return strings.Title(str)
 }
 
+func LowerCaseFirst(str string) string {
+	if len(str) == 0 {
+		return str
+	}
+	return strings.ToLower(str[:1]) + str[1:]
+}
+
 func ParseDocTemplateFilename(docName, filename, globalDistPath string, docIndex int, params map[string]string) (distPath, distFilename string) {
 	// разбираем имя шаблона на части
 	arr := strings.Split(filename, "_")
@@ -109,4 +118,17 @@ func ByteSliceEqual(a, b []byte) bool {
 		}
 	}
 	return true
-}
\ No newline at end of file
+}
+// SortedKeys отсортированные ключи map со строковыми ключами. Используется, чтобы сгенерированный код не зависел от порядка обхода map
+func SortedKeys(m interface{}) []string {
+	v := reflect.ValueOf(m)
+	if v.Kind() != reflect.Map {
+		log.Fatalf("SortedKeys: expected map, got %T", m)
+	}
+	res := make([]string, 0, v.Len())
+	for _, k := range v.MapKeys() {
+		res = append(res, k.String())
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,232 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// имя файла манифеста, который лежит в корне сгенерированного проекта
+const ManifestFilename = ".nla_manifest.json"
+
+type (
+	// манифест - список всех сгенерированных файлов с хэшами содержимого.
+	// Нужен чтобы удалять файлы, которые больше не генерируются, и не затирать файлы, измененные вручную
+	Manifest struct {
+		Files        map[string]string   `json:"files"`                  // путь относительно корня проекта - sha256 содержимого
+		Groups       map[string][]string `json:"groups,omitempty"`       // файлы, сгенерированные для документа (ключ - имя документа)
+		Fingerprints map[string]string   `json:"fingerprints,omitempty"` // хэши описания проекта и документов. Нужны для инкрементальной генерации
+		NewFiles     []string            `json:"newFiles,omitempty"`     // файлы .new рядом с файлами, измененными вручную. Удаляются, когда конфликт разрешен
+	}
+
+	manifestState struct {
+		root          string
+		isForce       bool
+		isIncremental bool // генерируется только часть файлов. Остальные файлы из прошлого манифеста сохраняются
+		group         string
+		regenerated   map[string]bool // группы, файлы которых записаны в текущей генерации
+		prev          Manifest
+		current       Manifest
+		handEdited    []string // файлы, измененные вручную, для которых сгенерирован .new
+	}
+)
+
+// состояние манифеста на время генерации. Если root пустой, то манифест не ведется
+var manifest manifestState
+
+// StartManifest начало генерации: читаем манифест прошлой генерации из root.
+// isForce - перезаписывать файлы, даже если они были изменены вручную
+func StartManifest(root string, isForce bool) error {
+	manifest = manifestState{root: filepath.Clean(root), isForce: isForce, current: Manifest{Files: map[string]string{}, Groups: map[string][]string{}}, regenerated: map[string]bool{}}
+	data, err := output.ReadFile(filepath.Join(root, ManifestFilename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return json.Unmarshal(data, &manifest.prev)
+}
+
+// SetManifestIncremental генерация, при которой перезаписывается только часть файлов (см SetManifestGroup).
+// Файлы прошлой генерации, которые не были перезаписаны, остаются в манифесте. Удаляются только устаревшие файлы перегенерированных групп
+func SetManifestIncremental() {
+	manifest.isIncremental = true
+}
+
+// SetManifestGroup все файлы, записанные после вызова, относятся к группе (документу). Пустая строка - файлы без группы
+func SetManifestGroup(group string) {
+	manifest.group = group
+	if len(group) > 0 && len(manifest.root) > 0 {
+		manifest.current.Groups[group] = []string{}
+		manifest.regenerated[group] = true
+	}
+}
+
+// ManifestFingerprints хэши описания проекта на момент прошлой генерации
+func ManifestFingerprints() map[string]string {
+	return manifest.prev.Fingerprints
+}
+
+// SetManifestFingerprints хэши описания проекта, которые сохраняются в манифест
+func SetManifestFingerprints(f map[string]string) {
+	manifest.current.Fingerprints = f
+}
+
+// IsManifestExist признак, что есть манифест от прошлой генерации
+func IsManifestExist() bool {
+	return manifest.prev.Files != nil
+}
+
+// FinishManifest окончание генерации: удаляем файлы, которые были в прошлом манифесте, но больше не генерируются,
+// и сохраняем новый манифест. Возвращает список удаленных (в dry run - планируемых к удалению) файлов
+func FinishManifest() (removed []string, err error) {
+	if len(manifest.root) == 0 {
+		return
+	}
+	// при инкрементальной генерации файл прошлой генерации считается устаревшим, только если его группа была перегенерирована
+	prevGroups := map[string]string{}
+	if manifest.isIncremental {
+		for group, files := range manifest.prev.Groups {
+			if !manifest.regenerated[group] {
+				manifest.current.Groups[group] = files
+			}
+			for _, name := range files {
+				prevGroups[name] = group
+			}
+		}
+	}
+	// .new файлы прошлой генерации, конфликт по которым разрешен: файл перезаписан без конфликта или больше не генерируется
+	for _, newName := range manifest.prev.NewFiles {
+		if CheckContainsSliceStr(newName, manifest.current.NewFiles...) {
+			continue
+		}
+		name := strings.TrimSuffix(newName, ".new")
+		if _, isWritten := manifest.current.Files[name]; !isWritten && manifest.isIncremental {
+			if group, ok := prevGroups[name]; !ok || !manifest.regenerated[group] {
+				manifest.current.NewFiles = append(manifest.current.NewFiles, newName)
+				continue
+			}
+		}
+		path := manifest.fullPath(newName)
+		if _, err := output.ReadFile(path); err != nil {
+			continue // файл уже удален вручную
+		}
+		if err = removeFileWithEmptyDirs(path, manifest.root); err != nil {
+			return removed, err
+		}
+		removed = append(removed, path)
+	}
+	for _, name := range sortedMapKeys(manifest.prev.Files) {
+		if _, ok := manifest.current.Files[name]; ok {
+			continue
+		}
+		if manifest.isIncremental {
+			if group, ok := prevGroups[name]; !ok || !manifest.regenerated[group] {
+				manifest.current.Files[name] = manifest.prev.Files[name]
+				continue
+			}
+		}
+		path := manifest.fullPath(name)
+		data, err := output.ReadFile(path)
+		if err != nil {
+			continue // файла уже нет
+		}
+		// файл изменен вручную - оставляем его
+		if !manifest.isForce && fileHash(data) != manifest.prev.Files[name] {
+			manifest.handEdited = append(manifest.handEdited, path)
+			continue
+		}
+		if err = removeFileWithEmptyDirs(path, manifest.root); err != nil {
+			return removed, err
+		}
+		removed = append(removed, path)
+	}
+	if isDryRun {
+		return
+	}
+	// порядок записи файлов группы зависит от шаблонов - сортируем, чтобы манифест не менялся без причины
+	for _, files := range manifest.current.Groups {
+		sort.Strings(files)
+	}
+	sort.Strings(manifest.current.NewFiles)
+	data, err := json.MarshalIndent(manifest.current, "", "\t")
+	if err != nil {
+		return
+	}
+	err = output.WriteFile(filepath.Join(manifest.root, ManifestFilename), data)
+	return
+}
+
+// ManifestHandEditedFiles список файлов, которые были изменены вручную и поэтому не перезаписаны/не удалены
+func ManifestHandEditedFiles() []string {
+	return manifest.handEdited
+}
+
+// регистрируем сгенерированный файл в манифесте. Возвращает путь, по которому надо записать файл:
+// если файл был изменен вручную после прошлой генерации, то пишем рядом файл с суффиксом .new
+func manifestAddFile(path string, data []byte) string {
+	if len(manifest.root) == 0 {
+		return path
+	}
+	name := manifest.relPath(path)
+	hash := fileHash(data)
+	manifest.current.Files[name] = hash
+	if len(manifest.group) > 0 {
+		manifest.current.Groups[manifest.group] = append(manifest.current.Groups[manifest.group], name)
+	}
+	if manifest.isForce {
+		return path
+	}
+	prevHash, ok := manifest.prev.Files[name]
+	if !ok {
+		return path
+	}
+	existFile, err := output.ReadFile(path)
+	if err != nil {
+		return path
+	}
+	existHash := fileHash(existFile)
+	if existHash == prevHash || existHash == hash {
+		return path
+	}
+	// в манифесте оставляем старый хэш, чтобы файл считался измененным вручную и в следующий раз
+	manifest.current.Files[name] = prevHash
+	manifest.handEdited = append(manifest.handEdited, path)
+	if !CheckContainsSliceStr(name+".new", manifest.current.NewFiles...) {
+		manifest.current.NewFiles = append(manifest.current.NewFiles, name+".new")
+	}
+	return path + ".new"
+}
+
+func (m manifestState) relPath(path string) string {
+	if rel, err := filepath.Rel(m.root, path); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
+
+func (m manifestState) fullPath(name string) string {
+	if strings.HasPrefix(name, "/") {
+		return name
+	}
+	return filepath.ToSlash(filepath.Join(m.root, name))
+}
+
+func fileHash(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
+
+func sortedMapKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
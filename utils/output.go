@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type (
+	// Output - куда записываются сгенерированные файлы. Пути передаются уже с учетом корня проекта (ProjectType.Generator.OutputRoot)
+	Output interface {
+		ReadFile(path string) ([]byte, error)
+		WriteFile(path string, data []byte) error
+		Remove(path string) error               // удаление файла или пустой директории
+		RemoveAll(path string) error            // удаление директории со всем содержимым
+		ListFiles(dir string) ([]string, error) // рекурсивный отсортированный список файлов в директории
+	}
+
+	// запись файлов на диск
+	DirOutput struct{}
+
+	// запись файлов в память. Если указан Base, то чтение файлов, которых нет в памяти, идет из Base (например, с диска)
+	MemOutput struct {
+		Files   map[string][]byte
+		Base    Output
+		deleted map[string]bool // файлы и директории из Base, которые считаются удаленными
+	}
+)
+
+func (DirOutput) ReadFile(path string) ([]byte, error) {
+	return ioutil.ReadFile(path)
+}
+
+func (DirOutput) WriteFile(path string, data []byte) error {
+	// для оптимизации (например, чтобы не рестартовал quasar) неизмененный файл не перезаписываем
+	if existFile, err := ioutil.ReadFile(path); err == nil && ByteSliceEqual(existFile, data) {
+		return nil
+	}
+	err := os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+func (DirOutput) Remove(path string) error {
+	return os.Remove(path)
+}
+
+func (DirOutput) RemoveAll(path string) error {
+	return os.RemoveAll(path)
+}
+
+func (DirOutput) ListFiles(dir string) ([]string, error) {
+	res := []string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			res = append(res, filepath.ToSlash(path))
+		}
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return res, nil
+	}
+	sort.Strings(res)
+	return res, err
+}
+
+func NewMemOutput(base Output) *MemOutput {
+	return &MemOutput{Files: map[string][]byte{}, Base: base, deleted: map[string]bool{}}
+}
+
+func (m *MemOutput) ReadFile(path string) ([]byte, error) {
+	path = cleanPath(path)
+	if data, ok := m.Files[path]; ok {
+		return data, nil
+	}
+	if m.Base == nil || m.isDeleted(path) {
+		return nil, &os.PathError{Op: "open", Path: path, Err: os.ErrNotExist}
+	}
+	return m.Base.ReadFile(path)
+}
+
+func (m *MemOutput) WriteFile(path string, data []byte) error {
+	path = cleanPath(path)
+	m.Files[path] = data
+	delete(m.deleted, path)
+	return nil
+}
+
+func (m *MemOutput) Remove(path string) error {
+	path = cleanPath(path)
+	delete(m.Files, path)
+	m.deleted[path] = true
+	return nil
+}
+
+func (m *MemOutput) RemoveAll(path string) error {
+	path = cleanPath(path)
+	for k := range m.Files {
+		if k == path || strings.HasPrefix(k, path+"/") {
+			delete(m.Files, k)
+		}
+	}
+	m.deleted[path] = true
+	return nil
+}
+
+func (m *MemOutput) ListFiles(dir string) ([]string, error) {
+	dir = cleanPath(dir)
+	names := map[string]bool{}
+	if m.Base != nil {
+		list, err := m.Base.ListFiles(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range list {
+			if !m.isDeleted(cleanPath(v)) {
+				names[cleanPath(v)] = true
+			}
+		}
+	}
+	for k := range m.Files {
+		if strings.HasPrefix(k, dir+"/") {
+			names[k] = true
+		}
+	}
+	res := []string{}
+	for k := range names {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res, nil
+}
+
+// проверка что файл или одна из его родительских директорий удалены
+func (m *MemOutput) isDeleted(path string) bool {
+	for p := path; p != "." && p != "/" && len(p) > 0; p = filepath.ToSlash(filepath.Dir(p)) {
+		if m.deleted[p] {
+			return true
+		}
+		if p == filepath.ToSlash(filepath.Dir(p)) {
+			break
+		}
+	}
+	return false
+}
+
+func cleanPath(path string) string {
+	return filepath.ToSlash(filepath.Clean(path))
+}
@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// префикс путей к файлам фреймворка (templates, sourceFiles, webClient...). Такие файлы читаются из FrameworkFS, а не с диска.
+// Все остальные пути (шаблоны документов, OverridePathForTemplates, TemplatePathOverride) читаются с диска как раньше
+const FrameworkPathPrefix = "nla_framework:"
+
+// файлы фреймворка. Заполняется из корневого пакета через embed.FS
+var frameworkFS fs.FS
+
+// SetFrameworkFS файловая система с файлами фреймворка
+func SetFrameworkFS(f fs.FS) {
+	frameworkFS = f
+}
+
+// FrameworkPath путь к файлу фреймворка относительно корня фреймворка. Например FrameworkPath("templates/sql/main.toml")
+func FrameworkPath(p string) string {
+	return FrameworkPathPrefix + "/" + strings.TrimPrefix(p, "/")
+}
+
+// путь внутри FrameworkFS. Второй параметр - признак, что это файл фреймворка
+func frameworkRelPath(p string) (string, bool) {
+	if !strings.HasPrefix(p, FrameworkPathPrefix) {
+		return p, false
+	}
+	rel := path.Clean(strings.TrimPrefix(strings.TrimPrefix(p, FrameworkPathPrefix), "/"))
+	return rel, true
+}
+
+// ReadSourceFile чтение исходного файла (шаблона): либо из файлов фреймворка, либо с диска
+func ReadSourceFile(p string) ([]byte, error) {
+	if rel, ok := frameworkRelPath(p); ok {
+		return fs.ReadFile(frameworkFS, rel)
+	}
+	return ioutil.ReadFile(p)
+}
+
+// ListSourceFiles рекурсивный список исходных файлов в директории. Пути возвращаются с разделителем '/'
+func ListSourceFiles(dir string) ([]string, error) {
+	res := []string{}
+	if rel, ok := frameworkRelPath(dir); ok {
+		err := fs.WalkDir(frameworkFS, rel, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				res = append(res, FrameworkPath(p))
+			}
+			return nil
+		})
+		return res, err
+	}
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			res = append(res, filepath.ToSlash(p))
+		}
+		return nil
+	})
+	sort.Strings(res)
+	return res, err
+}
+
+// ParseTemplateFiles аналог template.ParseFiles, который умеет читать файлы фреймворка из FrameworkFS
+func ParseTemplateFiles(t *template.Template, filenames ...string) (*template.Template, error) {
+	for _, filename := range filenames {
+		b, err := ReadSourceFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		name := path.Base(filepath.ToSlash(filename))
+		var tmpl *template.Template
+		if name == t.Name() {
+			tmpl = t
+		} else {
+			tmpl = t.New(name)
+		}
+		if _, err = tmpl.Parse(string(b)); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
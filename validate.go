@@ -0,0 +1,393 @@
+package nla_framework
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/NL-A/nla_framework/templates"
+	"github.com/NL-A/nla_framework/types"
+	"github.com/NL-A/nla_framework/utils"
+	"github.com/spf13/cast"
+)
+
+// имена служебных колонок, которые генератор добавляет в таблицу документа
+var reservedFldNames = []string{"id", "options", "created_at", "updated_at", "deleted", "search_text", "search_vector"}
+
+// ValidateProject проверка описания проекта. Возвращает полный список найденных ошибок, а не только первую
+func ValidateProject(p types.ProjectType) []types.ValidationError {
+	res := []types.ValidationError{}
+	addErr := func(docName, fldName, path, msg string, args ...interface{}) {
+		res = append(res, types.ValidationError{Doc: docName, Fld: fldName, Path: path, Msg: fmt.Sprintf(msg, args...)})
+	}
+
+	// ошибки, которые накопились при сборке описания (d.Fld, SetRowCol и пр)
+	res = append(res, types.BuildErrors()...)
+
+	// проверяем что название проекта без пробелов
+	if strings.Contains(p.Name, " ") {
+		addErr("", "", "Name", "wrong project name: '%s'. Remove spaces.", p.Name)
+	}
+	// проверяем что если авторизация через email, то должны быть заполнены необходимые поля
+	if p.Config.Auth.ByEmail {
+		if len(p.Config.Email.Sender) == 0 || len(p.Config.Email.Host) == 0 || p.Config.Email.Port == 0 {
+			addErr("", "", "Config.Email", "in Config.Email fill fields: 'Sender', 'Host', 'Port'")
+		}
+	}
+
+	// роли проекта. admin есть всегда
+	projectRoles := map[string]bool{"admin": true}
+	for _, r := range p.Roles {
+		projectRoles[r.Name] = true
+	}
+
+	docNames := map[string]bool{}
+	// документы с колонкой title. list и get_by_id берут title записи, на которую ссылается поле (см RefTitleName)
+	docTitles := map[string]bool{}
+	tableNames := map[string]bool{}
+	for _, d := range p.Docs {
+		tableNames[d.PgName()] = true
+		if len(d.Name) == 0 {
+			addErr("", "", "Docs", "doc with empty name")
+			continue
+		}
+		if docNames[d.Name] {
+			addErr(d.Name, "", fmt.Sprintf("Docs[%s]", d.Name), "duplicate doc name")
+		}
+		docNames[d.Name] = true
+		for _, fld := range d.Flds {
+			if fld.Name == "title" && !fld.Sql.IsOptionFld {
+				docTitles[d.Name] = true
+			}
+		}
+	}
+
+	for _, d := range p.Docs {
+		docPath := fmt.Sprintf("Docs[%s]", d.Name)
+		fldNames := map[string]bool{}
+		rowCols := map[string]string{}
+		for _, fld := range d.Flds {
+			fldPath := fmt.Sprintf("%s.Flds[%s]", docPath, fld.Name)
+			if len(fld.Name) > 0 {
+				if fldNames[fld.Name] {
+					addErr(d.Name, fld.Name, fldPath, "duplicate field name")
+				}
+				fldNames[fld.Name] = true
+			}
+			// проверяем чтобы не было поля user_id, потому что это служебное поле
+			if fld.Name == "user_id" {
+				addErr(d.Name, fld.Name, fldPath, "field with name 'user_id' is not allowed. Rename field.")
+			}
+			// служебные колонки таблицы (см PrintSqlModelFlds). Поля из options отдельных колонок не создают
+			if !fld.Sql.IsOptionFld && utils.CheckContainsSliceStr(fld.Name, reservedFldNames...) {
+				addErr(d.Name, fld.Name, fldPath, "field name '%s' is reserved for system column. Rename field.", fld.Name)
+			}
+			// ссылка на несуществующий документ
+			if len(fld.Sql.Ref) > 0 && !docNames[fld.Sql.Ref] && !utils.CheckContainsSliceStr(fld.Sql.Ref, types.SystemTables...) {
+				addErr(d.Name, fld.Name, fldPath+".Sql.Ref", "reference to unknown doc '%s'", fld.Sql.Ref)
+			} else if docNames[fld.Sql.Ref] && !docTitles[fld.Sql.Ref] {
+				addErr(d.Name, fld.Name, fldPath+".Sql.Ref", "referenced doc '%s' has no field 'title'. Add t.GetFldTitle() to doc '%[1]s'", fld.Sql.Ref)
+			}
+			// расположение поля в сетке
+			if len(fld.Vue.RowCol) > 0 {
+				isRowColValid := true
+				for _, rc := range fld.Vue.RowCol {
+					if len(rc) < 2 || rc[0] < 1 || rc[1] < 1 {
+						addErr(d.Name, fld.Name, fldPath+".Vue.RowCol", "wrong RowCol %v. Row and col must be positive numbers", fld.Vue.RowCol)
+						isRowColValid = false
+						break
+					}
+				}
+				if isRowColValid {
+					key := fmt.Sprintf("%v", fld.Vue.RowCol)
+					if prevFld, ok := rowCols[key]; ok {
+						addErr(d.Name, fld.Name, fldPath+".Vue.RowCol", "RowCol %v already used by field '%s'", fld.Vue.RowCol, prevFld)
+					} else {
+						rowCols[key] = fld.Name
+					}
+				}
+			}
+			// значения для select/radio
+			optionValues := map[string]bool{}
+			for i, v := range fld.Vue.Options {
+				optPath := fmt.Sprintf("%s.Vue.Options[%v]", fldPath, i)
+				value := cast.ToString(v.Value)
+				if len(value) == 0 {
+					addErr(d.Name, fld.Name, optPath, "option '%s' has empty value", v.Label)
+					continue
+				}
+				if strings.Contains(value, " ") {
+					addErr(d.Name, fld.Name, optPath, "option value '%s' contains spaces. Remove spaces from value.", value)
+				}
+				if optionValues[value] {
+					addErr(d.Name, fld.Name, optPath, "duplicate option value '%s'", value)
+				}
+				optionValues[value] = true
+			}
+			// вес в полнотекстовом поиске
+			if len(fld.Sql.SearchWeight) > 0 && !utils.CheckContainsSliceStr(fld.Sql.SearchWeight, "A", "B", "C", "D") {
+				addErr(d.Name, fld.Name, fldPath+".Sql.SearchWeight", "wrong search weight '%s'. Use A, B, C or D", fld.Sql.SearchWeight)
+			}
+			// роли доступа к полю
+			for _, role := range append(append([]string{}, fld.Roles.Read...), fld.Roles.Write...) {
+				if len(p.Roles) > 0 && !projectRoles[role] {
+					addErr(d.Name, fld.Name, fldPath+".Roles", "unknown role '%s'", role)
+				}
+			}
+			// поля внутри options хранятся в одной колонке, поэтому в sql роли для них не проверяются
+			if fld.Sql.IsOptionFld && len(fld.Roles.Read)+len(fld.Roles.Write) > 0 {
+				addErr(d.Name, fld.Name, fldPath+".Roles", "roles are not supported for option field. Make it a table column to restrict access")
+			}
+			// проверка что если документ - это уникальная связь двух таблиц, то в нем поле title если есть, то не должно быть уникальным
+			if d.Sql.IsUniqLink && fld.Name == "title" && fld.Sql.IsUniq {
+				addErr(d.Name, fld.Name, fldPath+".Sql.IsUniq", "field 'title' must be not uniq. Remove fld 'title' or t.GetFldTitle().SetIsNotUniq()")
+			}
+		}
+
+		// прежнее название не должно совпадать с текущим, иначе миграция переименует существующую колонку или таблицу
+		for _, fld := range d.Flds {
+			for _, prevName := range fld.Sql.PrevNames {
+				if fldNames[prevName] {
+					addErr(d.Name, fld.Name, fmt.Sprintf("%s.Flds[%s].Sql.PrevNames", docPath, fld.Name), "prev name '%s' is used by existing field", prevName)
+				}
+			}
+		}
+		// индексы и ограничения документа задаются строкой и при переименовании колонки сами не меняются
+		for _, fld := range d.Flds {
+			for _, prevName := range fld.Sql.PrevNames {
+				if fldNames[prevName] {
+					continue
+				}
+				re := regexp.MustCompile(`\b` + regexp.QuoteMeta(prevName) + `\b`)
+				for i, v := range d.Sql.Indexes {
+					if re.MatchString(v) {
+						addErr(d.Name, fld.Name, fmt.Sprintf("%s.Sql.Indexes[%v]", docPath, i), "index uses prev name '%s' of field '%s'. Rename it to '%[2]s'", prevName, fld.Name)
+					}
+				}
+				for _, c := range d.Sql.CheckConstrains {
+					if re.MatchString(c.CheckConditions) {
+						addErr(d.Name, fld.Name, fmt.Sprintf("%s.Sql.CheckConstrains[%s]", docPath, c.Name), "check constraint uses prev name '%s' of field '%s'. Rename it to '%[2]s'", prevName, fld.Name)
+					}
+				}
+				for _, c := range d.Sql.UniqConstrains {
+					if re.MatchString(c.UniqConditions) {
+						addErr(d.Name, fld.Name, fmt.Sprintf("%s.Sql.UniqConstrains[%s]", docPath, c.Name), "uniq constraint uses prev name '%s' of field '%s'. Rename it to '%[2]s'", prevName, fld.Name)
+					}
+				}
+			}
+		}
+		for _, prevName := range d.Sql.PrevNames {
+			if tableNames[prevName] {
+				addErr(d.Name, "", docPath+".Sql.PrevNames", "prev name '%s' is used by existing doc", prevName)
+			}
+		}
+
+		// полнотекстовый поиск строится по полям с IsSearch
+		if d.Sql.IsFullTextSearch {
+			isSearchFld := false
+			for _, fld := range d.Flds {
+				if fld.Sql.IsSearch {
+					isSearchFld = true
+				}
+			}
+			if !isSearchFld {
+				addErr(d.Name, "", docPath+".Sql.IsFullTextSearch", "full text search requires at least one field with IsSearch")
+			}
+		}
+
+		// фильтры списка: вид фильтра должен подходить к типу поля
+		filterKinds := []string{types.VueDocListFilterKindDateRange, types.VueDocListFilterKindNumberRange, types.VueDocListFilterKindIn, types.VueDocListFilterKindNotIn,
+			types.VueDocListFilterKindIsNull, types.VueDocListFilterKindRefMulti, types.VueDocListFilterKindArrayContains, types.VueDocListFilterKindOption}
+		for i, filter := range d.Vue.FilterList {
+			filterPath := fmt.Sprintf("%s.Vue.FilterList[%v]", docPath, i)
+			if len(filter.Kind) == 0 {
+				continue
+			}
+			if !utils.CheckContainsSliceStr(filter.Kind, filterKinds...) {
+				addErr(d.Name, filter.FldName, filterPath+".Kind", "unknown filter kind '%s'", filter.Kind)
+				continue
+			}
+			var fld *types.FldType
+			for j := range d.Flds {
+				if d.Flds[j].Name == filter.FldName {
+					fld = &d.Flds[j]
+				}
+			}
+			if fld == nil {
+				addErr(d.Name, filter.FldName, filterPath+".FldName", "unknown field '%s'", filter.FldName)
+				continue
+			}
+			switch filter.Kind {
+			case types.VueDocListFilterKindDateRange:
+				if !utils.CheckContainsSliceStr(fld.Type, types.FldTypeDate, types.FldTypeDatetime) {
+					addErr(d.Name, fld.Name, filterPath+".Kind", "filter '%s' requires date or datetime field", filter.Kind)
+				}
+			case types.VueDocListFilterKindNumberRange:
+				if !utils.CheckContainsSliceStr(fld.Type, types.FldTypeInt, types.FldTypeInt64, types.FldTypeDouble) {
+					addErr(d.Name, fld.Name, filterPath+".Kind", "filter '%s' requires int, int64 or double field", filter.Kind)
+				}
+			case types.VueDocListFilterKindIn, types.VueDocListFilterKindNotIn:
+				if len(filter.Options) == 0 && fld.Vue.Type != types.FldVueTypeSelect {
+					addErr(d.Name, fld.Name, filterPath+".Options", "filter '%s' requires Options or select field", filter.Kind)
+				}
+			case types.VueDocListFilterKindRefMulti:
+				if len(fld.Sql.Ref) == 0 {
+					addErr(d.Name, fld.Name, filterPath+".Kind", "filter '%s' requires ref field", filter.Kind)
+				}
+				if len(filter.RefTable) == 0 {
+					addErr(d.Name, fld.Name, filterPath+".RefTable", "ref table is empty")
+				}
+			case types.VueDocListFilterKindArrayContains:
+				if fld.Type != types.FldTypeTextArray {
+					addErr(d.Name, fld.Name, filterPath+".Kind", "filter '%s' requires text[] field", filter.Kind)
+				}
+			case types.VueDocListFilterKindOption:
+				if !fld.Sql.IsOptionFld {
+					addErr(d.Name, fld.Name, filterPath+".Kind", "filter '%s' requires field with IsOptionFld", filter.Kind)
+				}
+			}
+		}
+
+		// правила доступа к записям: поля должны быть в документе, роли и стейты - объявлены в проекте
+		for _, role := range utils.SortedKeys(d.Sql.AccessRules) {
+			rule := d.Sql.AccessRules[role]
+			rulePath := fmt.Sprintf("%s.Sql.AccessRules[%s]", docPath, role)
+			if len(p.Roles) > 0 && !projectRoles[role] {
+				addErr(d.Name, "", rulePath, "unknown role '%s'", role)
+			}
+			if len(rule.OwnerFld) > 0 && !fldNames[rule.OwnerFld] {
+				addErr(d.Name, "", rulePath+".OwnerFld", "unknown field '%s'", rule.OwnerFld)
+			}
+			if rule.Department != nil {
+				// id - для документов, которые сами связаны с пользователем (например, клиент через таблицу менеджеров клиента)
+				if !fldNames[rule.Department.Fld] && rule.Department.Fld != "id" {
+					addErr(d.Name, "", rulePath+".Department.Fld", "unknown field '%s'", rule.Department.Fld)
+				}
+				if len(rule.Department.LinkTable) == 0 {
+					addErr(d.Name, "", rulePath+".Department.LinkTable", "link table is empty")
+				}
+			}
+			smStates := map[string]bool{}
+			if d.StateMachine != nil {
+				for _, st := range d.StateMachine.States {
+					smStates[st.Title] = true
+				}
+			}
+			for _, st := range rule.States {
+				if !smStates[st] {
+					addErr(d.Name, "", rulePath+".States", "unknown state '%s'", st)
+				}
+			}
+		}
+
+		// шаблоны для табов ищутся так же, как в templates.ParseTemplates: среди общих шаблонов, шаблонов документа
+		// и шаблонов, переопределенных на уровне проекта
+		commonTabTmpls := templates.CommonTabTmplNames(p)
+		for i, tab := range d.Vue.Tabs {
+			if _, ok := d.Templates["webClient_"+tab.TmplName]; ok {
+				continue
+			}
+			if _, ok := p.OverridePathForTemplates[templates.TabDistPath(d, tab)+"/index.vue"]; ok {
+				continue
+			}
+			if !utils.CheckContainsSliceStr(tab.TmplName, commonTabTmpls...) {
+				addErr(d.Name, "", fmt.Sprintf("%s.Vue.Tabs[%v]", docPath, i), "template not found for tab '%s': '%s'", tab.Title, tab.TmplName)
+			}
+		}
+
+		// state machine: переходы должны ссылаться на объявленные состояния
+		if d.StateMachine != nil {
+			states := map[string]bool{}
+			for i, st := range d.StateMachine.States {
+				if states[st.Title] {
+					addErr(d.Name, "", fmt.Sprintf("%s.StateMachine.States[%v]", docPath, i), "duplicate state '%s'", st.Title)
+				}
+				states[st.Title] = true
+			}
+			transitions := map[string]bool{}
+			hasExit := map[string]bool{}
+			for i, st := range d.StateMachine.States {
+				for j, actn := range st.Actions {
+					actnPath := fmt.Sprintf("%s.StateMachine.States[%v].Actions[%v]", docPath, i, j)
+					if !states[actn.To] {
+						addErr(d.Name, "", actnPath+".To", "action '%s' of state '%s' points to unknown state '%s'", actn.Label, st.Title, actn.To)
+					}
+					if len(actn.From) > 0 && !states[actn.From] {
+						addErr(d.Name, "", actnPath+".From", "action '%s' of state '%s' points from unknown state '%s'", actn.Label, st.Title, actn.From)
+					}
+					// по паре from_to формируется название экшена в action.sql и файл кнопки - дубль перезатрет переход
+					key := st.ActionFrom(actn) + "_to_" + actn.To
+					if transitions[key] {
+						addErr(d.Name, "", actnPath, "duplicate action '%s' -> '%s'", st.ActionFrom(actn), actn.To)
+					}
+					transitions[key] = true
+					hasExit[st.ActionFrom(actn)] = true
+				}
+			}
+			// в состояние должен вести хотя бы один переход из начального. Состояние без переходов считается конечным (см DocSm.FinalStates),
+			// а у отмеченного конечным переходов быть не должно
+			reachable := d.StateMachine.ReachableStates()
+			for i, st := range d.StateMachine.States {
+				stPath := fmt.Sprintf("%s.StateMachine.States[%v]", docPath, i)
+				if !reachable[st.Title] {
+					addErr(d.Name, "", stPath, "state '%s' is unreachable from initial state '%s'", st.Title, d.StateMachine.GetFirstState().Title)
+				}
+				if hasExit[st.Title] && st.IsFinal {
+					addErr(d.Name, "", stPath+".IsFinal", "final state '%s' has actions. Remove actions or IsFinal", st.Title)
+				}
+			}
+		}
+	}
+
+	// боковое меню
+	for i, m := range p.Vue.Menu {
+		if len(m.DocName) > 0 && !docNames[m.DocName] {
+			addErr(m.DocName, "", fmt.Sprintf("Vue.Menu[%v].DocName", i), "menu item points to unknown doc '%s'", m.DocName)
+		}
+		for j, m1 := range m.LinkList {
+			if len(m1.DocName) > 0 && !docNames[m1.DocName] {
+				addErr(m1.DocName, "", fmt.Sprintf("Vue.Menu[%v].LinkList[%v].DocName", i, j), "menu item points to unknown doc '%s'", m1.DocName)
+			}
+		}
+	}
+
+	// содержимое добавляется только в зарегистрированные слоты
+	res = append(res, p.ValidateSlots()...)
+
+	// проверки плагинов генератора
+	for _, pl := range types.Plugins() {
+		res = append(res, pl.Validate(p)...)
+	}
+
+	return res
+}
+
+// Validate проверка проекта с печатью полного отчета об ошибках. Возвращает false, если есть ошибки
+func Validate(p types.ProjectType) bool {
+	setDefaults(&p)
+	errs := ValidateProject(p)
+	if len(errs) == 0 {
+		fmt.Printf("project '%s' is valid\n", p.Name)
+		return true
+	}
+	printValidationErrors(p, errs)
+	return false
+}
+
+// проверка проекта перед генерацией. Если есть ошибки, то печатаем полный отчет и завершаем работу
+func validateOrExit(p types.ProjectType) {
+	errs := ValidateProject(p)
+	if len(errs) == 0 {
+		return
+	}
+	printValidationErrors(p, errs)
+	log.Fatalf("project validation failed")
+}
+
+func printValidationErrors(p types.ProjectType, errs []types.ValidationError) {
+	fmt.Printf("project '%s' has %v error(s):\n", p.Name, len(errs))
+	for i, e := range errs {
+		fmt.Printf("%3d. %s\n", i+1, e.Error())
+	}
+}
@@ -0,0 +1,160 @@
+package nla_framework
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/NL-A/nla_framework/types"
+	"github.com/NL-A/nla_framework/utils"
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	CommandWatch = "watch"
+	// код выхода проекта, описанного в go коде, в режиме watch: файлы изменились, проект надо перезапустить (см cmd/nla)
+	WatchRestartExitCode = 3
+	// изменения, которые пришли в течение этого времени, обрабатываются одной генерацией (редакторы пишут файл в несколько приемов)
+	watchDebounce = 200 * time.Millisecond
+)
+
+// Watch генерация проекта в режиме наблюдения: после первой генерации ждем изменения описания проекта, шаблонов документов
+// и переопределенных шаблонов (OverridePathForTemplates, TemplatePathOverride), и перегенерируем только измененные документы
+// и зависящие от них файлы проекта (роуты, меню, i18n, apiCallPgFunc.go). load вызывается перед каждой генерацией
+func Watch(load func() (types.ProjectType, error), modifyFunc copyFileModifyFunc) error {
+	for {
+		types.ResetBuildErrors()
+		p, err := load()
+		if err != nil {
+			fmt.Println(err)
+		}
+		if err = watchOnce(p, modifyFunc, err == nil); err != nil {
+			return err
+		}
+	}
+}
+
+// инкрементальная генерация и ожидание следующего изменения. Ошибки в описании проекта печатаются, но не прерывают наблюдение
+func watchOnce(p types.ProjectType, modifyFunc copyFileModifyFunc, isGenerate bool) error {
+	if isGenerate {
+		setDefaults(&p)
+		if errs := ValidateProject(p); len(errs) > 0 {
+			printValidationErrors(p, errs)
+		} else {
+			_, stat := generateFiles(p, modifyFunc, true)
+			stat.print()
+		}
+	}
+	fmt.Println("watching for changes...")
+	outputRoot := p.Generator.OutputRoot
+	if len(outputRoot) == 0 {
+		outputRoot = types.DefaultOutputRoot
+	}
+	changed, err := waitForChanges(watchDirs(p), outputRoot)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("changed: %s\n", strings.Join(changed, ", "))
+	return nil
+}
+
+// WaitForChanges ожидание изменения файлов в директории с описанием проекта. Возвращает список измененных файлов
+func WaitForChanges(dir string) ([]string, error) {
+	return waitForChanges([]string{dir}, filepath.Join(dir, types.DefaultOutputRoot))
+}
+
+// директории для наблюдения: текущая директория (описание проекта и папки tmpl документов) рекурсивно,
+// директории шаблонов, которые лежат за ее пределами, и NLA_FRAMEWORK_DIR, если файлы фреймворка читаются с диска
+func watchDirs(p types.ProjectType) []string {
+	res := []string{"."}
+	if dir := os.Getenv("NLA_FRAMEWORK_DIR"); len(dir) > 0 {
+		res = append(res, dir)
+	}
+	sources := []string{}
+	for _, path := range p.OverridePathForTemplates {
+		sources = append(sources, path)
+	}
+	for _, d := range p.Docs {
+		for _, t := range d.Templates {
+			sources = append(sources, t.Source)
+		}
+		for _, t := range d.TemplatePathOverride {
+			sources = append(sources, t.Source)
+		}
+	}
+	for _, path := range sources {
+		if len(path) == 0 || strings.HasPrefix(path, utils.FrameworkPathPrefix) {
+			continue
+		}
+		res = append(res, filepath.Dir(path))
+	}
+	return res
+}
+
+// ожидание изменения файлов в директориях (рекурсивно). Скрытые директории и корень генерируемого проекта не отслеживаются.
+// Возвращает список измененных файлов
+func waitForChanges(dirs []string, outputRoot string) ([]string, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	defer watcher.Close()
+
+	skipDir, _ := filepath.Abs(outputRoot)
+	watched := map[string]bool{}
+	for _, dir := range dirs {
+		err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil || !info.IsDir() {
+				return nil
+			}
+			abs, _ := filepath.Abs(path)
+			if abs == skipDir || (path != dir && strings.HasPrefix(info.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			if watched[abs] {
+				return nil
+			}
+			watched[abs] = true
+			return watcher.Add(path)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	changed := map[string]bool{}
+	var timer <-chan time.Time
+	for {
+		select {
+		case e, ok := <-watcher.Events:
+			if !ok {
+				return nil, fmt.Errorf("watcher closed")
+			}
+			// смена прав и временные файлы редакторов не считаются изменением
+			if e.Op == fsnotify.Chmod || strings.HasPrefix(filepath.Base(e.Name), ".") || strings.HasSuffix(e.Name, "~") {
+				continue
+			}
+			changed[e.Name] = true
+			timer = time.After(watchDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil, fmt.Errorf("watcher closed")
+			}
+			return nil, err
+		case <-timer:
+			return sortedKeys(changed), nil
+		}
+	}
+}
+
+func sortedKeys(m map[string]bool) []string {
+	res := make([]string, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
@@ -1,18 +1,18 @@
 export default {
-  appName: '[[appName]]',
-  uiAppName: '[[uiAppName]]',
-  apiUrl: () => process.env.NODE_ENV === 'development' ? 'http://localhost:[[webPort]]' : '[[urlWithHttp]]',
-  wsUrl: () => process.env.NODE_ENV === 'development' ? 'ws://localhost:[[webPort]]' : 'wss://[[url]]',
+  appName: '[[slot:appName]]',
+  uiAppName: '[[slot:uiAppName]]',
+  apiUrl: () => process.env.NODE_ENV === 'development' ? 'http://localhost:[[slot:webPort]]' : '[[slot:urlWithHttp]]',
+  wsUrl: () => process.env.NODE_ENV === 'development' ? 'ws://localhost:[[slot:webPort]]' : 'wss://[[slot:url]]',
   isEmailAuth: {
     firstName: true,
     lastName: true,
   },
-  logoSrc: '[[logoSrc]]',
-  dadataToken: '[[dadataToken]]',
+  logoSrc: '[[slot:logoSrc]]',
+  dadataToken: '[[slot:dadataToken]]',
   // yandexMetrikaId: 54433825,
   breadcrumbIcons: {
-    [[breadcrumbIcons]]
+    // codeGenerate slot:breadcrumbIcons
   },
-  [[telegramConfig]]
-  tablesForTask: [[codoGeneratedTablesForTask]],
+  // codeGenerate slot:pluginsConfig
+  tablesForTask: [[slot:tablesForTask]],
 }
@@ -15,7 +15,7 @@ const routes = [
   {path: '/task/:id', component: () => import(`../app/components/task/item.vue`), props: true},
   {path: '/taskType', component: () => import(`../app/components/taskType/index.vue`)},
   {path: '/taskType/:id', component: () => import(`../app/components/taskType/item.vue`), props: true},
-  // for codeGenerate ##routes_slot1
+  // codeGenerate slot:routes
 ]
 
 // Always leave this as last one
@@ -41,6 +41,12 @@ const CurrentUser = class {
   }
 
   getIsInLogingProcess = () => isInLogingProcess$
+
+  // доступ к полю документа по ролям (FldType.Roles). admin имеет доступ ко всем полям
+  isFldAccess = (...roles) => {
+    const userRoles = user$.getValue()?.role || []
+    return userRoles.includes('admin') || roles.some(r => userRoles.includes(r))
+  }
 }
 
 const loginProcess = (isTrue) => {
@@ -11,7 +11,7 @@ const routes = [
   {path: '/users', component: () => import(`../app/components/users/index.vue`)},
   {path: '/users/:id', component: () => import(`../app/components/users/item.vue`), props: true},
   {path: '/profile', component: () => import(`../app/components/currentUser/profile.vue`)},
-  // for codeGenerate ##routes_slot1
+  // codeGenerate slot:routes
   // Always leave this as last one,
   // but you can also remove it
   {